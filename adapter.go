@@ -0,0 +1,134 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// Adapter describes JSON API representation of values of a Go type which can't implement the Marshal interfaces,
+// e.g. structs from other packages. Each method receives the value being marshaled.
+//
+// Adapter example:
+//
+//	type UserAdapter struct{}
+//
+//	func(UserAdapter) GetType(interface{}) string {
+//	  return "users"
+//	}
+//
+//	func(UserAdapter) GetID(v interface{}) string {
+//	  return strconv.Itoa(v.(models.User).ID)
+//	}
+//
+//	func(UserAdapter) GetAttributes(v interface{}) interface{} {
+//	  u := v.(models.User)
+//	  return map[string]interface{}{"name": u.Name, "email": u.Email}
+//	}
+//
+//	func(UserAdapter) GetRelationships(v interface{}) map[string]interface{} {
+//	  return map[string]interface{}{
+//	    "company": jsonapi.ResourceObjectIdentifier{Type: "companies", ID: v.(models.User).CompanyID},
+//	  }
+//	}
+//
+//	func(UserAdapter) GetMeta(interface{}) interface{} {
+//	  return nil
+//	}
+//
+//	jsonapi.RegisterAdapter(models.User{}, UserAdapter{})
+type Adapter interface {
+	// GetType returns resource type.
+	GetType(v interface{}) string
+	// GetID returns resource ID.
+	GetID(v interface{}) string
+	// GetAttributes returns value marshaled as resource attributes, nil means no attributes.
+	GetAttributes(v interface{}) interface{}
+	// GetRelationships returns resource relationships like MarshalRelationships does, nil means no relationships.
+	GetRelationships(v interface{}) map[string]interface{}
+	// GetMeta returns value marshaled as resource meta, nil means no meta.
+	GetMeta(v interface{}) interface{}
+}
+
+var adapters = struct {
+	sync.RWMutex
+	byType map[reflect.Type]Adapter
+}{
+	byType: map[reflect.Type]Adapter{},
+}
+
+// RegisterAdapter registers adapter for values of the same Go type as sample,
+// such values are marshaled as primary data, relationships and included using the adapter.
+// Adapter takes precedence over extractor registered for the same type.
+func RegisterAdapter(sample interface{}, adapter Adapter) {
+	adapters.Lock()
+	defer adapters.Unlock()
+
+	adapters.byType[reflect.TypeOf(sample)] = adapter
+}
+
+func lookupAdapter(v interface{}) (Adapter, interface{}, bool) {
+	adapters.RLock()
+	defer adapters.RUnlock()
+
+	for _, candidate := range lookupCandidates(v) {
+		if adapter, ok := adapters.byType[reflect.TypeOf(candidate)]; ok {
+			return adapter, candidate, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+type adaptedResource struct {
+	value   interface{}
+	adapter Adapter
+}
+
+func (ar adaptedResource) GetID() string {
+	return ar.adapter.GetID(ar.value)
+}
+
+func (ar adaptedResource) GetType() string {
+	return ar.adapter.GetType(ar.value)
+}
+
+func (ar adaptedResource) GetRelationships() map[string]interface{} {
+	return ar.adapter.GetRelationships(ar.value)
+}
+
+func (ar adaptedResource) GetMeta() interface{} {
+	if meta := ar.adapter.GetMeta(ar.value); meta != nil {
+		return meta
+	}
+
+	return struct{}{}
+}
+
+func (ar adaptedResource) MarshalJSON() ([]byte, error) {
+	attributes := ar.adapter.GetAttributes(ar.value)
+	if attributes == nil {
+		return []byte("{}"), nil
+	}
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(attributes)
+
+	return buf.Bytes(), err
+}
+
+func (ar adaptedResource) unwrap() interface{} {
+	return ar.value
+}
+
+func (ar adaptedResource) rewrap(value interface{}) MarshalResourceIdentifier {
+	return adaptedResource{value: value, adapter: ar.adapter}
+}
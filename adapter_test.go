@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Member struct {
+	UID       int
+	FullName  string
+	Publisher string
+	Visits    int
+}
+
+type MemberAdapter struct{}
+
+func (MemberAdapter) GetType(interface{}) string {
+	return "members"
+}
+
+func (MemberAdapter) GetID(v interface{}) string {
+	return strconv.Itoa(v.(Member).UID)
+}
+
+func (MemberAdapter) GetAttributes(v interface{}) interface{} {
+	return map[string]interface{}{"name": v.(Member).FullName}
+}
+
+func (MemberAdapter) GetRelationships(v interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"publisher": ResourceObjectIdentifier{Type: "publishers", ID: v.(Member).Publisher},
+	}
+}
+
+func (MemberAdapter) GetMeta(v interface{}) interface{} {
+	if visits := v.(Member).Visits; visits > 0 {
+		return map[string]int{"visits": visits}
+	}
+
+	return nil
+}
+
+type MembersView struct {
+	Members []Member
+}
+
+func (v MembersView) GetData() interface{} {
+	return v.Members
+}
+
+var _ = Describe("RegisterAdapter", func() {
+
+	BeforeEach(func() {
+		RegisterAdapter(Member{}, MemberAdapter{})
+	})
+
+	It("marshals values of registered types", func() {
+		view := MembersView{
+			Members: []Member{
+				{UID: 1, FullName: "Caleb Doxsey", Publisher: "apress", Visits: 3},
+				{UID: 2, FullName: "Alan A. A. Donovan", Publisher: "addison-wesley"},
+			},
+		}
+
+		result, err := Marshal(view)
+
+		expected := `
+      {
+        "data": [
+          {
+            "type": "members",
+            "id": "1",
+            "attributes": {
+              "name": "Caleb Doxsey"
+            },
+            "meta": {
+              "visits": 3
+            },
+            "relationships": {
+              "publisher": {
+                "data": { "type": "publishers", "id": "apress" }
+              }
+            }
+          },
+          {
+            "type": "members",
+            "id": "2",
+            "attributes": {
+              "name": "Alan A. A. Donovan"
+            },
+            "relationships": {
+              "publisher": {
+                "data": { "type": "publishers", "id": "addison-wesley" }
+              }
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,152 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// transformAttributes decodes attributes object into members, lets fn change them and encodes the result back.
+func transformAttributes(attributes json.RawMessage, fn func(members map[string]json.RawMessage) error) (json.RawMessage, error) {
+	members := map[string]json.RawMessage{}
+
+	if len(attributes) > 0 {
+		if err := json.Unmarshal(attributes, &members); err != nil {
+			return attributes, err
+		}
+	}
+
+	if err := fn(members); err != nil {
+		return attributes, err
+	}
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(members)
+
+	return buf.Bytes(), err
+}
+
+var (
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+	attributelessCache sync.Map
+)
+
+// isEmptyObject tells raw JSON is an object without members, regardless of its whitespace.
+func isEmptyObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+
+	return len(trimmed) >= 2 && trimmed[0] == '{' && trimmed[len(trimmed)-1] == '}' && len(bytes.TrimSpace(trimmed[1:len(trimmed)-1])) == 0
+}
+
+// hasAttributes tells the resource attributes are encoded: they are given by MarshalAttributes or MarshalRawAttributes,
+// or the resource isn't attributeless.
+func hasAttributes(mri MarshalResourceIdentifier) bool {
+	switch mri.(type) {
+	case MarshalAttributes, MarshalRawAttributes:
+		return true
+	}
+
+	return !attributeless(mri)
+}
+
+// attributeless tells the resource has no attributes regardless of its value: it is a struct without JSON members
+// and custom JSON marshaling, so its attributes encoding can be skipped.
+func attributeless(v interface{}) bool {
+	typ := reflect.TypeOf(v)
+
+	if cached, ok := attributelessCache.Load(typ); ok {
+		return cached.(bool)
+	}
+
+	result := isAttributelessType(typ)
+
+	attributelessCache.Store(typ, result)
+
+	return result
+}
+
+func isAttributelessType(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || typ.Implements(marshalerType) || reflect.PtrTo(typ).Implements(marshalerType) {
+		return false
+	}
+
+	if len(jsonFields(typ)) > 0 {
+		return false
+	}
+
+	// embedded fields of other kinds than struct are JSON members, which jsonFields doesn't report
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if !field.Anonymous || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() != reflect.Struct {
+			if len(field.PkgPath) == 0 {
+				return false
+			}
+
+			continue
+		}
+
+		if !isAttributelessType(fieldType) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// omitZero removes attributes of the zero valued fields of v, see Options.OmitZero.
+func omitZero(attributes json.RawMessage, v interface{}) (json.RawMessage, error) {
+	value := reflect.ValueOf(v)
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return attributes, nil
+		}
+
+		value = value.Elem()
+	}
+
+	var zero []string
+
+	for name, field := range jsonFields(value.Type()) {
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if !ok || fieldValue.IsZero() {
+			zero = append(zero, name)
+		}
+	}
+
+	if len(zero) == 0 {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for _, name := range zero {
+			delete(members, name)
+		}
+
+		return nil
+	})
+}
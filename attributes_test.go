@@ -0,0 +1,329 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Record struct {
+	ID     string
+	Fields map[string]interface{}
+}
+
+func (r Record) GetID() string {
+	return r.ID
+}
+
+func (r Record) GetType() string {
+	return "records"
+}
+
+func (r Record) GetAttributes() map[string]interface{} {
+	return r.Fields
+}
+
+func (r *Record) SetID(id string) error {
+	r.ID = id
+	return nil
+}
+
+func (r *Record) SetType(string) error {
+	return nil
+}
+
+func (r *Record) SetAttributes(attributes map[string]interface{}) error {
+	if _, ok := attributes["id"]; ok {
+		return errors.New("id attribute is reserved")
+	}
+
+	r.Fields = attributes
+	return nil
+}
+
+type RecordView struct {
+	Record Record
+}
+
+func (v RecordView) GetData() interface{} {
+	return v.Record
+}
+
+func (v *RecordView) SetData(to func(target interface{}) error) error {
+	return to(&v.Record)
+}
+
+var _ = Describe("Map-based attributes", func() {
+
+	It("marshals attributes given by GetAttributes", func() {
+		view := RecordView{Record: Record{ID: "1", Fields: map[string]interface{}{"color": "red", "size": 42}}}
+
+		result, err := Marshal(view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Data.One.Attributes).Should(MatchJSON(`{"color": "red", "size": 42}`))
+	})
+
+	It("omits empty attributes", func() {
+		result, err := Marshal(RecordView{Record: Record{ID: "1"}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "records", "id": "1"}}`))
+	})
+
+	It("unmarshals attributes with SetAttributes", func() {
+		view := RecordView{}
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "records", "id": "1", "attributes": {"color": "red", "tags": ["a"]}}}`), &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Record.ID).Should(Equal("1"))
+		Ω(view.Record.Fields).Should(Equal(map[string]interface{}{"color": "red", "tags": []interface{}{"a"}}))
+	})
+
+	It("returns SetAttributes error", func() {
+		view := RecordView{}
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "records", "id": "1", "attributes": {"id": "2"}}}`), &view)
+
+		Ω(err).Should(MatchError("id attribute is reserved"))
+	})
+})
+
+type Setting struct {
+	ID    string
+	Value json.RawMessage
+}
+
+func (s Setting) GetID() string {
+	return s.ID
+}
+
+func (s Setting) GetType() string {
+	return "settings"
+}
+
+func (s Setting) GetRawAttributes() json.RawMessage {
+	return s.Value
+}
+
+func (s *Setting) SetID(id string) error {
+	s.ID = id
+	return nil
+}
+
+func (s *Setting) SetType(string) error {
+	return nil
+}
+
+func (s *Setting) SetRawAttributes(attributes json.RawMessage) error {
+	s.Value = append(json.RawMessage{}, attributes...)
+	return nil
+}
+
+type SettingView struct {
+	Setting Setting
+}
+
+func (v SettingView) GetData() interface{} {
+	return v.Setting
+}
+
+func (v *SettingView) SetData(to func(target interface{}) error) error {
+	return to(&v.Setting)
+}
+
+var _ = Describe("Raw attributes", func() {
+
+	It("marshals raw attributes as is", func() {
+		view := SettingView{Setting: Setting{ID: "1", Value: json.RawMessage(`{"theme": "dark", "limits": {"daily": 10}}`)}}
+
+		result, err := Marshal(view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "settings", "id": "1", "attributes": {"theme": "dark", "limits": {"daily": 10}}}}`))
+	})
+
+	It("omits empty raw attributes", func() {
+		for _, raw := range []string{"", "null", "{}"} {
+			result, err := Marshal(SettingView{Setting: Setting{ID: "1", Value: json.RawMessage(raw)}})
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result).Should(MatchJSON(`{"data": {"type": "settings", "id": "1"}}`), raw)
+		}
+	})
+
+	It("fails on raw attributes which aren't JSON object", func() {
+		for _, raw := range []string{`[1]`, `"a"`, `{"a":`} {
+			_, err := Marshal(SettingView{Setting: Setting{ID: "1", Value: json.RawMessage(raw)}})
+
+			Ω(err).Should(MatchError(ErrInvalidRawAttributes), raw)
+		}
+	})
+
+	It("unmarshals raw attributes", func() {
+		view := SettingView{}
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "settings", "id": "1", "attributes": {"theme": "dark"}}}`), &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Setting.ID).Should(Equal("1"))
+		Ω(view.Setting.Value).Should(MatchJSON(`{"theme": "dark"}`))
+	})
+})
+
+type Draft struct {
+	ID    string `json:"-"`
+	Title string `json:"title,omitempty"`
+	Notes string `json:"notes,omitempty"`
+}
+
+func (d Draft) GetID() string {
+	return d.ID
+}
+
+func (d Draft) GetType() string {
+	return "drafts"
+}
+
+type DraftsView []Draft
+
+func (v DraftsView) GetData() interface{} {
+	return []Draft(v)
+}
+
+var _ = Describe("Empty attributes", func() {
+
+	view := DraftsView{{ID: "1"}, {ID: "2", Title: "Outline"}}
+
+	It("drops attributes when all of them are omitted", func() {
+		result, err := Marshal(view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": [
+			{"type": "drafts", "id": "1"},
+			{"type": "drafts", "id": "2", "attributes": {"title": "Outline"}}
+		]}`))
+	})
+
+	It("drops attributes when sparse fieldset omits all of them", func() {
+		result, err := Marshal(view, WithFields(map[string][]string{"drafts": {"notes"}}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": [{"type": "drafts", "id": "1"}, {"type": "drafts", "id": "2"}]}`))
+	})
+
+	It("emits empty attributes object when it is forced", func() {
+		result, err := Marshal(view, WithEmptyAttributes(), WithFields(map[string][]string{"drafts": {"notes"}}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": [
+			{"type": "drafts", "id": "1", "attributes": {}},
+			{"type": "drafts", "id": "2", "attributes": {}}
+		]}`))
+	})
+
+	It("emits empty attributes and relationships objects when they are forced", func() {
+		result, err := Marshal(view, WithEmptyAttributes(), WithEmptyRelationships(), WithFields(map[string][]string{"drafts": {"notes"}}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": [
+			{"type": "drafts", "id": "1", "attributes": {}, "relationships": {}},
+			{"type": "drafts", "id": "2", "attributes": {}, "relationships": {}}
+		]}`))
+	})
+
+	It("keeps present relationships when empty ones are forced", func() {
+		result, err := Marshal(Stadium{ID: "1", HomeTeamID: "7"}, WithEmptyRelationships())
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {
+			"type": "stadiums",
+			"id": "1",
+			"attributes": {"name": "", "seat_count": 0},
+			"relationships": {"home_team": {"data": {"type": "teams", "id": "7"}}}
+		}}`))
+	})
+})
+
+type Reading struct {
+	ID    string      `json:"-"`
+	Value interface{} `json:"value"`
+}
+
+func (r *Reading) SetID(id string) error {
+	r.ID = id
+	return nil
+}
+
+func (r *Reading) SetType(string) error {
+	return nil
+}
+
+func (r *Reading) SetData(to func(target interface{}) error) error {
+	return to(r)
+}
+
+var _ = Describe("Attribute numbers", func() {
+	payload := []byte(`{"data": {"type": "readings", "id": "1", "attributes": {"value": 9007199254740993}}}`)
+
+	It("decodes numbers as float64 by default", func() {
+		var reading Reading
+
+		_, err := Unmarshal(payload, &reading)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(reading.Value).Should(BeAssignableToTypeOf(float64(0)))
+	})
+
+	It("decodes numbers of interface{} fields as json.Number with UseNumber", func() {
+		var reading Reading
+
+		_, err := Unmarshal(payload, &reading, WithUseNumber())
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(reading.Value).Should(Equal(json.Number("9007199254740993")))
+	})
+
+	It("decodes numbers of attributes maps as json.Number with UseNumber", func() {
+		view := RecordView{}
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "records", "id": "1", "attributes": {"price": 12.3456789012345678}}}`), &view, WithUseNumber())
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Record.Fields).Should(Equal(map[string]interface{}{"price": json.Number("12.3456789012345678")}))
+	})
+})
+
+var _ = Describe("Zero attributes", func() {
+	It("keeps zero valued attributes by default", func() {
+		result, err := Marshal(Stadium{ID: "1", Name: "Arena"})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "stadiums", "id": "1", "attributes": {"name": "Arena", "seat_count": 0}, "relationships": {"home_team": {"data": null}}}}`))
+	})
+
+	It("drops zero valued attributes regardless of the json tags", func() {
+		result, err := Marshal(Stadium{ID: "1", Name: "Arena"}, WithOmitZero())
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "stadiums", "id": "1", "attributes": {"name": "Arena"}, "relationships": {"home_team": {"data": null}}}}`))
+	})
+
+	It("omits attributes object when all the attributes are zero", func() {
+		result, err := Marshal(Stadium{ID: "1"}, WithOmitZero())
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "stadiums", "id": "1", "relationships": {"home_team": {"data": null}}}}`))
+	})
+})
@@ -0,0 +1,174 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidBlob returned when Blob attribute isn't a base64 string, the error is wrapped with the details.
+var ErrInvalidBlob = errors.New("jsonapi: invalid blob")
+
+var blobType = reflect.TypeOf(Blob{})
+
+// Blob is binary attribute value marshaled as standard base64 string, nil Blob is marshaled as null.
+//
+// The decoded size of Blob attributes may be limited by `jsonapi:"maxsize=1048576"` field tag or Options.MaxBlobSize,
+// the limit is checked before decoding and Unmarshal returns ValidationError with 413 error objects for the exceeding attributes.
+// Large content should rather be uploaded separately and referenced by URL.
+type Blob []byte
+
+// Bytes is Blob under the name of the small binary attributes it is usually used for, e.g. thumbnails or signatures,
+// it is base64 encoded and size limited the same way.
+type Bytes = Blob
+
+// Size returns number of the blob bytes.
+func (b Blob) Size() int {
+	return len(b)
+}
+
+// Reader returns reader of the blob bytes, e.g. to stream them into storage.
+func (b Blob) Reader() io.Reader {
+	return bytes.NewReader(b)
+}
+
+// MarshalJSON marshals the blob as base64 string.
+func (b Blob) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+
+	buf := make([]byte, 0, base64.StdEncoding.EncodedLen(len(b))+2)
+	buf = append(buf, '"')
+	buf = append(buf, base64.StdEncoding.EncodeToString(b)...)
+
+	return append(buf, '"'), nil
+}
+
+// UnmarshalJSON unmarshals the blob from base64 string, null gives nil Blob.
+func (b *Blob) UnmarshalJSON(payload []byte) error {
+	if bytes.Equal(payload, []byte("null")) {
+		*b = nil
+		return nil
+	}
+
+	var encoded string
+
+	if err := json.Unmarshal(payload, &encoded); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBlob, err)
+	}
+
+	decoded := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+
+	n, err := base64.StdEncoding.Decode(decoded, []byte(encoded))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBlob, err)
+	}
+
+	*b = decoded[:n]
+
+	return nil
+}
+
+// blobLimits returns maximum decoded sizes of the type Blob attributes, given by field tags and the default limit.
+func blobLimits(typ reflect.Type, limit int64) (map[string]int64, error) {
+	limits := map[string]int64{}
+
+	for name, field := range jsonFields(typ) {
+		fieldType := field.field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType != blobType {
+			continue
+		}
+
+		fieldLimit := limit
+
+		if maxsize, ok := field.tag["maxsize"]; ok {
+			parsed, err := strconv.ParseInt(maxsize, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("jsonapi: invalid maxsize of %q: %w", name, err)
+			}
+
+			fieldLimit = parsed
+		}
+
+		if fieldLimit > 0 {
+			limits[name] = fieldLimit
+		}
+	}
+
+	return limits, nil
+}
+
+// validateBlobs checks decoded sizes of Blob attributes don't exceed their limits, without decoding them,
+// and returns 413 error objects for the violations.
+func validateBlobs(attributes json.RawMessage, typ reflect.Type, pointer string, opts *Options) ([]*ErrorObject, error) {
+	limits, err := blobLimits(typ, opts.MaxBlobSize)
+	if err != nil || len(limits) == 0 || len(attributes) == 0 {
+		return nil, err
+	}
+
+	members := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(attributes, &members); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(limits))
+
+	for name := range limits {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var errs []*ErrorObject
+
+	for _, name := range names {
+		raw, ok := members[name]
+		if !ok || bytes.Equal(raw, []byte("null")) {
+			continue
+		}
+
+		var encoded string
+
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBlob, err)
+		}
+
+		size := int64(base64.StdEncoding.DecodedLen(len(encoded)) - strings.Count(encoded, "="))
+		if size <= limits[name] {
+			continue
+		}
+
+		errs = append(errs, &ErrorObject{
+			Status: strconv.Itoa(http.StatusRequestEntityTooLarge),
+			Title:  "Attribute too large",
+			Detail: fmt.Sprintf("Attribute %q must not exceed %d bytes, upload the content separately and reference it by URL instead.", name, limits[name]),
+			Code:   "blob_too_large",
+			Source: ErrorObjectSource{Pointer: pointer + Pointer(name)},
+			Meta: map[string]interface{}{
+				"size":  size,
+				"limit": limits[name],
+			},
+		})
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Attachment struct {
+	ID        string `json:"-"`
+	Content   Blob   `json:"content"`
+	Thumbnail Blob   `json:"thumbnail,omitempty" jsonapi:"maxsize=4"`
+	Signature Bytes  `json:"signature,omitempty" jsonapi:"maxsize=2"`
+}
+
+func (a Attachment) GetID() string {
+	return a.ID
+}
+
+func (a Attachment) GetType() string {
+	return "attachments"
+}
+
+func (a *Attachment) SetID(id string) error {
+	a.ID = id
+	return nil
+}
+
+func (a *Attachment) SetType(string) error {
+	return nil
+}
+
+type AttachmentView struct {
+	Attachment Attachment
+}
+
+func (v AttachmentView) GetData() interface{} {
+	return v.Attachment
+}
+
+func (v *AttachmentView) SetData(to func(target interface{}) error) error {
+	return to(&v.Attachment)
+}
+
+var _ = Describe("Blob", func() {
+
+	It("marshals blob as base64 string", func() {
+		view := AttachmentView{Attachment: Attachment{ID: "1", Content: Blob("hello")}}
+
+		result, err := Marshal(view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Data.One.Attributes).Should(MatchJSON(`{"content": "aGVsbG8="}`))
+	})
+
+	It("unmarshals blob and streams its bytes", func() {
+		view := AttachmentView{}
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "attachments", "id": "1", "attributes": {"content": "aGVsbG8=", "thumbnail": null}}}`), &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Attachment.Content.Size()).Should(Equal(5))
+		Ω(view.Attachment.Thumbnail).Should(BeNil())
+
+		content, err := ioutil.ReadAll(view.Attachment.Content.Reader())
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(Equal("hello"))
+	})
+
+	It("fails on malformed base64", func() {
+		var b Blob
+
+		Ω(json.Unmarshal([]byte(`"not base64!"`), &b)).Should(MatchError(ErrInvalidBlob))
+		Ω(json.Unmarshal([]byte(`42`), &b)).Should(MatchError(ErrInvalidBlob))
+	})
+
+	It("rejects blobs exceeding the limits with guidance", func() {
+		view := AttachmentView{}
+		payload := []byte(`{"data": {"type": "attachments", "id": "1", "attributes": {"content": "aGVsbG8=", "thumbnail": "aGVsbG8="}}}`)
+
+		_, err := Unmarshal(payload, &view, WithMaxBlobSize(4))
+
+		var validationErr *ValidationError
+		Ω(errors.As(err, &validationErr)).Should(BeTrue())
+		Ω(validationErr.Errors).Should(HaveLen(2))
+		Ω(validationErr.Errors[0].Status).Should(Equal("413"))
+		Ω(validationErr.Errors[0].Code).Should(Equal("blob_too_large"))
+		Ω(validationErr.Errors[0].Source.Pointer).Should(Equal("/data/attributes/content"))
+		Ω(validationErr.Errors[0].Detail).Should(ContainSubstring("upload the content separately"))
+		Ω(validationErr.Errors[1].Source.Pointer).Should(Equal("/data/attributes/thumbnail"))
+	})
+
+	It("respects field limit over the default one", func() {
+		view := AttachmentView{}
+		payload := []byte(`{"data": {"type": "attachments", "id": "1", "attributes": {"content": "aGVsbG8=", "thumbnail": "aGVs"}}}`)
+
+		_, err := Unmarshal(payload, &view, WithMaxBlobSize(5))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(view.Attachment.Thumbnail)).Should(Equal("hel"))
+	})
+
+	It("round trips Bytes attributes as base64 strings with size limits", func() {
+		result, err := Marshal(AttachmentView{Attachment: Attachment{ID: "1", Signature: Bytes{0xca, 0xfe}}})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(result).Should(MatchJSON(`{"data": {"type": "attachments", "id": "1", "attributes": {"content": null, "signature": "yv4="}}}`))
+
+		view := AttachmentView{}
+
+		_, err = Unmarshal(result, &view)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Attachment.Signature).Should(Equal(Bytes{0xca, 0xfe}))
+
+		_, err = Unmarshal([]byte(`{"data": {"type": "attachments", "id": "1", "attributes": {"signature": "yv7K"}}}`), &view)
+
+		var validationErr *ValidationError
+		Ω(errors.As(err, &validationErr)).Should(BeTrue())
+		Ω(validationErr.Errors[0].Source.Pointer).Should(Equal("/data/attributes/signature"))
+	})
+})
@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+// DocumentBuilder constructs JSON API document programmatically, when there is no Go struct describing it,
+// e.g. in proxies, gateways and test fixtures. The built document is serialized with json.Marshal.
+//
+// DocumentBuilder example:
+//
+//	doc, err := jsonapi.NewDocument().
+//	  SetOne(book).
+//	  AddIncluded(author).
+//	  SetMeta(map[string]interface{}{"total": 1}).
+//	  Build()
+//
+//	payload, err := json.Marshal(doc)
+type DocumentBuilder struct {
+	doc *Document
+	err error
+}
+
+// NewDocument creates DocumentBuilder of an empty document.
+func NewDocument() *DocumentBuilder {
+	return &DocumentBuilder{doc: &Document{}}
+}
+
+// SetOne sets single resource object as the document primary data, nil resource object is marshaled as null.
+func (b *DocumentBuilder) SetOne(ro *ResourceObject) *DocumentBuilder {
+	b.doc.SetOne(ro)
+
+	return b
+}
+
+// SetMany sets resource objects collection as the document primary data, it is marshaled as empty array when there are none.
+func (b *DocumentBuilder) SetMany(resources ...*ResourceObject) *DocumentBuilder {
+	b.doc.SetMany(resources...)
+
+	return b
+}
+
+// AddIncluded adds resource objects to the document included, see Document.AddIncluded.
+func (b *DocumentBuilder) AddIncluded(resources ...*ResourceObject) *DocumentBuilder {
+	b.doc.AddIncluded(resources...)
+
+	return b
+}
+
+// AddErrors adds error objects to the document errors.
+func (b *DocumentBuilder) AddErrors(errs ...*ErrorObject) *DocumentBuilder {
+	for _, e := range errs {
+		b.doc.AddError(e)
+	}
+
+	return b
+}
+
+// SetMeta sets the document meta, the error of its marshaling is returned by Build.
+func (b *DocumentBuilder) SetMeta(meta interface{}) *DocumentBuilder {
+	if err := b.doc.SetMeta(meta); err != nil {
+		b.fail(err)
+	}
+
+	return b
+}
+
+// SetLink sets the document link by name, e.g. "self".
+func (b *DocumentBuilder) SetLink(name string, link *Link) *DocumentBuilder {
+	if b.doc.Links == nil {
+		b.doc.Links = Links{}
+	}
+
+	b.doc.Links[name] = link
+
+	return b
+}
+
+// Build returns the built document, or the first error occurred while building it.
+// A document containing both data and errors is rejected with ErrDataAndErrors.
+func (b *DocumentBuilder) Build() (*Document, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if b.doc.Data != nil && len(b.doc.Errors) > 0 {
+		return nil, ErrDataAndErrors
+	}
+
+	return b.doc, nil
+}
+
+func (b *DocumentBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
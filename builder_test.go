@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("DocumentBuilder", func() {
+
+	book := &ResourceObject{
+		ResourceObjectIdentifier: ResourceObjectIdentifier{Type: "books", ID: "1"},
+		Attributes:               json.RawMessage(`{"title":"Go"}`),
+	}
+
+	author := &ResourceObject{
+		ResourceObjectIdentifier: ResourceObjectIdentifier{Type: "authors", ID: "1"},
+		Attributes:               json.RawMessage(`{"name":"Rob"}`),
+	}
+
+	It("builds document with single resource object", func() {
+		doc, err := NewDocument().
+			SetOne(book).
+			AddIncluded(author).
+			SetMeta(map[string]interface{}{"total": 1}).
+			SetLink("self", &Link{Href: "/books/1"}).
+			Build()
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(doc)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		expected := `
+      {
+        "data": {"type": "books", "id": "1", "attributes": {"title": "Go"}},
+        "included": [{"type": "authors", "id": "1", "attributes": {"name": "Rob"}}],
+        "meta": {"total": 1},
+        "links": {"self": "/books/1"}
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+	})
+
+	It("builds document with empty collection", func() {
+		doc, err := NewDocument().SetMany().Build()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(doc)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": []}`))
+	})
+
+	It("builds document with null data", func() {
+		doc, err := NewDocument().SetOne(nil).Build()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(doc)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": null}`))
+	})
+
+	It("returns meta marshaling error", func() {
+		_, err := NewDocument().SetMeta(func() {}).Build()
+
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("rejects document with both data and errors", func() {
+		_, err := NewDocument().SetOne(book).AddErrors(&ErrorObject{Title: "Oops"}).Build()
+
+		Ω(err).Should(Equal(ErrDataAndErrors))
+	})
+})
@@ -0,0 +1,181 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheMeta is the resource meta member carrying cache hints.
+const CacheMeta = "cache"
+
+// CacheHints describes how long a resource may be cached and which keys purge it from caches.
+type CacheHints struct {
+	// MaxAge how long the resource may be cached, it is rounded down to seconds.
+	MaxAge time.Duration
+	// SurrogateKeys keys used to purge the resource from CDN caches.
+	SurrogateKeys []string
+	// Private tells the resource must not be stored by shared caches.
+	Private bool
+}
+
+// MarshalCacheHints interface should be implemented to be able emit resource cache hints
+// as resource meta, e.g. "meta": {"cache": {"max_age": 60, "surrogate_keys": ["books"]}}.
+//
+// GetCacheHints example:
+//
+//	func(s SomeStruct) GetCacheHints() jsonapi.CacheHints {
+//	  return jsonapi.CacheHints{
+//	    MaxAge:        time.Minute,
+//	    SurrogateKeys: []string{"books", "books:" + s.ID},
+//	  }
+//	}
+type MarshalCacheHints interface {
+	GetCacheHints() CacheHints
+}
+
+type cacheMeta struct {
+	MaxAge        int      `json:"max_age"`
+	SurrogateKeys []string `json:"surrogate_keys,omitempty"`
+	Private       bool     `json:"private,omitempty"`
+}
+
+func marshalCacheHints(meta json.RawMessage, mc MarshalCacheHints) (json.RawMessage, error) {
+	hints := mc.GetCacheHints()
+
+	return transformAttributes(meta, func(members map[string]json.RawMessage) error {
+		raw, err := json.Marshal(cacheMeta{
+			MaxAge:        int(hints.MaxAge / time.Second),
+			SurrogateKeys: hints.SurrogateKeys,
+			Private:       hints.Private,
+		})
+		if err != nil {
+			return err
+		}
+
+		members[CacheMeta] = raw
+
+		return nil
+	})
+}
+
+// DocumentCacheHints combines cache hints of all resources in the document, primary data and included.
+// The shortest max age wins, surrogate keys are merged keeping their first occurrence order
+// and the document is private when any of its resources is private.
+// The second returned value is false when none of the resources carries cache hints.
+func DocumentCacheHints(doc *Document) (CacheHints, bool) {
+	var (
+		hints CacheHints
+		found bool
+	)
+
+	seen := map[string]bool{}
+
+	for _, ro := range documentResources(doc) {
+		if len(ro.Meta) == 0 {
+			continue
+		}
+
+		members := map[string]json.RawMessage{}
+
+		if err := json.Unmarshal(ro.Meta, &members); err != nil {
+			continue
+		}
+
+		raw, ok := members[CacheMeta]
+		if !ok {
+			continue
+		}
+
+		meta := cacheMeta{}
+
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			continue
+		}
+
+		maxAge := time.Duration(meta.MaxAge) * time.Second
+
+		if !found || maxAge < hints.MaxAge {
+			hints.MaxAge = maxAge
+		}
+
+		hints.Private = hints.Private || meta.Private
+
+		for _, key := range meta.SurrogateKeys {
+			if !seen[key] {
+				seen[key] = true
+				hints.SurrogateKeys = append(hints.SurrogateKeys, key)
+			}
+		}
+
+		found = true
+	}
+
+	return hints, found
+}
+
+// SetCacheHeaders sets Cache-Control and Surrogate-Key response headers derived from the document cache hints,
+// the headers are left untouched when the document carries no cache hints.
+func SetCacheHeaders(w http.ResponseWriter, doc *Document) {
+	hints, ok := DocumentCacheHints(doc)
+	if !ok {
+		return
+	}
+
+	control := "max-age=" + strconv.Itoa(int(hints.MaxAge/time.Second))
+
+	if hints.Private {
+		control = "private, " + control
+	}
+
+	w.Header().Set("Cache-Control", control)
+
+	if len(hints.SurrogateKeys) > 0 {
+		w.Header().Set("Surrogate-Key", strings.Join(hints.SurrogateKeys, " "))
+	}
+}
+
+// SurrogateKeys returns sorted unique "type:id" keys of all resources in the document, primary data and included,
+// to drive CDN purging and cache invalidation from serialized responses. Resources without ID are skipped.
+func SurrogateKeys(doc *Document) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+
+	for _, ro := range documentResources(doc) {
+		if len(ro.ID) == 0 {
+			continue
+		}
+
+		key := ro.Type + ":" + ro.ID
+
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func documentResources(doc *Document) []*ResourceObject {
+	var resources []*ResourceObject
+
+	if doc.Data != nil {
+		if doc.Data.One != nil {
+			resources = append(resources, doc.Data.One)
+		}
+
+		resources = append(resources, doc.Data.Many...)
+	}
+
+	return append(resources, doc.Included...)
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type CachedBook struct {
+	BookWithMeta
+	MaxAge  time.Duration `json:"-"`
+	Private bool          `json:"-"`
+}
+
+func (b CachedBook) GetCacheHints() CacheHints {
+	return CacheHints{
+		MaxAge:        b.MaxAge,
+		SurrogateKeys: []string{"books", "books:" + b.ID},
+		Private:       b.Private,
+	}
+}
+
+type CachedBooksView struct {
+	Books []CachedBook
+}
+
+func (v CachedBooksView) GetData() interface{} {
+	return v.Books
+}
+
+var _ = Describe("Cache hints", func() {
+
+	view := CachedBooksView{
+		Books: []CachedBook{
+			{
+				BookWithMeta: BookWithMeta{
+					Book: Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+					Meta: BookMeta{Sold: 10},
+				},
+				MaxAge: time.Minute,
+			},
+			{
+				BookWithMeta: BookWithMeta{
+					Book: Book{ID: "2", Title: "Learning Go", Year: "2021", Type: "books"},
+				},
+				MaxAge: 30 * time.Second,
+			},
+		},
+	}
+
+	It("marshals cache hints into resource meta", func() {
+		result, err := Marshal(view)
+
+		expected := `
+      {
+        "data": [
+          {
+            "type": "books",
+            "id": "1",
+            "attributes": {
+              "title": "Go in Action",
+              "year": "2015"
+            },
+            "meta": {
+              "sold": 10,
+              "cache": { "max_age": 60, "surrogate_keys": ["books", "books:1"] }
+            }
+          },
+          {
+            "type": "books",
+            "id": "2",
+            "attributes": {
+              "title": "Learning Go",
+              "year": "2021"
+            },
+            "meta": {
+              "cache": { "max_age": 30, "surrogate_keys": ["books", "books:2"] }
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("sets cache headers derived from all resources", func() {
+		data, err := Marshal(view)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc, err := Unmarshal(data, &BooksView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		w := httptest.NewRecorder()
+		SetCacheHeaders(w, doc)
+
+		Ω(w.Header().Get("Cache-Control")).Should(Equal("max-age=30"))
+		Ω(w.Header().Get("Surrogate-Key")).Should(Equal("books books:1 books:2"))
+	})
+
+	It("marks cache control private when any resource is private", func() {
+		private := CachedBooksView{Books: []CachedBook{view.Books[0], view.Books[1]}}
+		private.Books[1].Private = true
+
+		data, err := Marshal(private)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc, err := Unmarshal(data, &BooksView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		hints, ok := DocumentCacheHints(doc)
+
+		Ω(ok).Should(BeTrue())
+		Ω(hints.Private).Should(BeTrue())
+	})
+
+	It("leaves headers untouched without cache hints", func() {
+		w := httptest.NewRecorder()
+		SetCacheHeaders(w, &Document{})
+
+		Ω(w.Header()).ShouldNot(HaveKey("Cache-Control"))
+	})
+})
+
+var _ = Describe("SurrogateKeys", func() {
+
+	It("returns keys of primary and included resources", func() {
+		view := BooksWithReadersIncludedView{
+			BooksWithReadersView: BooksWithReadersView{
+				Books: []BookWithReaders{
+					{
+						Book:    Book{ID: "2", Title: "Go in Action", Year: "2015", Type: "books"},
+						Readers: Readers{{ID: "1", Name: "Fred"}},
+					},
+					{
+						Book:    Book{ID: "1", Title: "Learning Go", Year: "2021", Type: "books"},
+						Readers: Readers{{ID: "1", Name: "Fred"}},
+					},
+				},
+			},
+		}
+
+		data, err := Marshal(view)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc, err := Unmarshal(data, &BooksView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(SurrogateKeys(doc)).Should(Equal([]string{"books:1", "books:2", "people:1"}))
+	})
+
+	It("returns empty keys of empty document", func() {
+		Ω(SurrogateKeys(&Document{})).Should(BeEmpty())
+	})
+})
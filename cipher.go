@@ -0,0 +1,101 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Cipher interface should be implemented to encrypt designated attributes, e.g. PII, at the serialization boundary.
+// Attributes are designated by `jsonapi:"encrypted"` field tag or Options.Encrypted.
+// Encrypt receives JSON encoded attribute value, the ciphertext is marshaled as base64 encoded string.
+// Decrypt receives the decoded ciphertext and returns JSON encoded attribute value.
+//
+// Cipher example:
+//
+//	type Email struct {
+//	  ID    string `json:"-"`
+//	  Email string `json:"email" jsonapi:"encrypted"`
+//	}
+//
+//	payload, err := jsonapi.MarshalWithOptions(view, jsonapi.Options{Cipher: aesCipher})
+type Cipher interface {
+	Encrypt(resourceType, attribute string, plaintext []byte) ([]byte, error)
+	Decrypt(resourceType, attribute string, ciphertext []byte) ([]byte, error)
+}
+
+func encryptedAttributes(resourceType string, typ reflect.Type, opts *Options) []string {
+	return append(taggedAttributes(typ, "encrypted"), opts.Encrypted[resourceType]...)
+}
+
+func encryptAttributes(resourceType string, attributes json.RawMessage, typ reflect.Type, opts *Options) (json.RawMessage, error) {
+	names := encryptedAttributes(resourceType, typ, opts)
+	if len(names) == 0 || bytes.Equal(attributes, []byte("{}\n")) {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for _, name := range names {
+			value, ok := members[name]
+			if !ok || bytes.Equal(value, []byte("null")) {
+				continue
+			}
+
+			ciphertext, err := opts.Cipher.Encrypt(resourceType, name, value)
+			if err != nil {
+				return fmt.Errorf("jsonapi: encrypt %q attribute: %w", name, err)
+			}
+
+			encoded, err := json.Marshal(base64.StdEncoding.EncodeToString(ciphertext))
+			if err != nil {
+				return err
+			}
+
+			members[name] = encoded
+		}
+
+		return nil
+	})
+}
+
+func decryptAttributes(resourceType string, attributes json.RawMessage, typ reflect.Type, opts *Options) (json.RawMessage, error) {
+	names := encryptedAttributes(resourceType, typ, opts)
+	if len(names) == 0 || len(attributes) == 0 {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for _, name := range names {
+			value, ok := members[name]
+			if !ok || bytes.Equal(value, []byte("null")) {
+				continue
+			}
+
+			var encoded string
+
+			if err := json.Unmarshal(value, &encoded); err != nil {
+				return fmt.Errorf("jsonapi: decrypt %q attribute: %w", name, err)
+			}
+
+			ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return fmt.Errorf("jsonapi: decrypt %q attribute: %w", name, err)
+			}
+
+			plaintext, err := opts.Cipher.Decrypt(resourceType, name, ciphertext)
+			if err != nil {
+				return fmt.Errorf("jsonapi: decrypt %q attribute: %w", name, err)
+			}
+
+			members[name] = plaintext
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Subscriber struct {
+	ID    string `json:"-"`
+	Name  string `json:"name"`
+	Email string `json:"email" jsonapi:"encrypted"`
+	Phone string `json:"phone,omitempty"`
+}
+
+func (s Subscriber) GetID() string {
+	return s.ID
+}
+
+func (s Subscriber) GetType() string {
+	return "subscribers"
+}
+
+func (s *Subscriber) SetID(id string) error {
+	s.ID = id
+	return nil
+}
+
+func (s *Subscriber) SetType(string) error {
+	return nil
+}
+
+type SubscriberView struct {
+	Subscriber Subscriber
+}
+
+func (v SubscriberView) GetData() interface{} {
+	return v.Subscriber
+}
+
+func (v *SubscriberView) SetData(to func(target interface{}) error) error {
+	return to(&v.Subscriber)
+}
+
+type ReverseCipher struct{}
+
+func (ReverseCipher) reverse(b []byte) []byte {
+	reversed := make([]byte, len(b))
+
+	for i, c := range b {
+		reversed[len(b)-1-i] = c
+	}
+
+	return reversed
+}
+
+func (c ReverseCipher) Encrypt(_, _ string, plaintext []byte) ([]byte, error) {
+	return c.reverse(plaintext), nil
+}
+
+func (c ReverseCipher) Decrypt(_, _ string, ciphertext []byte) ([]byte, error) {
+	return c.reverse(ciphertext), nil
+}
+
+var _ = Describe("Cipher", func() {
+
+	opts := Options{
+		Cipher: ReverseCipher{},
+		Encrypted: map[string][]string{
+			"subscribers": {"phone"},
+		},
+	}
+
+	encrypted := func(plaintext string) string {
+		return base64.StdEncoding.EncodeToString(ReverseCipher{}.reverse([]byte(plaintext)))
+	}
+
+	It("encrypts tagged and configured attributes", func() {
+		view := SubscriberView{
+			Subscriber: Subscriber{ID: "1", Name: "Caleb", Email: "caleb@example.com", Phone: "555-0100"},
+		}
+
+		result, err := MarshalWithOptions(view, opts)
+
+		expected := `
+      {
+        "data": {
+          "type": "subscribers",
+          "id": "1",
+          "attributes": {
+            "name": "Caleb",
+            "email": "` + encrypted(`"caleb@example.com"`) + `",
+            "phone": "` + encrypted(`"555-0100"`) + `"
+          }
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("decrypts tagged and configured attributes", func() {
+		payload := []byte(`
+      {
+        "data": {
+          "type": "subscribers",
+          "id": "1",
+          "attributes": {
+            "name": "Caleb",
+            "email": "` + encrypted(`"caleb@example.com"`) + `",
+            "phone": "` + encrypted(`"555-0100"`) + `"
+          }
+        }
+      }
+    `)
+
+		result := SubscriberView{}
+
+		_, err := UnmarshalWithOptions(payload, &result, opts)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result.Subscriber).Should(Equal(Subscriber{ID: "1", Name: "Caleb", Email: "caleb@example.com", Phone: "555-0100"}))
+	})
+
+	It("returns error for malformed ciphertext", func() {
+		payload := []byte(`{ "data": { "type": "subscribers", "id": "1", "attributes": { "email": "%%%" } } }`)
+
+		_, err := UnmarshalWithOptions(payload, &SubscriberView{}, opts)
+
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ClientIDPolicy describes whether primary data of a resource creation request may include client-generated ID.
+//
+// ClientIDPolicy example:
+//
+//	policy := jsonapi.ClientIDPolicy{
+//	  Allowed: true,
+//	  Exists: func(roi jsonapi.ResourceObjectIdentifier) bool {
+//	    _, ok := books[roi.ID]
+//	    return ok
+//	  },
+//	}
+//
+//	doc, err := jsonapi.Unmarshal(payload, &book)
+//	...
+//	if errs := policy.Validate(doc); len(errs) > 0 {
+//	  // respond with errs
+//	}
+type ClientIDPolicy struct {
+	// Allowed permits client-generated IDs, otherwise resources with ID are rejected with 403 Forbidden.
+	Allowed bool
+	// Exists optional func reports whether resource already exists, such resources are rejected with 409 Conflict.
+	Exists func(ResourceObjectIdentifier) bool
+}
+
+// Validate checks document primary data against the policy and returns error objects for the violations.
+func (p ClientIDPolicy) Validate(doc *Document) []*ErrorObject {
+	var errs []*ErrorObject
+
+	if doc == nil || doc.Data == nil {
+		return errs
+	}
+
+	if one := doc.Data.One; one != nil {
+		if err := p.validate(one.ResourceObjectIdentifier, "/data/id"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i, one := range doc.Data.Many {
+		if err := p.validate(one.ResourceObjectIdentifier, CollectionPointer(i, "id")); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func (p ClientIDPolicy) validate(roi ResourceObjectIdentifier, pointer string) *ErrorObject {
+	if len(roi.ID) == 0 {
+		return nil
+	}
+
+	if !p.Allowed {
+		return &ErrorObject{
+			Status: strconv.Itoa(http.StatusForbidden),
+			Title:  "Client-generated ID is not allowed",
+			Detail: fmt.Sprintf("Resource of type %q can't be created with client-generated ID.", roi.Type),
+			Code:   "client_id_forbidden",
+			Source: ErrorObjectSource{Pointer: pointer},
+		}
+	}
+
+	if p.Exists != nil && p.Exists(roi) {
+		return &ErrorObject{
+			Status: strconv.Itoa(http.StatusConflict),
+			Title:  "Resource already exists",
+			Detail: fmt.Sprintf("Resource of type %q with ID %q already exists.", roi.Type, roi.ID),
+			Code:   "client_id_conflict",
+			Source: ErrorObjectSource{Pointer: pointer},
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("ClientIDPolicy", func() {
+
+	payload := []byte(`
+    {
+      "data": {
+        "type": "books",
+        "id": "1",
+        "attributes": {
+          "title": "An Introduction to Programming in Go"
+        }
+      }
+    }
+  `)
+
+	It("rejects client-generated ID when it is not allowed", func() {
+		doc, err := Unmarshal(payload, &BookView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		errs := ClientIDPolicy{}.Validate(doc)
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Status).Should(Equal("403"))
+		Ω(errs[0].Source.Pointer).Should(Equal("/data/id"))
+	})
+
+	It("rejects client-generated ID of existing resource", func() {
+		doc, err := Unmarshal(payload, &BookView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		policy := ClientIDPolicy{
+			Allowed: true,
+			Exists: func(roi ResourceObjectIdentifier) bool {
+				return roi.Type == "books" && roi.ID == "1"
+			},
+		}
+
+		errs := policy.Validate(doc)
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Status).Should(Equal("409"))
+		Ω(errs[0].Source.Pointer).Should(Equal("/data/id"))
+	})
+
+	It("accepts allowed client-generated ID", func() {
+		doc, err := Unmarshal(payload, &BookView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(ClientIDPolicy{Allowed: true}.Validate(doc)).Should(BeEmpty())
+	})
+
+	It("accepts resource without ID", func() {
+		doc, err := Unmarshal([]byte(`{ "data": { "type": "books" } }`), &BookView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(ClientIDPolicy{}.Validate(doc)).Should(BeEmpty())
+	})
+})
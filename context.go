@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+)
+
+type optionsKey struct{}
+
+// ContextWithOptions returns a copy of ctx carrying marshal options, e.g. requested fields and include paths
+// attached by HTTP middleware, which MarshalContext applies. Options already carried by ctx are kept,
+// the given ones are applied after them.
+//
+// ContextWithOptions example:
+//
+//	func Fields(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			query, _ := jsonapi.ParseQuery(r.URL.Query())
+//			ctx := jsonapi.ContextWithOptions(r.Context(), jsonapi.WithFields(query.Fields))
+//			next.ServeHTTP(w, r.WithContext(ctx))
+//		})
+//	}
+func ContextWithOptions(ctx context.Context, opts ...Option) context.Context {
+	carried := OptionsFromContext(ctx)
+
+	combined := make([]Option, 0, len(carried)+len(opts))
+	combined = append(combined, carried...)
+	combined = append(combined, opts...)
+
+	return context.WithValue(ctx, optionsKey{}, combined)
+}
+
+// OptionsFromContext returns marshal options attached to ctx by ContextWithOptions.
+func OptionsFromContext(ctx context.Context) []Option {
+	opts, _ := ctx.Value(optionsKey{}).([]Option)
+
+	return opts
+}
+
+// MarshalContext serialize Go struct into []byte JSON API document like Marshal does,
+// honoring the options carried by ctx. Options.Context is set to ctx, Options.Locale and Options.RequestID
+// are taken from WithLocale and WithRequestID, then ContextWithOptions options and the given ones are applied,
+// the latter take precedence.
+func MarshalContext(ctx context.Context, payload interface{}, opts ...Option) ([]byte, error) {
+	options := contextOptions(ctx, opts)
+
+	return marshal(payload, &options, "", "")
+}
+
+func contextOptions(ctx context.Context, opts []Option) Options {
+	options := Options{Context: ctx}
+
+	if locale, ok := LocaleFromContext(ctx); ok {
+		options.Locale = locale
+	}
+
+	if id, ok := RequestIDFromContext(ctx); ok {
+		options.RequestID = id
+	}
+
+	for _, opt := range OptionsFromContext(ctx) {
+		opt(&options)
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("MarshalContext", func() {
+	stadium := Stadium{ID: "1", Name: "Arena", SeatCount: 8000}
+
+	It("marshals like Marshal without options in context", func() {
+		result, err := MarshalContext(context.Background(), stadium)
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		expected, err := Marshal(stadium)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(expected))
+	})
+
+	It("honors options attached to context", func() {
+		ctx := ContextWithOptions(context.Background(), WithFields(map[string][]string{"stadiums": {"name"}}))
+
+		result, err := MarshalContext(ctx, stadium)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "stadiums", "id": "1", "attributes": {"name": "Arena"}}}`))
+	})
+
+	It("keeps options already attached to context", func() {
+		ctx := ContextWithOptions(context.Background(), WithFields(map[string][]string{"stadiums": {"name"}}))
+		ctx = ContextWithOptions(ctx, WithNaming(KebabCase))
+
+		Ω(OptionsFromContext(ctx)).Should(HaveLen(2))
+
+		result, err := MarshalContext(ctx, stadium)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "stadiums", "id": "1", "attributes": {"name": "Arena"}}}`))
+	})
+
+	It("applies given options after the context ones", func() {
+		ctx := ContextWithOptions(context.Background(), WithFields(map[string][]string{"stadiums": {"name"}}))
+
+		result, err := MarshalContext(ctx, stadium, WithFields(map[string][]string{"stadiums": {"seat_count"}}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "stadiums", "id": "1", "attributes": {"seat_count": 8000}}}`))
+	})
+
+	It("stamps error objects with request ID from context", func() {
+		ctx := WithRequestID(context.Background(), "f3b4c1")
+
+		result, err := MarshalContext(ctx, ErrorsView{ValidationErrors: []*ErrorObject{{Status: "404", Title: "not found"}}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"errors": [{"status": "404", "title": "not found", "source": {}, "meta": {"request_id": "f3b4c1"}}]}`))
+	})
+})
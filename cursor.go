@@ -0,0 +1,201 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// CursorProfile is the URI of cursor pagination profile https://jsonapi.org/profiles/ethanresnick/cursor-pagination/
+const CursorProfile = "https://jsonapi.org/profiles/ethanresnick/cursor-pagination/"
+
+// Cursor pagination profile error types, given as error object "type" link.
+const (
+	CursorMaxSizeExceeded             = CursorProfile + "max-size-exceeded"
+	CursorInvalidParameterValue       = CursorProfile + "invalid-parameter-value"
+	CursorRangePaginationNotSupported = CursorProfile + "range-pagination-not-supported"
+)
+
+// CursorConfig describes cursor pagination settings of an endpoint.
+type CursorConfig struct {
+	// DefaultSize page size used when it is not given.
+	DefaultSize int
+	// MaxSize maximum page size, larger sizes are rejected as the profile requires. Zero means no limit.
+	MaxSize int
+	// RangePagination allows both page[after] and page[before] in a single request.
+	RangePagination bool
+}
+
+// CursorPage describes cursor pagination profile parameters.
+type CursorPage struct {
+	// After cursor, the page contains resources following it.
+	After string
+	// Before cursor, the page contains resources preceding it.
+	Before string
+	// Size page size.
+	Size int
+}
+
+// CursorPageMeta describes cursor pagination profile "page" document meta member.
+type CursorPageMeta struct {
+	// MaxSize maximum page size.
+	MaxSize int `json:"maxSize,omitempty"`
+	// Total number of resources in the collection, when it is known.
+	Total *int `json:"total,omitempty"`
+	// EstimatedTotal estimated number of resources in the collection.
+	EstimatedTotal *int `json:"estimatedTotal,omitempty"`
+	// RangeTruncated tells the page doesn't contain all resources of the requested range.
+	RangeTruncated bool `json:"rangeTruncated,omitempty"`
+}
+
+// ParseCursorPage parses page parameters, e.g. QueryParams.Page, according to cursor pagination profile.
+// The errors are returned as error objects with source parameter and the profile error type links.
+func ParseCursorPage(params map[string]string, config CursorConfig) (CursorPage, []*ErrorObject) {
+	var errs []*ErrorObject
+
+	page := CursorPage{Size: config.DefaultSize}
+
+	names := make([]string, 0, len(params))
+
+	for name := range params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		parameter := QueryKey("page", name)
+		value := params[name]
+
+		switch name {
+		case "after":
+			page.After = value
+		case "before":
+			page.Before = value
+		case "size":
+			size, err := strconv.Atoi(value)
+			if err != nil || size < 1 {
+				errs = append(errs, cursorError(parameter, CursorInvalidParameterValue, "Invalid parameter value", "Page size must be a positive integer."))
+				continue
+			}
+
+			if config.MaxSize > 0 && size > config.MaxSize {
+				err := cursorError(parameter, CursorMaxSizeExceeded, "Page size requested is too large", fmt.Sprintf("Page size must not exceed %d.", config.MaxSize))
+				err.Meta = map[string]interface{}{
+					"page": map[string]interface{}{"maxSize": config.MaxSize},
+				}
+
+				errs = append(errs, err)
+				continue
+			}
+
+			page.Size = size
+		default:
+			errs = append(errs, queryError(parameter, "Unsupported query parameter", fmt.Sprintf("Query parameter %q is not supported.", parameter)))
+		}
+	}
+
+	if len(page.After) > 0 && len(page.Before) > 0 && !config.RangePagination {
+		errs = append(errs, cursorError(QueryKey("page", "before"), CursorRangePaginationNotSupported, "Range pagination not supported", "Only one of page[after] and page[before] may be given."))
+	}
+
+	return page, errs
+}
+
+func cursorError(parameter, typ, title, detail string) *ErrorObject {
+	return &ErrorObject{
+		Status: strconv.Itoa(http.StatusBadRequest),
+		Title:  title,
+		Detail: detail,
+		Source: ErrorObjectSource{Parameter: parameter},
+		Links:  Links{"type": {Href: typ}},
+	}
+}
+
+// CursorMeta returns resource meta carrying the resource cursor, to be returned from the resource GetMeta.
+func CursorMeta(cursor string) interface{} {
+	return map[string]interface{}{
+		"page": map[string]string{"cursor": cursor},
+	}
+}
+
+// CursorLinks returns prev and next pagination links built from the request URL u, keeping its other query parameters.
+// Empty cursor means there is no such page, the link is marshaled as null as the profile requires.
+func CursorLinks(u *url.URL, page CursorPage, prevCursor, nextCursor string) Links {
+	link := func(param, cursor string) *Link {
+		if len(cursor) == 0 {
+			return nil
+		}
+
+		copied := *u
+		query := copied.Query()
+
+		query.Del(QueryKey("page", "after"))
+		query.Del(QueryKey("page", "before"))
+		query.Set(param, cursor)
+
+		if page.Size > 0 {
+			query.Set(QueryKey("page", "size"), strconv.Itoa(page.Size))
+		}
+
+		copied.RawQuery = query.Encode()
+
+		return &Link{Href: copied.String()}
+	}
+
+	return Links{
+		"prev": link(QueryKey("page", "before"), prevCursor),
+		"next": link(QueryKey("page", "after"), nextCursor),
+	}
+}
+
+// CursorPaginatedView is a view of a collection page following cursor pagination profile.
+type CursorPaginatedView struct {
+	// Data page resources, they should return CursorMeta from their GetMeta.
+	Data interface{}
+	// Included resources.
+	Included []interface{}
+	// Page "page" document meta member.
+	Page CursorPageMeta
+	// Links pagination links.
+	Links Links
+}
+
+// PaginateCursor returns view of data being the page of a collection, u is the request URL,
+// prevCursor and nextCursor are cursors of the first and the last page resources, empty when there is no such page.
+func PaginateCursor(data interface{}, page CursorPage, u *url.URL, prevCursor, nextCursor string) CursorPaginatedView {
+	return CursorPaginatedView{
+		Data:  data,
+		Links: CursorLinks(u, page, prevCursor, nextCursor),
+	}
+}
+
+// GetData returns page resources.
+func (v CursorPaginatedView) GetData() interface{} {
+	return v.Data
+}
+
+// GetIncluded returns included resources.
+func (v CursorPaginatedView) GetIncluded() []interface{} {
+	return v.Included
+}
+
+// GetMeta returns "page" meta member.
+func (v CursorPaginatedView) GetMeta() interface{} {
+	if v.Page == (CursorPageMeta{}) {
+		return struct{}{}
+	}
+
+	return map[string]interface{}{"page": v.Page}
+}
+
+// GetLinks returns pagination links.
+func (v CursorPaginatedView) GetLinks() Links {
+	return v.Links
+}
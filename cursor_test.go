@@ -0,0 +1,96 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Cursor pagination", func() {
+
+	config := CursorConfig{DefaultSize: 10, MaxSize: 50}
+
+	It("parses cursor page parameters", func() {
+		page, errs := ParseCursorPage(map[string]string{"after": "abc", "size": "20"}, config)
+
+		Ω(errs).Should(BeEmpty())
+		Ω(page).Should(Equal(CursorPage{After: "abc", Size: 20}))
+	})
+
+	It("uses default size", func() {
+		page, errs := ParseCursorPage(map[string]string{}, config)
+
+		Ω(errs).Should(BeEmpty())
+		Ω(page.Size).Should(Equal(10))
+	})
+
+	It("rejects too large page size", func() {
+		_, errs := ParseCursorPage(map[string]string{"size": "100"}, config)
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Status).Should(Equal("400"))
+		Ω(errs[0].Source.Parameter).Should(Equal("page[size]"))
+		Ω(errs[0].Links["type"].Href).Should(Equal(CursorMaxSizeExceeded))
+		Ω(errs[0].Meta).Should(HaveKeyWithValue("page", map[string]interface{}{"maxSize": 50}))
+	})
+
+	It("rejects range pagination unless it is supported", func() {
+		params := map[string]string{"after": "a", "before": "b"}
+
+		_, errs := ParseCursorPage(params, config)
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Links["type"].Href).Should(Equal(CursorRangePaginationNotSupported))
+
+		_, errs = ParseCursorPage(params, CursorConfig{RangePagination: true})
+
+		Ω(errs).Should(BeEmpty())
+	})
+
+	It("marshals cursor paginated view", func() {
+		u, _ := url.Parse("/books?sort=title&page[after]=x")
+		page := CursorPage{After: "x", Size: 1}
+
+		view := PaginateCursor(Books{{ID: "2", Title: "Go in Action", Year: "2015", Type: "books"}}, page, u, "", "c2")
+		view.Page = CursorPageMeta{MaxSize: 50}
+
+		result, err := Marshal(view)
+
+		expected := `
+      {
+        "data": [
+          {
+            "type": "books",
+            "id": "2",
+            "attributes": {
+              "title": "Go in Action",
+              "year": "2015"
+            }
+          }
+        ],
+        "meta": {
+          "page": {
+            "maxSize": 50
+          }
+        },
+        "links": {
+          "prev": null,
+          "next": "/books?page%5Bafter%5D=c2&page%5Bsize%5D=1&sort=title"
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("returns resource cursor meta", func() {
+		Ω(CursorMeta("c1")).Should(Equal(map[string]interface{}{"page": map[string]string{"cursor": "c1"}}))
+	})
+})
@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnmarshalDefaulted interface may be implemented to learn which attributes were absent
+// from the creation document and got their default values, see Options.Create.
+//
+// SetDefaulted example:
+//
+//	func (s *SomeStruct) SetDefaulted(attributes []string) error {
+//	  s.Defaulted = attributes
+//	  return nil
+//	}
+type UnmarshalDefaulted interface {
+	SetDefaulted(attributes []string) error
+}
+
+// defaultAttributes returns default values of the resource attributes by their names:
+// the ones given by `jsonapi:"default=value"` field tags, overridden by Options.Defaults.
+// Tag value of a string field is taken as is, of any other field it is decoded as JSON.
+// Tag values containing commas are single-quoted, e.g. `jsonapi:"default='[1,2]'"`.
+func defaultAttributes(resourceType string, typ reflect.Type, opts *Options) (map[string]json.RawMessage, error) {
+	defaults := map[string]json.RawMessage{}
+
+	for name, field := range jsonFields(typ) {
+		value, ok := field.tag["default"]
+		if !ok {
+			continue
+		}
+
+		fieldType := field.field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.String {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+
+			defaults[name] = raw
+			continue
+		}
+
+		if !json.Valid([]byte(value)) {
+			return nil, fmt.Errorf("jsonapi: %s default value of %q attribute is not valid JSON", typ, name)
+		}
+
+		defaults[name] = json.RawMessage(value)
+	}
+
+	for name, value := range opts.Defaults[resourceType] {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		defaults[name] = raw
+	}
+
+	return defaults, nil
+}
+
+// applyDefaults adds default values of the absent attributes and returns names of the defaulted ones.
+func applyDefaults(resourceType string, attributes json.RawMessage, typ reflect.Type, opts *Options) (json.RawMessage, []string, error) {
+	defaults, err := defaultAttributes(resourceType, typ, opts)
+	if err != nil || len(defaults) == 0 {
+		return attributes, nil, err
+	}
+
+	var defaulted []string
+
+	attributes, err = transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for name, value := range defaults {
+			if _, ok := members[name]; ok {
+				continue
+			}
+
+			members[name] = value
+			defaulted = append(defaulted, name)
+		}
+
+		return nil
+	})
+
+	sort.Strings(defaulted)
+
+	return attributes, defaulted, err
+}
+
+func isPrimaryPointer(pointer string) bool {
+	return strings.HasPrefix(pointer, "/data")
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Article struct {
+	ID        string   `json:"-"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status" jsonapi:"default=draft"`
+	Rating    int      `json:"rating" jsonapi:"default=3"`
+	Scores    []int    `json:"scores" jsonapi:"default='[1,2]'"`
+	Summary   string   `json:"summary" jsonapi:"default='short, sweet'"`
+	Tags      []string `json:"tags"`
+	Defaulted []string `json:"-"`
+}
+
+func (a *Article) SetID(id string) error {
+	a.ID = id
+	return nil
+}
+
+func (a *Article) SetType(string) error {
+	return nil
+}
+
+func (a *Article) SetDefaulted(attributes []string) error {
+	a.Defaulted = attributes
+	return nil
+}
+
+type ArticleView struct {
+	Article Article
+}
+
+func (v *ArticleView) SetData(to func(target interface{}) error) error {
+	return to(&v.Article)
+}
+
+var _ = Describe("Defaults", func() {
+
+	data := []byte(`{"data": {"type": "articles", "attributes": {"title": "Defaults", "rating": 5}}}`)
+
+	It("applies default values of absent attributes on create", func() {
+		view := ArticleView{}
+
+		opts := Options{
+			Create:   true,
+			Defaults: map[string]map[string]interface{}{"articles": {"tags": []string{"new"}}},
+		}
+
+		_, err := UnmarshalWithOptions(data, &view, opts)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Article.Title).Should(Equal("Defaults"))
+		Ω(view.Article.Status).Should(Equal("draft"))
+		Ω(view.Article.Rating).Should(Equal(5))
+		Ω(view.Article.Tags).Should(Equal([]string{"new"}))
+		Ω(view.Article.Scores).Should(Equal([]int{1, 2}))
+		Ω(view.Article.Summary).Should(Equal("short, sweet"))
+		Ω(view.Article.Defaulted).Should(Equal([]string{"scores", "status", "summary", "tags"}))
+	})
+
+	It("reports defaulted attributes apart from the present ones", func() {
+		view := ArticleView{}
+
+		_, report, err := UnmarshalWithReport(data, &view, WithOptions(Options{Create: true}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Resources).Should(HaveLen(1))
+		Ω(report.Resources[0].Attributes.Names()).Should(Equal([]string{"rating", "title"}))
+		Ω(report.Resources[0].Defaulted.Names()).Should(Equal([]string{"scores", "status", "summary"}))
+	})
+
+	It("doesn't apply default values unless creating", func() {
+		view := ArticleView{}
+
+		_, err := Unmarshal(data, &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Article.Status).Should(BeEmpty())
+		Ω(view.Article.Defaulted).Should(BeNil())
+	})
+})
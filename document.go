@@ -0,0 +1,345 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// PrimaryResources method returns resource objects of the document primary data,
+// it is empty when the data is null or missing.
+func (d *Document) PrimaryResources() []*ResourceObject {
+	return documentResources(&Document{Data: d.Data})
+}
+
+// FindIncluded method returns included resource object by its type and ID, nil when it is not included.
+func (d *Document) FindIncluded(resourceType, id string) *ResourceObject {
+	for _, ro := range d.Included {
+		if ro.Type == resourceType && ro.ID == id {
+			return ro
+		}
+	}
+
+	return nil
+}
+
+// ResourceCount method returns number of resource objects in the document, of primary data and included.
+func (d *Document) ResourceCount() int {
+	return len(documentResources(d))
+}
+
+// HasErrors method tells whether the document contains error objects.
+func (d *Document) HasErrors() bool {
+	return len(d.Errors) > 0
+}
+
+// SetOne method sets single resource object as the document primary data, nil resource object is marshaled as null.
+func (d *Document) SetOne(ro *ResourceObject) {
+	d.Data = &documentData{One: ro}
+}
+
+// SetMany method sets resource objects collection as the document primary data, it is marshaled as empty array when there are none.
+func (d *Document) SetMany(resources ...*ResourceObject) {
+	d.Data = &documentData{Many: append([]*ResourceObject{}, resources...)}
+}
+
+// AddIncluded method adds resource objects to the document included, skipping the ones already included
+// or present in primary data, the resource objects are identified by type and ID, or LID when they have no ID.
+func (d *Document) AddIncluded(resources ...*ResourceObject) {
+	present := map[ResourceObjectIdentifier]bool{}
+
+	for _, ro := range documentResources(d) {
+		present[resourceKey(ro)] = true
+	}
+
+	for _, ro := range resources {
+		if key := resourceKey(ro); !present[key] {
+			present[key] = true
+			d.Included = append(d.Included, ro)
+		}
+	}
+}
+
+// AddError method adds error object to the document errors.
+func (d *Document) AddError(e *ErrorObject) {
+	d.Errors = append(d.Errors, e)
+}
+
+// SetMeta method sets the document meta marshaled out of v, nil removes the meta.
+func (d *Document) SetMeta(v interface{}) error {
+	if v == nil {
+		d.Meta = nil
+		return nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	d.Meta = raw
+
+	return nil
+}
+
+// GetAttribute method returns raw JSON value of the attribute the JSON pointer refers to,
+// e.g. "/data/attributes/title", "/data/2/attributes/title" or "/included/0/attributes/address/city".
+// It returns false when there is no such value.
+func (d *Document) GetAttribute(pointer string) (json.RawMessage, bool) {
+	ro, tokens := d.attributeResource(pointer)
+	if ro == nil {
+		return nil, false
+	}
+
+	return lookupValue(ro.Attributes, tokens)
+}
+
+// DecodeAttribute decodes value of the attribute the JSON pointer refers to into target,
+// e.g. doc.DecodeAttribute("/data/attributes/address", &address). It returns false when there is no such value.
+func (d *Document) DecodeAttribute(pointer string, target interface{}) (bool, error) {
+	raw, ok := d.GetAttribute(pointer)
+	if !ok {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(raw, target)
+}
+
+// SetAttribute sets value of the attribute the JSON pointer refers to, e.g. "/data/attributes/address/city".
+// Missing objects along the pointer are created, array elements are replaced by index and "-" appends one.
+func (d *Document) SetAttribute(pointer string, value interface{}) error {
+	ro, tokens := d.attributeResource(pointer)
+	if ro == nil {
+		return fmt.Errorf("%w: %q doesn't refer to resource attribute", ErrInvalidPointer, pointer)
+	}
+
+	raw, err := encodeRaw(value)
+	if err != nil {
+		return err
+	}
+
+	attributes, err := setValue(ro.Attributes, tokens, raw)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrInvalidPointer, pointer, err)
+	}
+
+	ro.Attributes = attributes
+
+	return nil
+}
+
+// DeleteAttribute removes the attribute or nested value the JSON pointer refers to, it returns false when there is no such value.
+func (d *Document) DeleteAttribute(pointer string) bool {
+	ro, tokens := d.attributeResource(pointer)
+	if ro == nil {
+		return false
+	}
+
+	if _, ok := lookupValue(ro.Attributes, tokens); !ok {
+		return false
+	}
+
+	attributes, err := setValue(ro.Attributes, tokens, nil)
+	if err != nil {
+		return false
+	}
+
+	ro.Attributes = attributes
+
+	return true
+}
+
+// attributeResource returns the resource object the attribute pointer refers to and the reference tokens within its attributes.
+func (d *Document) attributeResource(pointer string) (*ResourceObject, []string) {
+	tokens, err := ParsePointer(pointer)
+	if err != nil || len(tokens) < 3 {
+		return nil, nil
+	}
+
+	var ro *ResourceObject
+
+	switch tokens[0] {
+	case "data":
+		if d.Data == nil {
+			return nil, nil
+		}
+
+		if d.Data.One != nil {
+			ro, tokens = d.Data.One, tokens[1:]
+		} else {
+			ro, tokens = indexResource(d.Data.Many, tokens[1:])
+		}
+	case "included":
+		ro, tokens = indexResource(d.Included, tokens[1:])
+	}
+
+	if ro == nil || len(tokens) < 2 || tokens[0] != "attributes" {
+		return nil, nil
+	}
+
+	return ro, tokens[1:]
+}
+
+func resourceKey(ro *ResourceObject) ResourceObjectIdentifier {
+	if len(ro.ID) > 0 {
+		return existenceKey(ro.ResourceObjectIdentifier)
+	}
+
+	return ResourceObjectIdentifier{Type: ro.Type, LID: ro.LID}
+}
+
+// indexResource returns the resource object at the index given by the first token and the rest tokens.
+func indexResource(resources []*ResourceObject, tokens []string) (*ResourceObject, []string) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	index, err := strconv.Atoi(tokens[0])
+	if err != nil || index < 0 || index >= len(resources) || strconv.Itoa(index) != tokens[0] {
+		return nil, nil
+	}
+
+	return resources[index], tokens[1:]
+}
+
+// lookupValue returns the value nested into raw JSON object or array along the unescaped reference tokens.
+func lookupValue(raw json.RawMessage, tokens []string) (json.RawMessage, bool) {
+	for _, token := range tokens {
+		switch jsonDelim(raw) {
+		case '{':
+			members := map[string]json.RawMessage{}
+
+			if err := json.Unmarshal(raw, &members); err != nil {
+				return nil, false
+			}
+
+			value, ok := members[token]
+			if !ok {
+				return nil, false
+			}
+
+			raw = value
+		case '[':
+			var items []json.RawMessage
+
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, false
+			}
+
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(items) || strconv.Itoa(index) != token {
+				return nil, false
+			}
+
+			raw = items[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return raw, true
+}
+
+// setValue returns raw JSON object or array with the value nested along the reference tokens set to value,
+// nil value removes it. Missing objects along the tokens are created.
+func setValue(raw json.RawMessage, tokens []string, value json.RawMessage) (json.RawMessage, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	token := tokens[0]
+
+	if jsonDelim(raw) == '[' {
+		var items []json.RawMessage
+
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+
+		index := len(items)
+
+		if token != "-" {
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(items) || strconv.Itoa(i) != token {
+				return nil, fmt.Errorf("array index %q out of range", token)
+			}
+
+			index = i
+		} else if value == nil || len(tokens) > 1 {
+			return nil, errors.New(`"-" may only append a value`)
+		}
+
+		if value == nil && len(tokens) == 1 {
+			items = append(items[:index], items[index+1:]...)
+
+			return encodeRaw(items)
+		}
+
+		var item json.RawMessage
+
+		if index < len(items) {
+			item = items[index]
+		}
+
+		item, err := setValue(item, tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		if index == len(items) {
+			items = append(items, item)
+		} else {
+			items[index] = item
+		}
+
+		return encodeRaw(items)
+	}
+
+	members := map[string]json.RawMessage{}
+
+	switch jsonDelim(raw) {
+	case '{':
+		if err := json.Unmarshal(raw, &members); err != nil {
+			return nil, err
+		}
+	case 0:
+		// missing or null value becomes an object
+		if len(raw) > 0 && string(bytes.TrimSpace(raw)) != "null" {
+			return nil, fmt.Errorf("%q can't be set in a scalar value", token)
+		}
+	default:
+		return nil, fmt.Errorf("%q can't be set in a scalar value", token)
+	}
+
+	if value == nil && len(tokens) == 1 {
+		delete(members, token)
+	} else {
+		member, err := setValue(members[token], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		members[token] = member
+	}
+
+	return encodeRaw(members)
+}
+
+// encodeRaw encodes v without HTML escaping and trailing newline.
+func encodeRaw(v interface{}) (json.RawMessage, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
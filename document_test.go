@@ -0,0 +1,162 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Document", func() {
+
+	payload := []byte(`
+    {
+      "data": [
+        {
+          "type": "books",
+          "id": "1",
+          "attributes": {"title": "Go", "tags": ["programming", "go"]},
+          "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}
+        },
+        {
+          "type": "books",
+          "id": "2",
+          "attributes": {"title": "Go Web", "tags": []},
+          "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}
+        }
+      ],
+      "included": [
+        {"type": "authors", "id": "1", "attributes": {"name": "Rob", "address": {"city": "Sydney"}}}
+      ]
+    }
+  `)
+
+	var doc *Document
+
+	BeforeEach(func() {
+		var err error
+
+		doc, err = Unmarshal(payload, &BooksView{})
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("returns primary resources", func() {
+		resources := doc.PrimaryResources()
+
+		Ω(resources).Should(HaveLen(2))
+		Ω(resources[1].ID).Should(Equal("2"))
+		Ω(doc.ResourceCount()).Should(Equal(3))
+		Ω(doc.HasErrors()).Should(BeFalse())
+	})
+
+	It("finds included resource", func() {
+		Ω(doc.FindIncluded("authors", "1")).ShouldNot(BeNil())
+		Ω(doc.FindIncluded("authors", "2")).Should(BeNil())
+	})
+
+	It("gets attribute by pointer", func() {
+		title, ok := doc.GetAttribute("/data/1/attributes/title")
+		Ω(ok).Should(BeTrue())
+		Ω(title).Should(MatchJSON(`"Go Web"`))
+
+		tag, ok := doc.GetAttribute("/data/0/attributes/tags/1")
+		Ω(ok).Should(BeTrue())
+		Ω(tag).Should(MatchJSON(`"go"`))
+
+		city, ok := doc.GetAttribute("/included/0/attributes/address/city")
+		Ω(ok).Should(BeTrue())
+		Ω(city).Should(MatchJSON(`"Sydney"`))
+	})
+
+	It("doesn't get missing attribute", func() {
+		for _, pointer := range []string{"/data/2/attributes/title", "/data/0/attributes/year", "/data/0/relationships/author", "/included/0/attributes/name/first", "data"} {
+			_, ok := doc.GetAttribute(pointer)
+			Ω(ok).Should(BeFalse(), pointer)
+		}
+	})
+
+	It("decodes nested attribute by pointer", func() {
+		var address struct {
+			City string `json:"city"`
+		}
+
+		ok, err := doc.DecodeAttribute("/included/0/attributes/address", &address)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(ok).Should(BeTrue())
+		Ω(address.City).Should(Equal("Sydney"))
+
+		ok, err = doc.DecodeAttribute("/included/0/attributes/phone", &address)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(ok).Should(BeFalse())
+	})
+
+	It("sets nested attributes by pointer creating missing objects", func() {
+		Ω(doc.SetAttribute("/included/0/attributes/address/city", "Melbourne")).Should(Succeed())
+		Ω(doc.SetAttribute("/included/0/attributes/address/geo/lat", -37.8)).Should(Succeed())
+		Ω(doc.SetAttribute("/data/0/attributes/tags/0", "golang")).Should(Succeed())
+		Ω(doc.SetAttribute("/data/1/attributes/tags/-", "web")).Should(Succeed())
+		Ω(doc.SetAttribute("/data/1/attributes/edition", map[string]int{"number": 2})).Should(Succeed())
+
+		Ω(doc.Included[0].Attributes).Should(MatchJSON(`{"name": "Rob", "address": {"city": "Melbourne", "geo": {"lat": -37.8}}}`))
+		Ω(doc.Data.Many[0].Attributes).Should(MatchJSON(`{"title": "Go", "tags": ["golang", "go"]}`))
+		Ω(doc.Data.Many[1].Attributes).Should(MatchJSON(`{"title": "Go Web", "tags": ["web"], "edition": {"number": 2}}`))
+	})
+
+	It("doesn't set attributes along invalid pointers", func() {
+		for _, pointer := range []string{"/data/2/attributes/title", "/data/0/relationships/author", "/data/0/attributes/title/first", "/data/0/attributes/tags/5", "data"} {
+			Ω(doc.SetAttribute(pointer, "x")).Should(MatchError(ErrInvalidPointer), pointer)
+		}
+	})
+
+	It("deletes nested attributes by pointer", func() {
+		Ω(doc.DeleteAttribute("/included/0/attributes/address/city")).Should(BeTrue())
+		Ω(doc.DeleteAttribute("/data/0/attributes/tags/0")).Should(BeTrue())
+		Ω(doc.DeleteAttribute("/data/0/attributes/year")).Should(BeFalse())
+
+		Ω(doc.Included[0].Attributes).Should(MatchJSON(`{"name": "Rob", "address": {}}`))
+		Ω(doc.Data.Many[0].Attributes).Should(MatchJSON(`{"title": "Go", "tags": ["go"]}`))
+	})
+})
+
+var _ = Describe("Document mutation", func() {
+
+	book := &ResourceObject{ResourceObjectIdentifier: ResourceObjectIdentifier{Type: "books", ID: "1"}}
+	author := &ResourceObject{ResourceObjectIdentifier: ResourceObjectIdentifier{Type: "authors", ID: "1"}}
+	draft := &ResourceObject{ResourceObjectIdentifier: ResourceObjectIdentifier{Type: "authors", LID: "new-author"}}
+
+	It("adds included resources once", func() {
+		doc := &Document{}
+		doc.SetOne(book)
+		doc.AddIncluded(author, book, draft)
+		doc.AddIncluded(&ResourceObject{ResourceObjectIdentifier: ResourceObjectIdentifier{Type: "authors", ID: "1"}}, draft)
+
+		Ω(doc.Included).Should(Equal([]*ResourceObject{author, draft}))
+	})
+
+	It("sets primary data", func() {
+		doc := &Document{}
+		doc.SetMany(book)
+		Ω(doc.PrimaryResources()).Should(Equal([]*ResourceObject{book}))
+
+		doc.SetOne(nil)
+		Ω(doc.PrimaryResources()).Should(BeEmpty())
+	})
+
+	It("sets meta and adds errors", func() {
+		doc := &Document{}
+
+		Ω(doc.SetMeta(map[string]int{"total": 2})).Should(Succeed())
+		Ω(doc.Meta).Should(MatchJSON(`{"total": 2}`))
+
+		Ω(doc.SetMeta(func() {})).ShouldNot(Succeed())
+
+		Ω(doc.SetMeta(nil)).Should(Succeed())
+		Ω(doc.Meta).Should(BeNil())
+
+		doc.AddError(&ErrorObject{Title: "Oops"})
+		Ω(doc.HasErrors()).Should(BeTrue())
+	})
+})
@@ -0,0 +1,168 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDuration returned when ISO 8601 duration is malformed, the error is wrapped with the details.
+var ErrInvalidDuration = errors.New("jsonapi: invalid ISO 8601 duration")
+
+// Duration is time.Duration attribute value marshaled as ISO 8601 duration string, e.g. "PT1H30M" or "-PT0.5S".
+// Days and weeks are parsed as 24 and 168 hours, years and months aren't supported because their length varies.
+type Duration time.Duration
+
+var durationUnits = []struct {
+	designator byte
+	unit       time.Duration
+	time       bool
+}{
+	{'W', 7 * 24 * time.Hour, false},
+	{'D', 24 * time.Hour, false},
+	{'H', time.Hour, true},
+	{'M', time.Minute, true},
+	{'S', time.Second, true},
+}
+
+// ParseDuration parses ISO 8601 duration, e.g. "P1DT2H", "PT1H30M" or "PT0.25S".
+func ParseDuration(s string) (Duration, error) {
+	text := s
+	sign := time.Duration(1)
+
+	switch {
+	case strings.HasPrefix(text, "-"):
+		sign, text = -1, text[1:]
+	case strings.HasPrefix(text, "+"):
+		text = text[1:]
+	}
+
+	if !strings.HasPrefix(text, "P") || len(text) < 3 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+
+	text = text[1:]
+
+	var (
+		total    float64
+		inTime   bool
+		unitNext int
+	)
+
+	for len(text) > 0 {
+		if text[0] == 'T' {
+			if inTime || len(text) == 1 {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+			}
+
+			inTime = true
+			text = text[1:]
+
+			continue
+		}
+
+		end := strings.IndexFunc(text, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+		if end <= 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+
+		number, err := strconv.ParseFloat(strings.Replace(text[:end], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+
+		i := unitNext
+		for i < len(durationUnits) && (durationUnits[i].designator != text[end] || durationUnits[i].time != inTime) {
+			i++
+		}
+
+		if i == len(durationUnits) {
+			return 0, fmt.Errorf("%w: %q: unsupported or misplaced designator %q", ErrInvalidDuration, s, text[end])
+		}
+
+		total += number * float64(durationUnits[i].unit)
+		unitNext = i + 1
+		text = text[end+1:]
+	}
+
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %q overflows", ErrInvalidDuration, s)
+	}
+
+	return Duration(sign * time.Duration(math.Round(total))), nil
+}
+
+// String returns ISO 8601 duration in hours, minutes and seconds, e.g. "PT1H30M", zero duration gives "PT0S".
+func (d Duration) String() string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+
+	// math.MinInt64 has no positive counterpart, uint64 holds it
+	n := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		n = -n
+	}
+
+	b.WriteString("PT")
+
+	if hours := n / uint64(time.Hour); hours > 0 {
+		b.WriteString(strconv.FormatUint(hours, 10) + "H")
+	}
+
+	if minutes := n % uint64(time.Hour) / uint64(time.Minute); minutes > 0 {
+		b.WriteString(strconv.FormatUint(minutes, 10) + "M")
+	}
+
+	if nanos := n % uint64(time.Minute); nanos > 0 {
+		seconds := strconv.FormatUint(nanos/uint64(time.Second), 10)
+
+		if fraction := nanos % uint64(time.Second); fraction > 0 {
+			seconds += strings.TrimRight(fmt.Sprintf(".%09d", fraction), "0")
+		}
+
+		b.WriteString(seconds + "S")
+	}
+
+	return b.String()
+}
+
+// MarshalJSON marshals the duration as ISO 8601 string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON unmarshals ISO 8601 duration string, null leaves the duration unchanged.
+func (d *Duration) UnmarshalJSON(payload []byte) error {
+	if string(payload) == "null" {
+		return nil
+	}
+
+	var s string
+
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return fmt.Errorf("%w: duration must be a string", ErrInvalidDuration)
+	}
+
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Workout struct {
+	ID       string   `json:"-"`
+	Duration Duration `json:"duration"`
+}
+
+func (w Workout) GetID() string {
+	return w.ID
+}
+
+func (w Workout) GetType() string {
+	return "workouts"
+}
+
+func (w *Workout) SetID(id string) error {
+	w.ID = id
+	return nil
+}
+
+func (w *Workout) SetType(string) error {
+	return nil
+}
+
+func (w *Workout) SetData(to func(target interface{}) error) error {
+	return to(w)
+}
+
+func (w Workout) GetData() interface{} {
+	return w
+}
+
+var _ = Describe("Duration", func() {
+	It("formats durations in hours, minutes and seconds", func() {
+		for d, s := range map[time.Duration]string{
+			0:                                 "PT0S",
+			90 * time.Minute:                  "PT1H30M",
+			26 * time.Hour:                    "PT26H",
+			45 * time.Second:                  "PT45S",
+			1500 * time.Millisecond:           "PT1.5S",
+			-time.Hour - 250*time.Millisecond: "-PT1H0.25S",
+			time.Nanosecond:                   "PT0.000000001S",
+		} {
+			Ω(Duration(d).String()).Should(Equal(s))
+		}
+	})
+
+	It("parses ISO 8601 durations", func() {
+		for s, d := range map[string]time.Duration{
+			"PT0S":       0,
+			"PT1H30M":    90 * time.Minute,
+			"P1DT2H":     26 * time.Hour,
+			"P1W":        7 * 24 * time.Hour,
+			"PT1.5S":     1500 * time.Millisecond,
+			"PT0,25S":    250 * time.Millisecond,
+			"-PT1H0.25S": -time.Hour - 250*time.Millisecond,
+			"PT0.5H":     30 * time.Minute,
+		} {
+			parsed, err := ParseDuration(s)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(time.Duration(parsed)).Should(Equal(d), s)
+		}
+	})
+
+	It("fails on malformed and unsupported durations", func() {
+		for _, s := range []string{"", "P", "PT", "1H", "PT1", "P1H", "PT1D", "PT1M1H", "P1Y", "P1M", "P1DT", "PTxS"} {
+			_, err := ParseDuration(s)
+			Ω(err).Should(MatchError(ErrInvalidDuration), s)
+		}
+	})
+
+	It("marshals and unmarshals duration attributes", func() {
+		payload, err := Marshal(Workout{ID: "1", Duration: Duration(95 * time.Minute)})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`{"data": {"type": "workouts", "id": "1", "attributes": {"duration": "PT1H35M"}}}`))
+
+		var workout Workout
+
+		_, err = Unmarshal(payload, &workout)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(workout.Duration).Should(Equal(Duration(95 * time.Minute)))
+
+		_, err = Unmarshal([]byte(`{"data": {"type": "workouts", "id": "1", "attributes": {"duration": 5700000000000}}}`), &Workout{})
+		Ω(err).Should(MatchError(ErrInvalidDuration))
+	})
+
+	It("round trips through JSON", func() {
+		var d Duration
+
+		Ω(json.Unmarshal([]byte(`"P2DT3H4M5.006S"`), &d)).Should(Succeed())
+		Ω(json.Marshal(d)).Should(MatchJSON(`"PT51H4M5.006S"`))
+	})
+})
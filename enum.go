@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnumValues returns allowed values of the sample type attributes constrained by `jsonapi:"enum=a|b|c"` field tags,
+// e.g. to advertise them in API schema.
+func EnumValues(sample interface{}) map[string][]string {
+	return taggedEnums(reflect.TypeOf(sample))
+}
+
+func taggedEnums(typ reflect.Type) map[string][]string {
+	enums := map[string][]string{}
+
+	for name, field := range jsonFields(typ) {
+		if values, ok := field.tag["enum"]; ok && len(values) > 0 {
+			enums[name] = strings.Split(values, "|")
+		}
+	}
+
+	return enums
+}
+
+// validateEnums checks attribute values are among the allowed ones, given by field tags and Options.Enums,
+// and returns 422 error objects with the offending value in meta for the violations.
+func validateEnums(resourceType string, attributes json.RawMessage, typ reflect.Type, pointer string, opts *Options) ([]*ErrorObject, error) {
+	enums := taggedEnums(typ)
+
+	for name, values := range opts.Enums[resourceType] {
+		enums[name] = values
+	}
+
+	if len(enums) == 0 || len(attributes) == 0 {
+		return nil, nil
+	}
+
+	members := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(attributes, &members); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(enums))
+
+	for name := range enums {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var errs []*ErrorObject
+
+	for _, name := range names {
+		raw, ok := members[name]
+		if !ok || bytes.Equal(raw, []byte("null")) {
+			continue
+		}
+
+		var value interface{}
+
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+
+		if isEnumValue(raw, value, enums[name]) {
+			continue
+		}
+
+		errs = append(errs, &ErrorObject{
+			Status: strconv.Itoa(http.StatusUnprocessableEntity),
+			Title:  "Invalid attribute value",
+			Detail: fmt.Sprintf("Attribute %q must be one of: %s.", name, strings.Join(enums[name], ", ")),
+			Code:   "invalid_enum_value",
+			Source: ErrorObjectSource{Pointer: pointer + Pointer(name)},
+			Meta: map[string]interface{}{
+				"value":   value,
+				"allowed": enums[name],
+			},
+		})
+	}
+
+	return errs, nil
+}
+
+func isEnumValue(raw json.RawMessage, value interface{}, allowed []string) bool {
+	s, ok := value.(string)
+	if !ok {
+		s = string(bytes.TrimSpace(raw))
+	}
+
+	for _, candidate := range allowed {
+		if s == candidate {
+			return true
+		}
+	}
+
+	return false
+}
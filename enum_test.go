@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Ticket struct {
+	ID       string  `json:"-"`
+	Status   string  `json:"status" jsonapi:"enum=open|closed"`
+	Priority int     `json:"priority" jsonapi:"enum=1|2|3"`
+	Channel  *string `json:"channel"`
+}
+
+func (t *Ticket) SetID(id string) error {
+	t.ID = id
+	return nil
+}
+
+func (t *Ticket) SetType(string) error {
+	return nil
+}
+
+type TicketsView struct {
+	Tickets []Ticket
+}
+
+func (v *TicketsView) SetData(to func(target interface{}) error) error {
+	return to(&v.Tickets)
+}
+
+var _ = Describe("Enums", func() {
+
+	It("returns allowed values of tagged attributes", func() {
+		Ω(EnumValues(Ticket{})).Should(Equal(map[string][]string{
+			"status":   {"open", "closed"},
+			"priority": {"1", "2", "3"},
+		}))
+	})
+
+	It("unmarshals allowed values", func() {
+		view := TicketsView{}
+
+		_, err := Unmarshal([]byte(`{"data": [{"type": "tickets", "id": "1", "attributes": {"status": "open", "priority": 2, "channel": null}}]}`), &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Tickets[0].Status).Should(Equal("open"))
+	})
+
+	It("returns 422 errors for values which are not allowed", func() {
+		data := []byte(`
+      {
+        "data": [
+          {"type": "tickets", "id": "1", "attributes": {"status": "open", "priority": 1}},
+          {"type": "tickets", "id": "2", "attributes": {"status": "pending", "priority": 5, "channel": "fax"}}
+        ]
+      }
+    `)
+
+		opts := Options{Enums: map[string]map[string][]string{"tickets": {"channel": {"email", "phone"}}}}
+
+		_, err := UnmarshalWithOptions(data, &TicketsView{}, opts)
+
+		var validationErr *ValidationError
+
+		Ω(errors.As(err, &validationErr)).Should(BeTrue())
+		Ω(validationErr.Errors).Should(HaveLen(3))
+
+		Ω(validationErr.Errors[0].Status).Should(Equal("422"))
+		Ω(validationErr.Errors[0].Source.Pointer).Should(Equal("/data/1/attributes/channel"))
+		Ω(validationErr.Errors[1].Source.Pointer).Should(Equal("/data/1/attributes/priority"))
+		Ω(validationErr.Errors[1].Meta).Should(HaveKeyWithValue("value", float64(5)))
+		Ω(validationErr.Errors[2].Source.Pointer).Should(Equal("/data/1/attributes/status"))
+		Ω(validationErr.Errors[2].Meta).Should(HaveKeyWithValue("value", "pending"))
+	})
+})
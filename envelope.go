@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// EnvelopeContentType describes envelope data content type.
+const EnvelopeContentType = "application/vnd.jsonapi-envelope+json"
+
+// Envelope carries several named JSON API documents between internal services,
+// e.g. the primary response document next to a sideband audit document.
+// Envelope is not a part of JSON API specification and should not be exposed to the API clients.
+//
+// Envelope example:
+//
+//	envelope := jsonapi.NewEnvelope()
+//	envelope.Meta["hop"] = "billing"
+//
+//	if err := envelope.Add("response", bookView); err != nil {
+//	  // handle error
+//	}
+//
+//	data, err := jsonapi.MarshalEnvelope(envelope)
+//
+//	// on the next hop
+//
+//	envelope, err := jsonapi.UnmarshalEnvelope(data)
+//
+//	view := BookView{}
+//	_, err = envelope.Unmarshal("response", &view)
+type Envelope struct {
+	// Documents JSON API documents by name.
+	Documents map[string]*Document `json:"documents"`
+	// Meta envelope metadata, e.g. tracing information.
+	Meta map[string]interface{} `json:"meta,omitempty"`
+}
+
+// NewEnvelope returns empty envelope.
+func NewEnvelope() *Envelope {
+	return &Envelope{
+		Documents: map[string]*Document{},
+		Meta:      map[string]interface{}{},
+	}
+}
+
+// Add marshals payload the same way Marshal does and puts the document into envelope under the given name.
+func (e *Envelope) Add(name string, payload interface{}) error {
+	return e.AddWithOptions(name, payload, Options{})
+}
+
+// AddWithOptions marshals payload the same way MarshalWithOptions does and puts the document into envelope under the given name.
+func (e *Envelope) AddWithOptions(name string, payload interface{}, opts Options) error {
+	data, err := MarshalWithOptions(payload, opts)
+	if err != nil {
+		return err
+	}
+
+	doc := &Document{}
+
+	if err := json.Unmarshal(data, doc); err != nil {
+		return err
+	}
+
+	if e.Documents == nil {
+		e.Documents = map[string]*Document{}
+	}
+
+	e.Documents[name] = doc
+
+	return nil
+}
+
+// Unmarshal deserializes the document with the given name into target the same way Unmarshal does.
+func (e *Envelope) Unmarshal(name string, target interface{}) (*Document, error) {
+	return e.UnmarshalWithOptions(name, target, Options{})
+}
+
+// UnmarshalWithOptions deserializes the document with the given name into target the same way UnmarshalWithOptions does.
+func (e *Envelope) UnmarshalWithOptions(name string, target interface{}, opts Options) (*Document, error) {
+	doc, ok := e.Documents[name]
+	if !ok {
+		return nil, fmt.Errorf("jsonapi: envelope has no %q document", name)
+	}
+
+	data, err := encodeDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalWithOptions(data, target, opts)
+}
+
+// MarshalEnvelope serializes envelope into []byte.
+func MarshalEnvelope(e *Envelope) ([]byte, error) {
+	documents := e.Documents
+	if documents == nil {
+		documents = map[string]*Document{}
+	}
+
+	return encodeDocument(&Envelope{Documents: documents, Meta: e.Meta})
+}
+
+// UnmarshalEnvelope deserializes []byte into envelope.
+func UnmarshalEnvelope(data []byte) (*Envelope, error) {
+	e := NewEnvelope()
+
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+
+	if e.Documents == nil {
+		e.Documents = map[string]*Document{}
+	}
+
+	for name, doc := range e.Documents {
+		if doc == nil {
+			return nil, fmt.Errorf("jsonapi: envelope %q document is null", name)
+		}
+	}
+
+	return e, nil
+}
+
+func encodeDocument(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(v)
+
+	return buf.Bytes(), err
+}
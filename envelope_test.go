@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Envelope", func() {
+
+	book := Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"}
+
+	It("marshals named documents", func() {
+		envelope := NewEnvelope()
+		envelope.Meta["hop"] = "billing"
+
+		Ω(envelope.Add("response", BookView{Book: book})).Should(Succeed())
+		Ω(envelope.Add("audit", ErrorsView{ValidationErrors: []*ErrorObject{{Title: "Audit"}}})).Should(Succeed())
+
+		result, err := MarshalEnvelope(envelope)
+
+		expected := `
+      {
+        "documents": {
+          "audit": {
+            "errors": [
+              {
+                "title": "Audit",
+                "source": {}
+              }
+            ]
+          },
+          "response": {
+            "data": {
+              "type": "books",
+              "id": "1",
+              "attributes": {
+                "title": "Go in Action",
+                "year": "2015"
+              }
+            }
+          }
+        },
+        "meta": {
+          "hop": "billing"
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("unmarshals named documents", func() {
+		envelope := NewEnvelope()
+		Ω(envelope.Add("response", BookView{Book: book})).Should(Succeed())
+
+		data, err := MarshalEnvelope(envelope)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		decoded, err := UnmarshalEnvelope(data)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		view := BookView{}
+
+		_, err = decoded.Unmarshal("response", &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Book).Should(Equal(book))
+	})
+
+	It("returns error for missing document", func() {
+		_, err := NewEnvelope().Unmarshal("response", &BookView{})
+
+		Ω(err).Should(MatchError(`jsonapi: envelope has no "response" document`))
+	})
+
+	It("returns error for null document", func() {
+		_, err := UnmarshalEnvelope([]byte(`{"documents": {"response": null}}`))
+
+		Ω(err).Should(HaveOccurred())
+	})
+})
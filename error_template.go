@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParamsMeta is the error object meta member containing parameters of its title and detail templates.
+const ParamsMeta = "params"
+
+// ErrorTemplate describes an error object once, e.g. in an error catalog, its title and detail may contain
+// {name} placeholders which are replaced with the parameters given to New on Marshal and WriteErrors.
+//
+// ErrorTemplate example:
+//
+//	var ErrTooShort = jsonapi.ErrorTemplate{
+//	  Status: "422",
+//	  Code:   "too_short",
+//	  Title:  "is too short",
+//	  Detail: "must be at least {min} characters",
+//	}
+//
+//	tooShort := ErrTooShort.New(map[string]interface{}{"min": 8})
+//	tooShort.Source.Pointer = "/data/attributes/password"
+type ErrorTemplate struct {
+	// Status the HTTP status code applicable to this problem, expressed as a string value.
+	Status string
+	// Code application specified value to identify the error.
+	Code string
+	// Title a short, human-readable summary of the problem, may contain {name} placeholders.
+	Title string
+	// Detail a human-readable explanation of the problem, may contain {name} placeholders.
+	Detail string
+}
+
+// New returns error object of the template carrying params in its meta, see ParamsMeta.
+func (t ErrorTemplate) New(params map[string]interface{}) *ErrorObject {
+	eo := &ErrorObject{
+		Status: t.Status,
+		Code:   t.Code,
+		Title:  t.Title,
+		Detail: t.Detail,
+	}
+
+	if len(params) > 0 {
+		eo.Meta = map[string]interface{}{ParamsMeta: params}
+	}
+
+	return eo
+}
+
+// FormatErrors returns copies of error objects with {name} placeholders of their title and detail replaced
+// with the parameters carried in meta, see ParamsMeta. Placeholders without parameters are kept as is.
+// The given error objects are not changed.
+func FormatErrors(errs []*ErrorObject) []*ErrorObject {
+	if len(errs) == 0 {
+		return errs
+	}
+
+	formatted := make([]*ErrorObject, 0, len(errs))
+
+	for _, err := range errs {
+		params := errorParams(err)
+		if len(params) == 0 {
+			formatted = append(formatted, err)
+			continue
+		}
+
+		copied := *err
+		copied.Title = formatTemplate(err.Title, params)
+		copied.Detail = formatTemplate(err.Detail, params)
+
+		formatted = append(formatted, &copied)
+	}
+
+	return formatted
+}
+
+func errorParams(err *ErrorObject) map[string]interface{} {
+	if err == nil {
+		return nil
+	}
+
+	switch params := err.Meta[ParamsMeta].(type) {
+	case map[string]interface{}:
+		return params
+	case map[string]string:
+		converted := make(map[string]interface{}, len(params))
+
+		for k, v := range params {
+			converted[k] = v
+		}
+
+		return converted
+	}
+
+	return nil
+}
+
+func formatTemplate(template string, params map[string]interface{}) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+
+	replacements := make([]string, 0, 2*len(params))
+
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", fmt.Sprint(value))
+	}
+
+	return strings.NewReplacer(replacements...).Replace(template)
+}
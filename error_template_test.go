@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Error templates", func() {
+	tooShort := ErrorTemplate{
+		Status: "422",
+		Code:   "too_short",
+		Title:  "is too short",
+		Detail: "must be at least {min} characters, got {actual}",
+	}
+
+	It("creates error object carrying params in meta", func() {
+		eo := tooShort.New(map[string]interface{}{"min": 8, "actual": 5})
+
+		Ω(eo.Status).Should(Equal("422"))
+		Ω(eo.Code).Should(Equal("too_short"))
+		Ω(eo.Detail).Should(Equal("must be at least {min} characters, got {actual}"))
+		Ω(eo.Meta).Should(Equal(map[string]interface{}{ParamsMeta: map[string]interface{}{"min": 8, "actual": 5}}))
+	})
+
+	It("formats title and detail on Marshal", func() {
+		eo := tooShort.New(map[string]interface{}{"min": 8, "actual": 5})
+		eo.Source.Pointer = "/data/attributes/password"
+
+		result, err := Marshal(ErrorsView{ValidationErrors: []*ErrorObject{eo}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"errors": [{
+			"status": "422",
+			"code": "too_short",
+			"title": "is too short",
+			"detail": "must be at least 8 characters, got 5",
+			"source": {"pointer": "/data/attributes/password"},
+			"meta": {"params": {"min": 8, "actual": 5}}
+		}]}`))
+		Ω(eo.Detail).Should(Equal("must be at least {min} characters, got {actual}"))
+	})
+
+	It("formats translated templates", func() {
+		translator := ErrorTranslatorFunc(func(locale, code, member, value string) (string, bool) {
+			if member == "detail" {
+				return "mindestens {min} Zeichen", true
+			}
+
+			return "", false
+		})
+
+		result, err := Marshal(ErrorsView{ValidationErrors: []*ErrorObject{tooShort.New(map[string]interface{}{"min": 8})}}, WithErrorTranslator("de", translator))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"errors": [{
+			"status": "422",
+			"code": "too_short",
+			"title": "is too short",
+			"detail": "mindestens 8 Zeichen",
+			"source": {},
+			"meta": {"params": {"min": 8}}
+		}]}`))
+	})
+
+	It("keeps placeholders without params", func() {
+		formatted := FormatErrors([]*ErrorObject{
+			{Detail: "must be at least {min} characters, got {actual}", Meta: map[string]interface{}{ParamsMeta: map[string]string{"min": "8"}}},
+			tooShort.New(nil),
+		})
+
+		Ω(formatted[0].Detail).Should(Equal("must be at least 8 characters, got {actual}"))
+		Ω(formatted[1].Detail).Should(Equal("must be at least {min} characters, got {actual}"))
+	})
+
+	It("formats title and detail on WriteErrors", func() {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/users", nil)
+
+		err := WriteErrors(w, r, []*ErrorObject{tooShort.New(map[string]interface{}{"min": 8, "actual": 5})})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(w.Body.String()).Should(ContainSubstring("must be at least 8 characters, got 5"))
+	})
+})
@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+// ErrorTranslator interface should be implemented to localize error objects title and detail for the request locale,
+// keyed by the error object code. Member is either "title" or "detail", value is its untranslated text.
+// TranslateError returns false when there is no translation, the member keeps its value then.
+// Error objects without code aren't translated.
+//
+// TranslateError example:
+//
+//	func(c Catalog) TranslateError(locale, code, member, value string) (string, bool) {
+//	  translated, ok := c[locale][code+"."+member]
+//	  return translated, ok
+//	}
+type ErrorTranslator interface {
+	TranslateError(locale, code, member, value string) (string, bool)
+}
+
+// ErrorTranslatorFunc is an adapter to allow the use of ordinary functions as ErrorTranslator.
+type ErrorTranslatorFunc func(locale, code, member, value string) (string, bool)
+
+// TranslateError calls f(locale, code, member, value).
+func (f ErrorTranslatorFunc) TranslateError(locale, code, member, value string) (string, bool) {
+	return f(locale, code, member, value)
+}
+
+// TranslateErrors returns copies of error objects with title and detail localized for the locale,
+// e.g. before WriteErrors with the locale taken by LocaleFromContext. The given error objects are not changed.
+func TranslateErrors(errs []*ErrorObject, locale string, translator ErrorTranslator) []*ErrorObject {
+	if translator == nil || len(locale) == 0 || len(errs) == 0 {
+		return errs
+	}
+
+	translated := make([]*ErrorObject, 0, len(errs))
+
+	for _, err := range errs {
+		if err == nil || len(err.Code) == 0 {
+			translated = append(translated, err)
+			continue
+		}
+
+		copied := *err
+
+		if title, ok := translator.TranslateError(locale, err.Code, "title", err.Title); ok {
+			copied.Title = title
+		}
+
+		if detail, ok := translator.TranslateError(locale, err.Code, "detail", err.Detail); ok {
+			copied.Detail = detail
+		}
+
+		translated = append(translated, &copied)
+	}
+
+	return translated
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Error translator", func() {
+	catalog := map[string]map[string]string{
+		"de": {
+			"blank.title":  "darf nicht leer sein",
+			"blank.detail": "Der Titel darf nicht leer sein.",
+			"taken.title":  "ist bereits vergeben",
+		},
+	}
+
+	translator := ErrorTranslatorFunc(func(locale, code, member, value string) (string, bool) {
+		translated, ok := catalog[locale][code+"."+member]
+		return translated, ok
+	})
+
+	errs := func() []*ErrorObject {
+		return []*ErrorObject{
+			{Status: "422", Code: "blank", Title: "can't be blank", Detail: "Title can't be blank."},
+			{Status: "422", Code: "taken", Title: "has already been taken", Detail: "Slug has already been taken."},
+			{Status: "500", Title: "internal error"},
+		}
+	}
+
+	It("translates error objects title and detail by code", func() {
+		result, err := Marshal(ErrorsView{ValidationErrors: errs()}, WithErrorTranslator("de", translator))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"errors": [
+			{"status": "422", "code": "blank", "title": "darf nicht leer sein", "detail": "Der Titel darf nicht leer sein.", "source": {}},
+			{"status": "422", "code": "taken", "title": "ist bereits vergeben", "detail": "Slug has already been taken.", "source": {}},
+			{"status": "500", "title": "internal error", "source": {}}
+		]}`))
+	})
+
+	It("doesn't translate error objects without locale", func() {
+		result, err := Marshal(ErrorsView{ValidationErrors: errs()}, WithErrorTranslator("", translator))
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		expected, err := Marshal(ErrorsView{ValidationErrors: errs()})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(expected))
+	})
+
+	It("takes locale from context", func() {
+		ctx := WithLocale(context.Background(), "de")
+
+		result, err := MarshalContext(ctx, ErrorsView{ValidationErrors: errs()[:1]}, WithErrorTranslator("", translator))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"errors": [
+			{"status": "422", "code": "blank", "title": "darf nicht leer sein", "detail": "Der Titel darf nicht leer sein.", "source": {}}
+		]}`))
+	})
+
+	It("doesn't change the given error objects", func() {
+		given := errs()
+
+		translated := TranslateErrors(given, "de", translator)
+
+		Ω(translated[0].Title).Should(Equal("darf nicht leer sein"))
+		Ω(given[0].Title).Should(Equal("can't be blank"))
+		Ω(translated[2]).Should(BeIdenticalTo(given[2]))
+	})
+})
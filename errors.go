@@ -0,0 +1,267 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AttributeError describes invalid resource attribute value found during Unmarshal.
+type AttributeError struct {
+	// Pointer a JSON Pointer to the attribute in the document, e.g. "/data/attributes/published-at".
+	Pointer string
+	// Err the underlying error.
+	Err error
+}
+
+// Error returns error message.
+func (e *AttributeError) Error() string {
+	return fmt.Sprintf("jsonapi: invalid attribute %s: %v", e.Pointer, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *AttributeError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorObject returns JSON API error object describing the error, with source pointer to the attribute.
+func (e *AttributeError) ErrorObject() *ErrorObject {
+	return &ErrorObject{
+		Status: strconv.Itoa(http.StatusBadRequest),
+		Title:  "Invalid attribute",
+		Detail: e.Err.Error(),
+		Code:   "invalid_attribute",
+		Source: ErrorObjectSource{Pointer: e.Pointer},
+	}
+}
+
+// MarshalError describes failure of marshaling a resource object, e.g. of its attribute value MarshalJSON,
+// telling which of the document resources is malformed.
+type MarshalError struct {
+	// Type the resource type.
+	Type string
+	// ID the resource ID, it may be empty for resources being created.
+	ID string
+	// Member a JSON Pointer to the resource object member being marshaled, e.g. "/attributes" or "/relationships/author".
+	Member string
+	// Err the underlying error.
+	Err error
+}
+
+// Error returns error message.
+func (e *MarshalError) Error() string {
+	return fmt.Sprintf("jsonapi: marshal %s %q %s: %v", e.Type, e.ID, e.Member, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *MarshalError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalErrorDecorator interface may be implemented by resources to decorate their marshal errors,
+// e.g. to add the resource specific context or to map them to domain errors.
+//
+// DecorateMarshalError example:
+//
+//	func(s SomeStruct) DecorateMarshalError(err *jsonapi.MarshalError) error {
+//	  return fmt.Errorf("%w (tenant %s)", err, s.TenantID)
+//	}
+type MarshalErrorDecorator interface {
+	DecorateMarshalError(err *MarshalError) error
+}
+
+// marshalError returns MarshalError of the resource member, decorated by the resource when it is MarshalErrorDecorator.
+// The member of the error returned by the member marshaling is kept, when it is MarshalError already.
+func marshalError(mri MarshalResourceIdentifier, member string, err error) error {
+	me, ok := err.(*MarshalError)
+	if !ok || len(me.Type) > 0 {
+		me = &MarshalError{Member: member, Err: err}
+	}
+
+	me.Type, me.ID = mri.GetType(), mri.GetID()
+
+	if md, ok := unwrapResource(mri).(MarshalErrorDecorator); ok {
+		return md.DecorateMarshalError(me)
+	}
+
+	return me
+}
+
+// ValidationError returned by Unmarshal when the document fails validation hooks, e.g. Options.Existence.
+type ValidationError struct {
+	// Errors error objects describing the violations.
+	Errors []*ErrorObject
+}
+
+// Error returns error message.
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return "jsonapi: document is invalid: " + errorObjectMessage(e.Errors[0])
+	}
+
+	return fmt.Sprintf("jsonapi: document is invalid: %d errors", len(e.Errors))
+}
+
+// Error returns error message, so error objects may be returned as Go errors, e.g. by services to HTTP handlers.
+func (e *ErrorObject) Error() string {
+	message := errorObjectMessage(e)
+
+	// error objects identified by code only
+	if len(e.Title) == 0 && len(e.Detail) == 0 {
+		message += e.Code
+	}
+
+	if len(e.Status) > 0 {
+		return e.Status + " " + message
+	}
+
+	return message
+}
+
+// NewError returns error object of the HTTP status, e.g. http.StatusUnprocessableEntity.
+func NewError(status int, code, title, detail string) *ErrorObject {
+	return &ErrorObject{
+		Status: strconv.Itoa(status),
+		Code:   code,
+		Title:  title,
+		Detail: detail,
+	}
+}
+
+// errorObjecter is implemented by errors describing themselves as error object, e.g. AttributeError.
+type errorObjecter interface {
+	ErrorObject() *ErrorObject
+}
+
+// ErrorFromErr returns error object describing err, so any error returned to HTTP handler may be written as error document.
+// Error objects and Errors are returned as is, the first one of Errors and ValidationError,
+// AttributeError, malformed and too large documents and unsupported media type describe the client errors,
+// other errors are 500 error objects not disclosing their messages. It returns nil when err is nil.
+func ErrorFromErr(err error) *ErrorObject {
+	if err == nil {
+		return nil
+	}
+
+	var eo *ErrorObject
+	if errors.As(err, &eo) && eo != nil {
+		return eo
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) && len(validationErr.Errors) > 0 && validationErr.Errors[0] != nil {
+		return validationErr.Errors[0]
+	}
+
+	var objecter errorObjecter
+	if errors.As(err, &objecter) {
+		return objecter.ErrorObject()
+	}
+
+	var (
+		syntaxErr    *json.SyntaxError
+		unmarshalErr *json.UnmarshalTypeError
+	)
+
+	if errors.As(err, &syntaxErr) || errors.As(err, &unmarshalErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return NewError(http.StatusBadRequest, "malformed_document", "Malformed document", err.Error())
+	}
+
+	if errors.Is(err, ErrUnsupportedMediaType) {
+		return NewError(http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type", err.Error())
+	}
+
+	if errors.Is(err, ErrDocumentTooLarge) {
+		return NewError(http.StatusRequestEntityTooLarge, "document_too_large", "Document is too large", "")
+	}
+
+	return NewError(http.StatusInternalServerError, "", http.StatusText(http.StatusInternalServerError), "")
+}
+
+// Errors aggregates error objects as Go error, it implements MarshalErrors, so it may be marshaled as errors document.
+// errors.As finds the first error object of Errors.
+//
+// Errors example:
+//
+//	func (s *Service) CreateUser(u *User) error {
+//	  var errs jsonapi.Errors
+//
+//	  if len(u.Email) == 0 {
+//	    errs = append(errs, &jsonapi.ErrorObject{Status: "422", Title: "can't be blank", Source: jsonapi.ErrorObjectSource{Pointer: "/data/attributes/email"}})
+//	  }
+//
+//	  if len(errs) > 0 {
+//	    return errs
+//	  }
+//
+//	  return s.store.Insert(u)
+//	}
+type Errors []*ErrorObject
+
+// Error returns error message.
+func (errs Errors) Error() string {
+	messages := make([]string, 0, len(errs))
+
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// GetErrors returns the error objects, see MarshalErrors.
+func (errs Errors) GetErrors() []*ErrorObject {
+	return errs
+}
+
+// As sets target of *ErrorObject type to the first error object.
+func (errs Errors) As(target interface{}) bool {
+	eo, ok := target.(**ErrorObject)
+	if !ok {
+		return false
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			*eo = err
+			return true
+		}
+	}
+
+	return false
+}
+
+// Is reports whether any error object matches target, so errors.Is finds every error object,
+// not only the first one As sets.
+func (errs Errors) Is(target error) bool {
+	for _, err := range errs {
+		if err != nil && errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func errorObjectMessage(err *ErrorObject) string {
+	message := err.Title
+
+	if len(err.Detail) > 0 {
+		message = err.Detail
+	}
+
+	if len(err.Source.Pointer) > 0 {
+		message = err.Source.Pointer + ": " + message
+	}
+
+	return message
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var errMalformedPart = errors.New("malformed part")
+
+type Part struct {
+	Valid bool
+}
+
+func (p Part) MarshalJSON() ([]byte, error) {
+	if !p.Valid {
+		return nil, errMalformedPart
+	}
+
+	return []byte(`"part"`), nil
+}
+
+type Gadget struct {
+	ID     string `json:"-"`
+	Part   Part   `json:"part"`
+	Tenant string `json:"-"`
+}
+
+func (g Gadget) GetID() string {
+	return g.ID
+}
+
+func (g Gadget) GetType() string {
+	return "gadgets"
+}
+
+type DecoratedGadget struct {
+	Gadget
+}
+
+func (g DecoratedGadget) DecorateMarshalError(err *MarshalError) error {
+	return fmt.Errorf("tenant %s: %w", g.Tenant, err)
+}
+
+type GadgetsView []interface{}
+
+func (v GadgetsView) GetData() interface{} {
+	return []interface{}(v)
+}
+
+var _ = Describe("MarshalError", func() {
+
+	It("tells which resource member failed", func() {
+		view := GadgetsView{Gadget{ID: "1", Part: Part{Valid: true}}, Gadget{ID: "2"}}
+
+		_, err := Marshal(view)
+
+		var marshalErr *MarshalError
+		Ω(errors.As(err, &marshalErr)).Should(BeTrue())
+		Ω(marshalErr.Type).Should(Equal("gadgets"))
+		Ω(marshalErr.ID).Should(Equal("2"))
+		Ω(marshalErr.Member).Should(Equal("/attributes"))
+		Ω(errors.Is(err, errMalformedPart)).Should(BeTrue())
+		Ω(err.Error()).Should(HavePrefix(`jsonapi: marshal gadgets "2" /attributes: `))
+	})
+
+	It("tells which relationship failed", func() {
+		view := BookWithAuthorView{Book: BookWithAuthor{Book: Book{ID: "1", Type: "books"}}}
+
+		_, err := MarshalWithOptions(view, Options{EmptyID: EmptyIDError})
+
+		var marshalErr *MarshalError
+		Ω(errors.As(err, &marshalErr)).Should(BeTrue())
+		Ω(marshalErr.Type).Should(Equal("books"))
+		Ω(marshalErr.ID).Should(Equal("1"))
+		Ω(marshalErr.Member).Should(Equal("/relationships/author"))
+		Ω(errors.Is(err, ErrEmptyID)).Should(BeTrue())
+	})
+
+	It("is decorated by the resource", func() {
+		_, err := Marshal(GadgetsView{DecoratedGadget{Gadget{ID: "1", Tenant: "acme"}}})
+
+		var marshalErr *MarshalError
+		Ω(errors.As(err, &marshalErr)).Should(BeTrue())
+		Ω(err.Error()).Should(HavePrefix(`tenant acme: jsonapi: marshal gadgets "1" /attributes: `))
+	})
+})
+
+var _ = Describe("Error objects as errors", func() {
+	blank := &ErrorObject{Status: "422", Title: "can't be blank", Source: ErrorObjectSource{Pointer: "/data/attributes/email"}}
+	taken := &ErrorObject{Status: "409", Code: "taken"}
+
+	It("returns error object message", func() {
+		var err error = blank
+
+		Ω(err.Error()).Should(Equal("422 /data/attributes/email: can't be blank"))
+		Ω(taken.Error()).Should(Equal("409 taken"))
+		Ω((&ErrorObject{Title: "is invalid", Detail: "Email is invalid."}).Error()).Should(Equal("Email is invalid."))
+	})
+
+	It("returns aggregated errors message", func() {
+		err := Errors{blank, nil, taken}
+
+		Ω(err.Error()).Should(Equal("422 /data/attributes/email: can't be blank; 409 taken"))
+	})
+
+	It("finds the first error object of wrapped aggregated errors", func() {
+		err := fmt.Errorf("create user: %w", Errors{nil, blank, taken})
+
+		var eo *ErrorObject
+
+		Ω(errors.As(err, &eo)).Should(BeTrue())
+		Ω(eo).Should(BeIdenticalTo(blank))
+		Ω(errors.Is(err, taken)).Should(BeTrue())
+	})
+
+	It("finds aggregated errors", func() {
+		err := fmt.Errorf("create user: %w", Errors{blank})
+
+		var errs Errors
+
+		Ω(errors.As(err, &errs)).Should(BeTrue())
+		Ω(errs).Should(HaveLen(1))
+	})
+
+	It("marshals aggregated errors as errors document", func() {
+		result, err := Marshal(Errors{blank, taken})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"errors": [
+			{"status": "422", "title": "can't be blank", "source": {"pointer": "/data/attributes/email"}},
+			{"status": "409", "code": "taken", "source": {}}
+		]}`))
+	})
+})
@@ -0,0 +1,220 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package bookstore is the reference JSON API service served by examples/server and consumed by examples/client.
+// It parses query parameters, sorts and paginates collections, includes related resources and reports errors.
+package bookstore
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pieoneers/jsonapi-go"
+)
+
+// Author is an "authors" resource.
+type Author struct {
+	ID   string `json:"-"`
+	Name string `json:"name"`
+}
+
+// GetID returns the author ID.
+func (a Author) GetID() string {
+	return a.ID
+}
+
+// GetType returns "authors" resource type.
+func (a Author) GetType() string {
+	return "authors"
+}
+
+// Book is a "books" resource related to its author.
+type Book struct {
+	ID       string `json:"-"`
+	Title    string `json:"title"`
+	Year     int    `json:"year"`
+	AuthorID string `json:"-"`
+}
+
+// GetID returns the book ID.
+func (b Book) GetID() string {
+	return b.ID
+}
+
+// GetType returns "books" resource type.
+func (b Book) GetType() string {
+	return "books"
+}
+
+// GetRelationships returns the book author linkage.
+func (b Book) GetRelationships() map[string]interface{} {
+	return map[string]interface{}{
+		"author": jsonapi.ResourceObjectIdentifier{ID: b.AuthorID, Type: "authors"},
+	}
+}
+
+// Store is in-memory collection of books and their authors.
+type Store struct {
+	Books   []Book
+	Authors map[string]Author
+}
+
+// NewStore returns store populated with sample books.
+func NewStore() *Store {
+	return &Store{
+		Books: []Book{
+			{ID: "1", Title: "The Go Programming Language", Year: 2015, AuthorID: "1"},
+			{ID: "2", Title: "The C Programming Language", Year: 1978, AuthorID: "2"},
+			{ID: "3", Title: "The Practice of Programming", Year: 1999, AuthorID: "2"},
+			{ID: "4", Title: "The UNIX Programming Environment", Year: 1984, AuthorID: "3"},
+			{ID: "5", Title: "Go in Action", Year: 2015, AuthorID: "4"},
+		},
+		Authors: map[string]Author{
+			"1": {ID: "1", Name: "Alan Donovan"},
+			"2": {ID: "2", Name: "Brian Kernighan"},
+			"3": {ID: "3", Name: "Rob Pike"},
+			"4": {ID: "4", Name: "William Kennedy"},
+		},
+	}
+}
+
+// PageConfig pagination of the books collection.
+var PageConfig = jsonapi.PageConfig{DefaultSize: 2, MaxSize: 10}
+
+// includable relationship paths of books.
+var includable = map[string]bool{"author": true}
+
+type bookView struct {
+	Data     Book
+	Included []interface{}
+}
+
+func (v bookView) GetData() interface{} {
+	return v.Data
+}
+
+func (v bookView) GetIncluded() []interface{} {
+	return v.Included
+}
+
+// NewHandler returns HTTP handler serving the store books:
+//
+//	GET /books?include=author&fields[books]=title&sort=-year&page[number]=2&page[size]=2
+//	GET /books/1?include=author
+func NewHandler(store *Store) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/books", func(w http.ResponseWriter, r *http.Request) {
+		listBooks(store, w, r)
+	})
+
+	mux.HandleFunc("/books/", func(w http.ResponseWriter, r *http.Request) {
+		showBook(store, w, r, strings.TrimPrefix(r.URL.Path, "/books/"))
+	})
+
+	return mux
+}
+
+func listBooks(store *Store, w http.ResponseWriter, r *http.Request) {
+	params, errs := parseQuery(r)
+	if len(errs) > 0 {
+		_ = jsonapi.WriteErrors(w, r, errs)
+		return
+	}
+
+	books := append([]Book{}, store.Books...)
+
+	if errs := jsonapi.ApplySort(books, params.Sort, nil); len(errs) > 0 {
+		_ = jsonapi.WriteErrors(w, r, errs)
+		return
+	}
+
+	page, errs := jsonapi.ParsePage(params.Page, PageConfig)
+	if len(errs) > 0 {
+		_ = jsonapi.WriteErrors(w, r, errs)
+		return
+	}
+
+	start, end := page.Offset, page.Offset+page.Limit
+
+	if start > len(books) {
+		start = len(books)
+	}
+
+	if end > len(books) {
+		end = len(books)
+	}
+
+	view := jsonapi.Paginate(books[start:end], page, len(books), r.URL)
+	view.Included = store.authorsOf(books[start:end]...)
+
+	write(w, r, view, params)
+}
+
+func showBook(store *Store, w http.ResponseWriter, r *http.Request, id string) {
+	params, errs := parseQuery(r)
+	if len(errs) > 0 {
+		_ = jsonapi.WriteErrors(w, r, errs)
+		return
+	}
+
+	for _, book := range store.Books {
+		if book.ID == id {
+			write(w, r, bookView{Data: book, Included: store.authorsOf(book)}, params)
+			return
+		}
+	}
+
+	_ = jsonapi.WriteErrors(w, r, []*jsonapi.ErrorObject{{
+		Status: "404",
+		Title:  "Not Found",
+		Detail: "Book " + id + " does not exist.",
+	}})
+}
+
+func parseQuery(r *http.Request) (*jsonapi.QueryParams, []*jsonapi.ErrorObject) {
+	if r.Method != http.MethodGet {
+		return nil, []*jsonapi.ErrorObject{{Status: "405", Title: "Method Not Allowed"}}
+	}
+
+	params, errs := jsonapi.ParseQuery(r.URL.Query())
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	for _, path := range params.Include {
+		if !includable[path] {
+			errs = append(errs, &jsonapi.ErrorObject{
+				Status: "400",
+				Title:  "Invalid query parameter",
+				Detail: "Relationship path " + path + " can't be included.",
+				Source: jsonapi.ErrorObjectSource{Parameter: "include"},
+			})
+		}
+	}
+
+	return params, errs
+}
+
+func write(w http.ResponseWriter, r *http.Request, view interface{}, params *jsonapi.QueryParams) {
+	err := jsonapi.Write(w, http.StatusOK, view, jsonapi.WithInclude(params.Include...), jsonapi.WithFields(params.Fields))
+	if err != nil {
+		_ = jsonapi.WriteErrors(w, r, []*jsonapi.ErrorObject{jsonapi.ErrorFromErr(err)})
+	}
+}
+
+func (s *Store) authorsOf(books ...Book) []interface{} {
+	var authors []interface{}
+
+	seen := map[string]bool{}
+
+	for _, book := range books {
+		if author, ok := s.Authors[book.AuthorID]; ok && !seen[author.ID] {
+			seen[author.ID] = true
+			authors = append(authors, author)
+		}
+	}
+
+	return authors
+}
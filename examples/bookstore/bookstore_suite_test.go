@@ -0,0 +1,17 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bookstore_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBookstore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bookstore Suite")
+}
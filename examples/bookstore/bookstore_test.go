@@ -0,0 +1,120 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bookstore_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pieoneers/jsonapi-go"
+	"github.com/pieoneers/jsonapi-go/examples/bookstore"
+)
+
+var _ = Describe("Bookstore", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		handler = bookstore.NewHandler(bookstore.NewStore())
+	})
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+		return rec
+	}
+
+	It("serves the first page of books with pagination links and meta", func() {
+		rec := get("/books")
+
+		Ω(rec.Code).Should(Equal(http.StatusOK))
+		Ω(rec.Header().Get("Content-Type")).Should(Equal(jsonapi.ContentType))
+		Ω(rec.Body.String()).Should(MatchJSON(`
+			{
+				"data": [
+					{"type": "books", "id": "1", "attributes": {"title": "The Go Programming Language", "year": 2015}, "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}},
+					{"type": "books", "id": "2", "attributes": {"title": "The C Programming Language", "year": 1978}, "relationships": {"author": {"data": {"type": "authors", "id": "2"}}}}
+				],
+				"meta": {"total": 5, "per_page": 2, "current_page": 1, "total_pages": 3},
+				"links": {
+					"self": "/books?page%5Bnumber%5D=1&page%5Bsize%5D=2",
+					"first": "/books?page%5Bnumber%5D=1&page%5Bsize%5D=2",
+					"next": "/books?page%5Bnumber%5D=2&page%5Bsize%5D=2",
+					"last": "/books?page%5Bnumber%5D=3&page%5Bsize%5D=2"
+				}
+			}
+		`))
+	})
+
+	It("sorts, paginates, includes authors and applies sparse fieldsets", func() {
+		rec := get("/books?sort=year&page[number]=2&page[size]=2&include=author&fields[books]=title")
+
+		Ω(rec.Code).Should(Equal(http.StatusOK))
+
+		doc, books, err := jsonapi.UnmarshalGeneric(rec.Body.Bytes())
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(books).Should(HaveLen(2))
+		Ω(books[0].AttributeNames()).Should(Equal([]string{"title"}))
+
+		title, ok := books[0].GetString("title")
+		Ω(ok).Should(BeTrue())
+		Ω(title).Should(Equal("The Practice of Programming"))
+
+		title, ok = books[1].GetString("title")
+		Ω(ok).Should(BeTrue())
+		Ω(title).Should(Equal("The Go Programming Language"))
+
+		Ω(doc.Included).Should(HaveLen(2))
+		Ω(doc.Included[0].ID).Should(Equal("2"))
+		Ω(doc.Included[1].ID).Should(Equal("1"))
+		Ω(doc.Links).Should(HaveKey("prev"))
+		Ω(doc.Links).Should(HaveKey("next"))
+	})
+
+	It("serves a single book with its author", func() {
+		rec := get("/books/4?include=author")
+
+		Ω(rec.Code).Should(Equal(http.StatusOK))
+		Ω(rec.Body.String()).Should(MatchJSON(`
+			{
+				"data": {"type": "books", "id": "4", "attributes": {"title": "The UNIX Programming Environment", "year": 1984}, "relationships": {"author": {"data": {"type": "authors", "id": "3"}}}},
+				"included": [{"type": "authors", "id": "3", "attributes": {"name": "Rob Pike"}}]
+			}
+		`))
+	})
+
+	It("responds with not found error of a missing book", func() {
+		rec := get("/books/42")
+
+		Ω(rec.Code).Should(Equal(http.StatusNotFound))
+		Ω(rec.Body.String()).Should(MatchJSON(`{"errors": [{"status": "404", "title": "Not Found", "detail": "Book 42 does not exist.", "source": {}}]}`))
+	})
+
+	It("responds with bad request errors of invalid query parameters", func() {
+		rec := get("/books?include=publisher&sort=-isbn")
+
+		Ω(rec.Code).Should(Equal(http.StatusBadRequest))
+
+		doc, _, err := jsonapi.UnmarshalGeneric(rec.Body.Bytes())
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(doc.Errors).Should(HaveLen(1))
+		Ω(doc.Errors[0].Source.Parameter).Should(Equal("include"))
+
+		rec = get("/books?sort=-isbn")
+
+		Ω(rec.Code).Should(Equal(http.StatusBadRequest))
+		Ω(rec.Body.String()).Should(ContainSubstring(`"parameter":"sort"`))
+
+		rec = get("/books?page[size]=0")
+
+		Ω(rec.Code).Should(Equal(http.StatusBadRequest))
+		Ω(rec.Body.String()).Should(ContainSubstring(`"parameter":"page[size]"`))
+	})
+})
@@ -0,0 +1,174 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Command client lists all bookstore books page by page following pagination next links:
+//
+//	go run ./examples/client -url 'http://localhost:8080/books?include=author&sort=-year'
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/pieoneers/jsonapi-go"
+)
+
+// Book is a "books" resource as the client sees it.
+type Book struct {
+	ID       string `json:"-"`
+	Title    string `json:"title"`
+	Year     int    `json:"year"`
+	AuthorID string `json:"-"`
+	// Author name resolved from the included authors.
+	Author string `json:"-"`
+}
+
+// SetID sets the book ID.
+func (b *Book) SetID(id string) error {
+	b.ID = id
+	return nil
+}
+
+// SetType checks the resource is a book.
+func (b *Book) SetType(t string) error {
+	if t != "books" {
+		return fmt.Errorf("unexpected resource type %q", t)
+	}
+
+	return nil
+}
+
+// SetRelationships sets the book author ID.
+func (b *Book) SetRelationships(relationships map[string]interface{}) error {
+	if author, ok := jsonapi.ToOne(relationships, "author"); ok {
+		b.AuthorID = author.ID
+	}
+
+	return nil
+}
+
+// Books is a page of the books collection.
+type Books []Book
+
+// SetData unmarshals the page books.
+func (b *Books) SetData(to func(target interface{}) error) error {
+	return to(b)
+}
+
+// Client is the bookstore API client.
+type Client struct {
+	HTTPClient *http.Client
+}
+
+// ListBooks fetches the books collection at rawurl, following next links until the last page.
+func (c *Client) ListBooks(rawurl string) ([]Book, error) {
+	var books []Book
+
+	next, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	for next != nil {
+		page, links, err := c.fetchPage(next)
+		if err != nil {
+			return books, err
+		}
+
+		books = append(books, page...)
+
+		link, ok := links["next"]
+		if !ok {
+			break
+		}
+
+		href, err := url.Parse(link.Href)
+		if err != nil {
+			return books, err
+		}
+
+		next = next.ResolveReference(href)
+	}
+
+	return books, nil
+}
+
+func (c *Client) fetchPage(u *url.URL) ([]Book, jsonapi.Links, error) {
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set("Accept", jsonapi.ContentType)
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	var books Books
+
+	doc, err := jsonapi.UnmarshalReader(res.Body, &books)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(doc.Errors) > 0 {
+		return nil, nil, errorOf(res.StatusCode, doc.Errors)
+	}
+
+	authors := map[string]string{}
+
+	for _, ro := range doc.Included {
+		if ro.Type != "authors" {
+			continue
+		}
+
+		resource, err := jsonapi.ResourceOf(ro)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		authors[ro.ID], _ = resource.GetString("name")
+	}
+
+	for i := range books {
+		books[i].Author = authors[books[i].AuthorID]
+	}
+
+	return books, doc.Links, nil
+}
+
+func errorOf(status int, errs []*jsonapi.ErrorObject) error {
+	first := errs[0]
+
+	if len(first.Detail) > 0 {
+		return fmt.Errorf("bookstore: %d %s: %s", status, first.Title, first.Detail)
+	}
+
+	return fmt.Errorf("bookstore: %d %s", status, first.Title)
+}
+
+func main() {
+	rawurl := flag.String("url", "http://localhost:8080/books?include=author", "books collection URL")
+	flag.Parse()
+
+	books, err := (&Client{}).ListBooks(*rawurl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, book := range books {
+		fmt.Printf("%s (%d) by %s\n", book.Title, book.Year, book.Author)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/pieoneers/jsonapi-go/examples/bookstore"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server   *httptest.Server
+		client   *Client
+		requests int32
+	)
+
+	BeforeEach(func() {
+		atomic.StoreInt32(&requests, 0)
+
+		handler := bookstore.NewHandler(bookstore.NewStore())
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			handler.ServeHTTP(w, r)
+		}))
+
+		client = &Client{HTTPClient: server.Client()}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("lists all books following next links", func() {
+		books, err := client.ListBooks(server.URL + "/books?include=author&sort=-year,title")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(atomic.LoadInt32(&requests)).Should(Equal(int32(3)))
+		Ω(books).Should(Equal([]Book{
+			{ID: "5", Title: "Go in Action", Year: 2015, AuthorID: "4", Author: "William Kennedy"},
+			{ID: "1", Title: "The Go Programming Language", Year: 2015, AuthorID: "1", Author: "Alan Donovan"},
+			{ID: "3", Title: "The Practice of Programming", Year: 1999, AuthorID: "2", Author: "Brian Kernighan"},
+			{ID: "4", Title: "The UNIX Programming Environment", Year: 1984, AuthorID: "3", Author: "Rob Pike"},
+			{ID: "2", Title: "The C Programming Language", Year: 1978, AuthorID: "2", Author: "Brian Kernighan"},
+		}))
+	})
+
+	It("starts from the requested page", func() {
+		books, err := client.ListBooks(server.URL + "/books?page[number]=2&page[size]=3")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(atomic.LoadInt32(&requests)).Should(Equal(int32(1)))
+		Ω(books).Should(HaveLen(2))
+		Ω(books[0].ID).Should(Equal("4"))
+		Ω(books[0].Author).Should(BeEmpty())
+	})
+
+	It("returns error objects as error", func() {
+		_, err := client.ListBooks(server.URL + "/books?include=publisher")
+
+		Ω(err).Should(MatchError("bookstore: 400 Invalid query parameter: Relationship path publisher can't be included."))
+	})
+})
@@ -0,0 +1,28 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Command server serves the bookstore JSON API:
+//
+//	go run ./examples/server -addr :8080
+//	curl 'http://localhost:8080/books?include=author&sort=-year&page[size]=2'
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/pieoneers/jsonapi-go"
+	"github.com/pieoneers/jsonapi-go/examples/bookstore"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	flag.Parse()
+
+	handler := jsonapi.Middleware()(bookstore.NewHandler(bookstore.NewStore()))
+
+	log.Printf("serving bookstore on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, handler))
+}
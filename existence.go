@@ -0,0 +1,147 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// ExistencePolicy validates that resources referenced by primary data relationships exist,
+// all referenced identifiers are checked at once, so the application can look them up in one batched query.
+//
+// ExistencePolicy example:
+//
+//	policy := jsonapi.ExistencePolicy{
+//	  Missing: func(ids []jsonapi.ResourceObjectIdentifier) ([]jsonapi.ResourceObjectIdentifier, error) {
+//	    return store.Missing(ids)
+//	  },
+//	  Status: http.StatusUnprocessableEntity,
+//	}
+//
+//	doc, err := jsonapi.Unmarshal(payload, &book)
+//	...
+//	errs, err := policy.Validate(doc)
+//	if len(errs) > 0 {
+//	  // respond with errs
+//	}
+//
+// The policy may be given as Options.Existence as well, Unmarshal returns ValidationError then.
+type ExistencePolicy struct {
+	// Missing returns those of the unique referenced identifiers which refer to resources that don't exist.
+	Missing func(identifiers []ResourceObjectIdentifier) ([]ResourceObjectIdentifier, error)
+	// Status HTTP status code of the returned error objects, 404 Not Found when it is zero.
+	Status int
+}
+
+// Validate checks resources referenced by document primary data relationships exist
+// and returns error objects with pointers to the linkage of the missing ones.
+// Linkage identified by lid only is skipped.
+func (p ExistencePolicy) Validate(doc *Document) ([]*ErrorObject, error) {
+	var errs []*ErrorObject
+
+	if doc == nil || doc.Data == nil || p.Missing == nil {
+		return errs, nil
+	}
+
+	type reference struct {
+		roi     ResourceObjectIdentifier
+		pointer string
+	}
+
+	var references []reference
+
+	collect := func(ro *ResourceObject, pointer string) {
+		names := make([]string, 0, len(ro.Relationships))
+
+		for name := range ro.Relationships {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			rel := ro.Relationships[name]
+			base := pointer + Pointer("relationships", name, "data")
+
+			if rel == nil || rel.Data == nil {
+				continue
+			}
+
+			if one := rel.Data.One; one != nil {
+				references = append(references, reference{roi: *one, pointer: base})
+			}
+
+			for i, one := range rel.Data.Many {
+				references = append(references, reference{roi: *one, pointer: fmt.Sprintf("%s/%d", base, i)})
+			}
+		}
+	}
+
+	if one := doc.Data.One; one != nil {
+		collect(one, "/data")
+	}
+
+	for i, one := range doc.Data.Many {
+		collect(one, CollectionPointer(i))
+	}
+
+	seen := map[ResourceObjectIdentifier]bool{}
+
+	var identifiers []ResourceObjectIdentifier
+
+	for _, ref := range references {
+		key := existenceKey(ref.roi)
+
+		if len(key.ID) == 0 || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		identifiers = append(identifiers, key)
+	}
+
+	if len(identifiers) == 0 {
+		return errs, nil
+	}
+
+	missing, err := p.Missing(identifiers)
+	if err != nil {
+		return errs, err
+	}
+
+	notFound := map[ResourceObjectIdentifier]bool{}
+
+	for _, roi := range missing {
+		notFound[existenceKey(roi)] = true
+	}
+
+	status := p.Status
+	if status == 0 {
+		status = http.StatusNotFound
+	}
+
+	for _, ref := range references {
+		if !notFound[existenceKey(ref.roi)] {
+			continue
+		}
+
+		errs = append(errs, &ErrorObject{
+			Status: strconv.Itoa(status),
+			Title:  "Related resource not found",
+			Detail: fmt.Sprintf("Resource of type %q with ID %q does not exist.", ref.roi.Type, ref.roi.ID),
+			Code:   "related_resource_not_found",
+			Source: ErrorObjectSource{Pointer: ref.pointer},
+		})
+	}
+
+	return errs, nil
+}
+
+func existenceKey(roi ResourceObjectIdentifier) ResourceObjectIdentifier {
+	return ResourceObjectIdentifier{Type: roi.Type, ID: roi.ID}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("ExistencePolicy", func() {
+
+	data := []byte(`
+    {
+      "data": [
+        {
+          "type": "books",
+          "id": "1",
+          "relationships": {
+            "readers": { "data": [{ "type": "people", "id": "1" }, { "type": "people", "id": "2" }] }
+          }
+        },
+        {
+          "type": "books",
+          "id": "2",
+          "relationships": {
+            "readers": { "data": [{ "type": "people", "id": "2" }, { "type": "people", "lid": "new" }] }
+          }
+        }
+      ]
+    }
+  `)
+
+	var calls [][]ResourceObjectIdentifier
+
+	policy := ExistencePolicy{
+		Missing: func(ids []ResourceObjectIdentifier) ([]ResourceObjectIdentifier, error) {
+			calls = append(calls, ids)
+
+			return []ResourceObjectIdentifier{{Type: "people", ID: "2"}}, nil
+		},
+		Status: http.StatusUnprocessableEntity,
+	}
+
+	BeforeEach(func() {
+		calls = nil
+	})
+
+	It("validates referenced resources exist in one call", func() {
+		doc, err := Unmarshal(data, &BooksWithReadersView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		errs, err := policy.Validate(doc)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(calls).Should(Equal([][]ResourceObjectIdentifier{{{Type: "people", ID: "1"}, {Type: "people", ID: "2"}}}))
+		Ω(errs).Should(HaveLen(2))
+		Ω(errs[0].Status).Should(Equal("422"))
+		Ω(errs[0].Source.Pointer).Should(Equal("/data/0/relationships/readers/data/1"))
+		Ω(errs[1].Source.Pointer).Should(Equal("/data/1/relationships/readers/data/0"))
+	})
+
+	It("returns validation error from Unmarshal", func() {
+		_, err := UnmarshalWithOptions(data, &BooksWithReadersView{}, Options{Existence: &policy})
+
+		var validationErr *ValidationError
+
+		Ω(errors.As(err, &validationErr)).Should(BeTrue())
+		Ω(validationErr.Errors).Should(HaveLen(2))
+		Ω(err).Should(MatchError("jsonapi: document is invalid: 2 errors"))
+	})
+
+	It("returns 404 errors by default", func() {
+		doc, err := Unmarshal(data, &BooksWithReadersView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		errs, err := ExistencePolicy{Missing: policy.Missing}.Validate(doc)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(errs[0].Status).Should(Equal("404"))
+	})
+})
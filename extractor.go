@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Extractor returns JSON API resource type and ID of the given value.
+type Extractor func(v interface{}) (typ string, id string)
+
+var extractors = struct {
+	sync.RWMutex
+	byType map[reflect.Type]Extractor
+}{
+	byType: map[reflect.Type]Extractor{},
+}
+
+// RegisterExtractor registers extractor for values of the same Go type as sample,
+// such values may be used as primary data, relationships and included without implementing MarshalResourceIdentifier.
+// It is intended for types you can't modify, e.g. generated models or vendored structs.
+//
+// RegisterExtractor example:
+//
+//	jsonapi.RegisterExtractor(models.User{}, func(v interface{}) (string, string) {
+//	  return "users", strconv.Itoa(v.(models.User).ID)
+//	})
+func RegisterExtractor(sample interface{}, extractor Extractor) {
+	extractors.Lock()
+	defer extractors.Unlock()
+
+	extractors.byType[reflect.TypeOf(sample)] = extractor
+}
+
+func lookupExtractor(v interface{}) (Extractor, interface{}, bool) {
+	extractors.RLock()
+	defer extractors.RUnlock()
+
+	for _, candidate := range lookupCandidates(v) {
+		if extractor, ok := extractors.byType[reflect.TypeOf(candidate)]; ok {
+			return extractor, candidate, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// lookupCandidates returns values which registries are looked up by: the value itself and the value it points to.
+func lookupCandidates(v interface{}) []interface{} {
+	candidates := []interface{}{v}
+
+	if value := reflect.ValueOf(v); value.Kind() == reflect.Ptr && !value.IsNil() {
+		candidates = append(candidates, value.Elem().Interface())
+	}
+
+	return candidates
+}
+
+func resourceIdentifier(v interface{}) (MarshalResourceIdentifier, error) {
+	if mri, ok := v.(MarshalResourceIdentifier); ok {
+		return mri, nil
+	}
+
+	if adapter, value, ok := lookupAdapter(v); ok {
+		return adaptedResource{value: value, adapter: adapter}, nil
+	}
+
+	if extractor, value, ok := lookupExtractor(v); ok {
+		typ, id := extractor(value)
+
+		return extractedResource{value: value, typ: typ, id: id}, nil
+	}
+
+	if tr, ok := newTaggedResource(v); ok {
+		return tr, nil
+	}
+
+	return nil, fmt.Errorf("jsonapi: %T does not implement MarshalResourceIdentifier", v)
+}
+
+type extractedResource struct {
+	value interface{}
+	typ   string
+	id    string
+}
+
+func (er extractedResource) GetID() string {
+	return er.id
+}
+
+func (er extractedResource) GetType() string {
+	return er.typ
+}
+
+func (er extractedResource) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(er.value)
+
+	return buf.Bytes(), err
+}
+
+func (er extractedResource) unwrap() interface{} {
+	return er.value
+}
+
+func (er extractedResource) rewrap(value interface{}) MarshalResourceIdentifier {
+	return extractedResource{value: value, typ: er.typ, id: er.id}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Publisher struct {
+	Code string `json:"-"`
+	Name string `json:"name"`
+}
+
+type BookWithPublisherView struct {
+	Book      BookWithPublisher `json:"-"`
+	Publisher Publisher         `json:"-"`
+}
+
+func (v BookWithPublisherView) GetData() interface{} {
+	return v.Book
+}
+
+func (v BookWithPublisherView) GetIncluded() []interface{} {
+	return []interface{}{&v.Publisher}
+}
+
+type BookWithPublisher struct {
+	Book
+	Publisher Publisher `json:"-"`
+}
+
+func (b BookWithPublisher) GetRelationships() map[string]interface{} {
+	return map[string]interface{}{
+		"publisher": b.Publisher,
+	}
+}
+
+var _ = Describe("RegisterExtractor", func() {
+
+	BeforeEach(func() {
+		RegisterExtractor(Publisher{}, func(v interface{}) (string, string) {
+			return "publishers", v.(Publisher).Code
+		})
+	})
+
+	It("marshals relationships and included of registered types", func() {
+		publisher := Publisher{Code: "apress", Name: "Apress"}
+
+		view := BookWithPublisherView{
+			Book: BookWithPublisher{
+				Book: Book{
+					ID:    "1",
+					Title: "An Introduction to Programming in Go",
+					Year:  "2012",
+					Type:  "books",
+				},
+				Publisher: publisher,
+			},
+			Publisher: publisher,
+		}
+
+		result, err := Marshal(view)
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "title": "An Introduction to Programming in Go",
+            "year": "2012"
+          },
+          "relationships": {
+            "publisher": {
+              "data": { "type": "publishers", "id": "apress" }
+            }
+          }
+        },
+        "included": [
+          {
+            "type": "publishers",
+            "id": "apress",
+            "attributes": {
+              "name": "Apress"
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("returns error for not registered types", func() {
+		_, err := Marshal(IncludedOnlyView{Included: []interface{}{Reader{ID: "1"}, struct{}{}}})
+
+		Ω(err).Should(MatchError("jsonapi: struct {} does not implement MarshalResourceIdentifier"))
+	})
+})
+
+type IncludedOnlyView struct {
+	Included []interface{}
+}
+
+func (v IncludedOnlyView) GetIncluded() []interface{} {
+	return v.Included
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+)
+
+// MarshalWithFields serialize Go struct into []byte JSON API document like Marshal does,
+// restricting attributes and relationships of each resource type to the requested sparse fieldset.
+// Resource types missing in fields are marshaled with all their fields.
+//
+// MarshalWithFields example:
+//
+//	params, _ := jsonapi.ParseQuery(req.URL.Query())
+//	payload, err := jsonapi.MarshalWithFields(view, params.Fields)
+func MarshalWithFields(payload interface{}, fields map[string][]string) ([]byte, error) {
+	return MarshalWithOptions(payload, Options{Fields: fields})
+}
+
+func filterDocumentFields(doc *Document, fields map[string][]string) error {
+	var resources []*ResourceObject
+
+	if doc.Data != nil {
+		if doc.Data.One != nil {
+			resources = append(resources, doc.Data.One)
+		}
+
+		resources = append(resources, doc.Data.Many...)
+	}
+
+	resources = append(resources, doc.Included...)
+
+	for _, ro := range resources {
+		if fieldset, ok := fields[ro.Type]; ok {
+			if err := filterFields(ro, fieldset); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func filterFields(ro *ResourceObject, fields []string) error {
+	allowed := make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+		allowed[field] = true
+	}
+
+	for key := range ro.Relationships {
+		if !allowed[key] {
+			delete(ro.Relationships, key)
+		}
+	}
+
+	if len(ro.Relationships) == 0 {
+		ro.Relationships = nil
+	}
+
+	if len(ro.Attributes) == 0 {
+		return nil
+	}
+
+	attributes, err := transformAttributes(ro.Attributes, func(members map[string]json.RawMessage) error {
+		for key := range members {
+			if !allowed[key] {
+				delete(members, key)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if isEmptyObject(attributes) {
+		attributes = nil
+	}
+
+	ro.Attributes = attributes
+
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("MarshalWithFields", func() {
+
+	view := BookWithAuthorIncludedView{
+		BookWithAuthorView: BookWithAuthorView{
+			Book: BookWithAuthor{
+				Book: Book{
+					ID:    "1",
+					Title: "An Introduction to Programming in Go",
+					Year:  "2012",
+					Type:  "books",
+				},
+				Author: Author{
+					ID:   "1",
+					Name: "Caleb Doxsey",
+				},
+			},
+		},
+	}
+
+	It("marshals requested fields only", func() {
+		result, err := MarshalWithFields(view, map[string][]string{
+			"books":   {"title"},
+			"authors": {},
+		})
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "title": "An Introduction to Programming in Go"
+          }
+        },
+        "included": [
+          {
+            "type": "authors",
+            "id": "1"
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("marshals all fields of resource types not given", func() {
+		result, err := MarshalWithFields(view, map[string][]string{
+			"books": {"year", "author"},
+		})
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "year": "2012"
+          },
+          "relationships": {
+            "author": {
+              "data": { "type": "authors", "id": "1" }
+            }
+          }
+        },
+        "included": [
+          {
+            "type": "authors",
+            "id": "1",
+            "attributes": {
+              "name": "Caleb Doxsey"
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+})
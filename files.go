@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FileLinkKind kind of the file link.
+type FileLinkKind string
+
+const (
+	// FileUpload link the file content is uploaded to, e.g. with PUT request.
+	FileUpload FileLinkKind = "upload"
+	// FileDownload link the file content is downloaded from.
+	FileDownload FileLinkKind = "download"
+)
+
+// MarshalFiles interface should be implemented to be able emit upload and download links of the resource files,
+// so binary content stays out of the document, see FileLinkSigner.
+// Links of a file named as one of the resource relationships are added to the relationship links,
+// the other ones are added to the resource links, e.g. "links": {"avatar-upload": "https://..."}.
+//
+// GetFiles example:
+//
+//	func(u User) GetFiles() map[string][]jsonapi.FileLinkKind {
+//	  return map[string][]jsonapi.FileLinkKind{
+//	    "avatar": {jsonapi.FileUpload, jsonapi.FileDownload},
+//	  }
+//	}
+type MarshalFiles interface {
+	GetFiles() map[string][]FileLinkKind
+}
+
+// FileLinkSigner interface should be implemented to supply the file links, usually pre-signed storage URLs.
+// SignFileLink returns nil link when the file has no such link, e.g. download link of not uploaded file.
+type FileLinkSigner interface {
+	SignFileLink(ctx context.Context, resource ResourceObjectIdentifier, file string, kind FileLinkKind) (*Link, error)
+}
+
+// FileLinkSignerFunc is an adapter to allow the use of ordinary functions as FileLinkSigner.
+type FileLinkSignerFunc func(ctx context.Context, resource ResourceObjectIdentifier, file string, kind FileLinkKind) (*Link, error)
+
+// SignFileLink calls f(ctx, resource, file, kind).
+func (f FileLinkSignerFunc) SignFileLink(ctx context.Context, resource ResourceObjectIdentifier, file string, kind FileLinkKind) (*Link, error) {
+	return f(ctx, resource, file, kind)
+}
+
+// FileLinkName returns name of the file link, e.g. "avatar-upload".
+func FileLinkName(file string, kind FileLinkKind) string {
+	return file + "-" + string(kind)
+}
+
+// FileLink returns the file link out of resource or relationship links, nil when there is no such link.
+func FileLink(links Links, file string, kind FileLinkKind) *Link {
+	return links[FileLinkName(file, kind)]
+}
+
+// PresignedLink returns link of pre-signed URL, which expiration time is given in the link meta,
+// e.g. {"href": "https://...", "meta": {"expires_at": "2020-05-01T10:00:00Z"}}.
+func PresignedLink(href string, expiresAt time.Time) *Link {
+	return &Link{Href: href, Meta: map[string]interface{}{"expires_at": expiresAt.UTC().Format(time.RFC3339)}}
+}
+
+// marshalFileLinks adds links of the resource files, signed by Options.FileLinks, to the resource object or its relationships.
+func marshalFileLinks(one *ResourceObject, mf MarshalFiles, opts *Options) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	files := mf.GetFiles()
+	names := make([]string, 0, len(files))
+
+	for file := range files {
+		names = append(names, file)
+	}
+
+	sort.Strings(names)
+
+	for _, file := range names {
+		for _, kind := range files[file] {
+			link, err := opts.FileLinks.SignFileLink(ctx, one.ResourceObjectIdentifier, file, kind)
+			if err != nil {
+				return fmt.Errorf("%w: %q file %s link", err, file, kind)
+			}
+
+			if link == nil {
+				continue
+			}
+
+			links := &one.Links
+
+			if rel, ok := one.Relationships[file]; ok && rel != nil {
+				links = &rel.Links
+			}
+
+			if *links == nil {
+				*links = Links{}
+			}
+
+			(*links)[FileLinkName(file, kind)] = link
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Profile struct {
+	ID   string `json:"-"`
+	Name string `json:"name"`
+}
+
+func (p Profile) GetID() string {
+	return p.ID
+}
+
+func (p Profile) GetType() string {
+	return "profiles"
+}
+
+func (p Profile) GetRelationships() map[string]interface{} {
+	return map[string]interface{}{
+		"resume": ResourceObjectIdentifier{Type: "documents", ID: "7"},
+	}
+}
+
+func (p Profile) GetFiles() map[string][]FileLinkKind {
+	return map[string][]FileLinkKind{
+		"avatar": {FileUpload, FileDownload},
+		"resume": {FileDownload},
+	}
+}
+
+type ProfileView struct {
+	Profile Profile
+}
+
+func (v ProfileView) GetData() interface{} {
+	return v.Profile
+}
+
+var _ = Describe("File links", func() {
+
+	expiresAt := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	signer := FileLinkSignerFunc(func(ctx context.Context, resource ResourceObjectIdentifier, file string, kind FileLinkKind) (*Link, error) {
+		if file == "avatar" && kind == FileDownload {
+			return nil, nil
+		}
+
+		return PresignedLink("https://storage.example.com/"+resource.Type+"/"+resource.ID+"/"+file+"?op="+string(kind), expiresAt), nil
+	})
+
+	It("adds signed links to resource and relationship links", func() {
+		result, err := Marshal(ProfileView{Profile: Profile{ID: "1", Name: "Ann"}}, WithFileLinks(signer))
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+
+		ro := doc.Data.One
+
+		Ω(ro.Links).Should(HaveLen(1))
+		Ω(FileLink(ro.Links, "avatar", FileUpload).Href).Should(Equal("https://storage.example.com/profiles/1/avatar?op=upload"))
+		Ω(FileLink(ro.Links, "avatar", FileUpload).Meta).Should(HaveKeyWithValue("expires_at", "2020-05-01T10:00:00Z"))
+		Ω(FileLink(ro.Links, "avatar", FileDownload)).Should(BeNil())
+
+		resume, err := json.Marshal(ro.Relationships["resume"])
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resume).Should(MatchJSON(`{
+			"data": {"type": "documents", "id": "7"},
+			"links": {"resume-download": {"href": "https://storage.example.com/profiles/1/resume?op=download", "meta": {"expires_at": "2020-05-01T10:00:00Z"}}}
+		}`))
+	})
+
+	It("doesn't add links without signer", func() {
+		result, err := Marshal(ProfileView{Profile: Profile{ID: "1", Name: "Ann"}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Data.One.Links).Should(BeEmpty())
+	})
+
+	It("fails when signing fails", func() {
+		failure := errors.New("storage unavailable")
+
+		_, err := Marshal(ProfileView{Profile: Profile{ID: "1"}}, WithFileLinks(FileLinkSignerFunc(func(context.Context, ResourceObjectIdentifier, string, FileLinkKind) (*Link, error) {
+			return nil, failure
+		})))
+
+		Ω(err).Should(MatchError(failure))
+		Ω(err.Error()).Should(ContainSubstring(`"avatar" file upload link`))
+	})
+})
@@ -0,0 +1,170 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FilterOperator describes filter condition comparison operator.
+type FilterOperator string
+
+// Filter operators, given as the last filter parameter segment, e.g. filter[year][gte]=2000.
+const (
+	FilterEq   FilterOperator = "eq"
+	FilterNe   FilterOperator = "ne"
+	FilterGt   FilterOperator = "gt"
+	FilterGte  FilterOperator = "gte"
+	FilterLt   FilterOperator = "lt"
+	FilterLte  FilterOperator = "lte"
+	FilterIn   FilterOperator = "in"
+	FilterNin  FilterOperator = "nin"
+	FilterLike FilterOperator = "like"
+)
+
+// FilterLogic describes how filter group children are combined.
+type FilterLogic string
+
+// Filter group logic, "or" may be given as the first filter parameter segment, e.g. filter[or][title]=Go.
+const (
+	FilterAnd FilterLogic = "and"
+	FilterOr  FilterLogic = "or"
+)
+
+var filterOperators = map[FilterOperator]bool{
+	FilterEq:   true,
+	FilterNe:   true,
+	FilterGt:   true,
+	FilterGte:  true,
+	FilterLt:   true,
+	FilterLte:  true,
+	FilterIn:   true,
+	FilterNin:  true,
+	FilterLike: true,
+}
+
+// FilterNode describes a node of the parsed filter tree, it is either a group of nodes or a condition.
+type FilterNode struct {
+	// Logic combines group children, empty for conditions.
+	Logic FilterLogic
+	// Children group nodes.
+	Children []*FilterNode
+	// Field condition field name, nested fields are dot-separated, e.g. filter[author][name] gives "author.name".
+	Field string
+	// Operator condition operator.
+	Operator FilterOperator
+	// Values condition values, comma-separated values are split.
+	Values []string
+}
+
+// FilterSchema describes filterable fields and operators allowed for each of them.
+type FilterSchema map[string][]FilterOperator
+
+// ParseFilter builds filter tree from filter query parameters:
+//
+//	filter[title]=Go                  title eq "Go"
+//	filter[id]=1,2,3                  id in ("1", "2", "3")
+//	filter[year][gte]=2000            year gte "2000"
+//	filter[author][name][like]=Don    author.name like "Don"
+//	filter[or][title]=Go&filter[or][year]=2015    (title eq "Go" or year eq "2015")
+//
+// The root node is "and" group. When schema is given, fields and operators missing in it are rejected.
+// The errors are returned as error objects with source parameter.
+func ParseFilter(params []FilterParam, schema FilterSchema) (*FilterNode, []*ErrorObject) {
+	var errs []*ErrorObject
+
+	root := &FilterNode{Logic: FilterAnd}
+
+	var or *FilterNode
+
+	for _, param := range params {
+		parameter := filterParameter(param.Path)
+
+		path := param.Path
+		group := root
+
+		if len(path) > 0 && FilterLogic(path[0]) == FilterOr {
+			if or == nil {
+				or = &FilterNode{Logic: FilterOr}
+				root.Children = append(root.Children, or)
+			}
+
+			group = or
+			path = path[1:]
+		} else if len(path) > 0 && FilterLogic(path[0]) == FilterAnd {
+			path = path[1:]
+		}
+
+		if len(path) == 0 {
+			errs = append(errs, queryError(parameter, "Invalid query parameter", "Filter field must be given."))
+			continue
+		}
+
+		condition := &FilterNode{Operator: FilterEq}
+
+		if op := FilterOperator(path[len(path)-1]); len(path) > 1 && filterOperators[op] {
+			condition.Operator = op
+			path = path[:len(path)-1]
+		}
+
+		condition.Field = strings.Join(path, ".")
+		condition.Values = strings.Split(param.Value, ",")
+
+		if len(condition.Values) > 1 {
+			switch condition.Operator {
+			case FilterEq:
+				condition.Operator = FilterIn
+			case FilterNe:
+				condition.Operator = FilterNin
+			case FilterIn, FilterNin:
+			default:
+				errs = append(errs, queryError(parameter, "Invalid query parameter", fmt.Sprintf("Filter operator %q accepts a single value.", condition.Operator)))
+				continue
+			}
+		}
+
+		if schema != nil && !schema.allows(condition.Field, condition.Operator) {
+			errs = append(errs, queryError(parameter, "Invalid query parameter", fmt.Sprintf("Filtering by %q with %q operator is not supported.", condition.Field, condition.Operator)))
+			continue
+		}
+
+		group.Children = append(group.Children, condition)
+	}
+
+	return root, errs
+}
+
+// Walk calls fn for the node and all its descendants in depth-first order.
+func (n *FilterNode) Walk(fn func(node *FilterNode)) {
+	fn(n)
+
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+func (s FilterSchema) allows(field string, op FilterOperator) bool {
+	ops, ok := s[field]
+	if !ok {
+		return false
+	}
+
+	for _, allowed := range ops {
+		if allowed == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+func filterParameter(path []string) string {
+	if len(path) == 0 {
+		return "filter"
+	}
+
+	return "filter[" + strings.Join(path, "][") + "]"
+}
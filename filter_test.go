@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("ParseFilter", func() {
+
+	parse := func(query string, schema FilterSchema) (*FilterNode, []*ErrorObject) {
+		values, err := url.ParseQuery(query)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		params, errs := ParseQuery(values)
+		Ω(errs).Should(BeEmpty())
+
+		return ParseFilter(params.Filter, schema)
+	}
+
+	It("parses filter parameters into filter tree", func() {
+		root, errs := parse("filter[id]=1,2&filter[year][gte]=2000&filter[author][name][like]=Don&filter[or][title]=Go&filter[or][year]=2015", nil)
+
+		Ω(errs).Should(BeEmpty())
+		Ω(root).Should(Equal(&FilterNode{
+			Logic: FilterAnd,
+			Children: []*FilterNode{
+				{Field: "author.name", Operator: FilterLike, Values: []string{"Don"}},
+				{Field: "id", Operator: FilterIn, Values: []string{"1", "2"}},
+				{
+					Logic: FilterOr,
+					Children: []*FilterNode{
+						{Field: "title", Operator: FilterEq, Values: []string{"Go"}},
+						{Field: "year", Operator: FilterEq, Values: []string{"2015"}},
+					},
+				},
+				{Field: "year", Operator: FilterGte, Values: []string{"2000"}},
+			},
+		}))
+	})
+
+	It("reports fields and operators missing in schema", func() {
+		schema := FilterSchema{
+			"title": {FilterEq, FilterLike},
+		}
+
+		_, errs := parse("filter[title][like]=Go&filter[title][gt]=A&filter[year]=2015", schema)
+
+		var parameters []string
+
+		for _, err := range errs {
+			parameters = append(parameters, err.Source.Parameter)
+		}
+
+		Ω(parameters).Should(Equal([]string{"filter[title][gt]", "filter[year]"}))
+	})
+
+	It("reports several values of single value operators", func() {
+		_, errs := parse("filter[year][gt]=2000,2010", nil)
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Source.Parameter).Should(Equal("filter[year][gt]"))
+	})
+})
@@ -0,0 +1,213 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Resource is untyped resource object with attributes and meta accessors, for gateways and tooling
+// which handle arbitrary resource types without Go structs describing them.
+type Resource struct {
+	ResourceObjectIdentifier
+	// Links the resource links.
+	Links Links
+
+	attributes    map[string]json.RawMessage
+	meta          map[string]json.RawMessage
+	relationships map[string]*relationship
+}
+
+// UnmarshalGeneric deserialize JSON API document and returns its primary data as untyped resources.
+// Included resources can be turned into them with ResourceOf.
+//
+// UnmarshalGeneric example:
+//
+//	doc, resources, err := jsonapi.UnmarshalGeneric(data)
+//
+//	for _, resource := range resources {
+//	  title, ok := resource.GetString("title")
+//	  ...
+//	}
+//
+// Strict and Version options are respected, the other ones don't apply.
+func UnmarshalGeneric(data []byte, opts ...Option) (*Document, []Resource, error) {
+	options := newOptions(opts)
+
+	doc, err := decodeDocument(data, &options)
+	if err != nil {
+		return doc, nil, err
+	}
+
+	var resources []Resource
+
+	for _, ro := range doc.PrimaryResources() {
+		resource, err := ResourceOf(ro)
+		if err != nil {
+			return doc, nil, err
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return doc, resources, nil
+}
+
+// ResourceOf returns untyped resource of the resource object, it fails when attributes or meta aren't JSON objects.
+func ResourceOf(ro *ResourceObject) (Resource, error) {
+	resource := Resource{
+		ResourceObjectIdentifier: ro.ResourceObjectIdentifier,
+		Links:                    ro.Links,
+		attributes:               map[string]json.RawMessage{},
+		meta:                     map[string]json.RawMessage{},
+		relationships:            ro.Relationships,
+	}
+
+	if len(ro.Attributes) > 0 {
+		if err := json.Unmarshal(ro.Attributes, &resource.attributes); err != nil {
+			return resource, err
+		}
+	}
+
+	if len(ro.Meta) > 0 {
+		if err := json.Unmarshal(ro.Meta, &resource.meta); err != nil {
+			return resource, err
+		}
+	}
+
+	return resource, nil
+}
+
+// Attribute returns raw JSON value of the attribute, false when the resource has no such attribute.
+func (r Resource) Attribute(name string) (json.RawMessage, bool) {
+	value, ok := r.attributes[name]
+
+	return value, ok
+}
+
+// AttributeNames returns the resource attribute names in sorted order.
+func (r Resource) AttributeNames() []string {
+	names := make([]string, 0, len(r.attributes))
+
+	for name := range r.attributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// DecodeAttribute decodes the attribute into target, it does nothing when the resource has no such attribute.
+func (r Resource) DecodeAttribute(name string, target interface{}) error {
+	value, ok := r.attributes[name]
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(value, target)
+}
+
+// GetString returns string attribute, false when it is missing, null or isn't a string.
+func (r Resource) GetString(name string) (string, bool) {
+	var s string
+
+	ok := r.decode(name, &s)
+
+	return s, ok
+}
+
+// GetFloat returns numeric attribute, false when it is missing or isn't a number.
+func (r Resource) GetFloat(name string) (float64, bool) {
+	var f float64
+
+	ok := r.decode(name, &f)
+
+	return f, ok
+}
+
+// GetInt returns integer attribute, false when it is missing or isn't an integer number.
+func (r Resource) GetInt(name string) (int64, bool) {
+	var i int64
+
+	ok := r.decode(name, &i)
+
+	return i, ok
+}
+
+// GetBool returns boolean attribute, false when it is missing or isn't a boolean.
+func (r Resource) GetBool(name string) (bool, bool) {
+	var b bool
+
+	ok := r.decode(name, &b)
+
+	return b, ok
+}
+
+// GetTime returns RFC 3339 time attribute, false when it is missing or isn't such a string.
+func (r Resource) GetTime(name string) (time.Time, bool) {
+	var t time.Time
+
+	ok := r.decode(name, &t)
+
+	return t, ok
+}
+
+// Meta returns raw JSON value of the resource meta member, false when the resource has no such member.
+func (r Resource) Meta(name string) (json.RawMessage, bool) {
+	value, ok := r.meta[name]
+
+	return value, ok
+}
+
+// RelationshipNames returns the resource relationship names in sorted order.
+func (r Resource) RelationshipNames() []string {
+	names := make([]string, 0, len(r.relationships))
+
+	for name := range r.relationships {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ToOne returns to-one relationship linkage, nil for empty one or relationship without linkage.
+// It returns false when the resource has no such relationship or it is to-many one.
+func (r Resource) ToOne(name string) (*ResourceObjectIdentifier, bool) {
+	rel, ok := r.relationships[name]
+	if !ok || rel == nil || rel.Data == nil {
+		return nil, ok
+	}
+
+	if rel.Data.Many != nil {
+		return nil, false
+	}
+
+	return rel.Data.One, true
+}
+
+// ToMany returns to-many relationship linkage.
+// It returns false when the resource has no such relationship or it is to-one one.
+func (r Resource) ToMany(name string) ([]*ResourceObjectIdentifier, bool) {
+	rel, ok := r.relationships[name]
+	if !ok || rel == nil || rel.Data == nil || rel.Data.Many == nil {
+		return nil, false
+	}
+
+	return rel.Data.Many, true
+}
+
+func (r Resource) decode(name string, target interface{}) bool {
+	value, ok := r.attributes[name]
+	if !ok || string(value) == "null" {
+		return false
+	}
+
+	return json.Unmarshal(value, target) == nil
+}
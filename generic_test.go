@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("UnmarshalGeneric", func() {
+
+	payload := []byte(`
+    {
+      "data": [
+        {
+          "type": "books",
+          "id": "1",
+          "attributes": {"title": "Go", "year": 2012, "rating": 4.5, "available": true, "published_at": "2012-03-01T10:00:00Z", "subtitle": null},
+          "meta": {"rank": 1},
+          "relationships": {
+            "author": {"data": {"type": "authors", "id": "1"}},
+            "editor": {"data": null},
+            "readers": {"data": [{"type": "people", "id": "1"}]}
+          },
+          "links": {"self": "/books/1"}
+        }
+      ],
+      "included": [{"type": "authors", "id": "1", "attributes": {"name": "Rob"}}]
+    }
+  `)
+
+	It("unmarshals untyped resources", func() {
+		doc, resources, err := UnmarshalGeneric(payload)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resources).Should(HaveLen(1))
+
+		book := resources[0]
+		Ω(book.Type).Should(Equal("books"))
+		Ω(book.ID).Should(Equal("1"))
+		Ω(book.Links["self"].Href).Should(Equal("/books/1"))
+		Ω(book.AttributeNames()).Should(Equal([]string{"available", "published_at", "rating", "subtitle", "title", "year"}))
+
+		title, ok := book.GetString("title")
+		Ω(ok).Should(BeTrue())
+		Ω(title).Should(Equal("Go"))
+
+		year, ok := book.GetInt("year")
+		Ω(ok).Should(BeTrue())
+		Ω(year).Should(Equal(int64(2012)))
+
+		rating, ok := book.GetFloat("rating")
+		Ω(ok).Should(BeTrue())
+		Ω(rating).Should(Equal(4.5))
+
+		available, ok := book.GetBool("available")
+		Ω(ok).Should(BeTrue())
+		Ω(available).Should(BeTrue())
+
+		publishedAt, ok := book.GetTime("published_at")
+		Ω(ok).Should(BeTrue())
+		Ω(publishedAt).Should(Equal(time.Date(2012, 3, 1, 10, 0, 0, 0, time.UTC)))
+
+		meta, found := book.Meta("rank")
+		Ω(found).Should(BeTrue())
+		Ω(meta).Should(MatchJSON(`1`))
+
+		author, err := ResourceOf(doc.FindIncluded("authors", "1"))
+		Ω(err).ShouldNot(HaveOccurred())
+		name, _ := author.GetString("name")
+		Ω(name).Should(Equal("Rob"))
+	})
+
+	It("doesn't get attributes of other types", func() {
+		_, resources, err := UnmarshalGeneric(payload)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		book := resources[0]
+
+		_, ok := book.GetString("year")
+		Ω(ok).Should(BeFalse())
+
+		_, ok = book.GetInt("rating")
+		Ω(ok).Should(BeFalse())
+
+		_, ok = book.GetString("subtitle")
+		Ω(ok).Should(BeFalse())
+
+		_, ok = book.GetString("isbn")
+		Ω(ok).Should(BeFalse())
+	})
+
+	It("returns relationships linkage", func() {
+		_, resources, err := UnmarshalGeneric(payload)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		book := resources[0]
+		Ω(book.RelationshipNames()).Should(Equal([]string{"author", "editor", "readers"}))
+
+		author, ok := book.ToOne("author")
+		Ω(ok).Should(BeTrue())
+		Ω(author).Should(Equal(&ResourceObjectIdentifier{Type: "authors", ID: "1"}))
+
+		editor, ok := book.ToOne("editor")
+		Ω(ok).Should(BeTrue())
+		Ω(editor).Should(BeNil())
+
+		readers, ok := book.ToMany("readers")
+		Ω(ok).Should(BeTrue())
+		Ω(readers).Should(HaveLen(1))
+
+		_, ok = book.ToOne("readers")
+		Ω(ok).Should(BeFalse())
+
+		_, ok = book.ToMany("author")
+		Ω(ok).Should(BeFalse())
+	})
+
+	It("respects strict mode", func() {
+		_, _, err := UnmarshalGeneric([]byte(`{"data": [], "errors": []}`), WithStrict())
+
+		Ω(err).Should(Equal(ErrDataAndErrors))
+	})
+})
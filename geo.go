@@ -0,0 +1,199 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GeoJSON geometry types https://tools.ietf.org/html/rfc7946#section-3.1
+const (
+	GeoPointType              = "Point"
+	GeoMultiPointType         = "MultiPoint"
+	GeoLineStringType         = "LineString"
+	GeoMultiLineStringType    = "MultiLineString"
+	GeoPolygonType            = "Polygon"
+	GeoMultiPolygonType       = "MultiPolygon"
+	GeoGeometryCollectionType = "GeometryCollection"
+)
+
+// ErrInvalidGeometry returned when GeoJSON geometry is malformed, the error is wrapped with the details.
+var ErrInvalidGeometry = errors.New("jsonapi: invalid GeoJSON geometry")
+
+// geoDepth nesting depth of the geometry type coordinates, 1 is a single position.
+var geoDepth = map[string]int{
+	GeoPointType:           1,
+	GeoMultiPointType:      2,
+	GeoLineStringType:      2,
+	GeoMultiLineStringType: 3,
+	GeoPolygonType:         3,
+	GeoMultiPolygonType:    4,
+}
+
+// Geometry is GeoJSON geometry object attribute value, e.g. "location": {"type": "Point", "coordinates": [30.5, 50.4]}.
+// The geometry is validated on Marshal and Unmarshal: positions must be [longitude, latitude] or [longitude, latitude, altitude]
+// in WGS 84 ranges, line strings must have two positions at least and polygon rings must be closed with four positions at least.
+// Zero Geometry is invalid, *Geometry should be used for optional attributes.
+type Geometry struct {
+	// Type GeoJSON geometry type, e.g. GeoPointType.
+	Type string `json:"type"`
+	// Coordinates raw coordinates of the geometry, nested according to its type.
+	Coordinates json.RawMessage `json:"coordinates,omitempty"`
+	// Geometries of GeometryCollection.
+	Geometries []Geometry `json:"geometries,omitempty"`
+}
+
+type geometry Geometry
+
+// NewPoint returns Point geometry of the position.
+func NewPoint(longitude, latitude float64) Geometry {
+	coordinates, _ := json.Marshal([]float64{longitude, latitude})
+
+	return Geometry{Type: GeoPointType, Coordinates: coordinates}
+}
+
+// Point returns longitude and latitude of Point geometry, false when it is another geometry or it is malformed.
+func (g Geometry) Point() (float64, float64, bool) {
+	var position []float64
+
+	if g.Type != GeoPointType || json.Unmarshal(g.Coordinates, &position) != nil || len(position) < 2 {
+		return 0, 0, false
+	}
+
+	return position[0], position[1], true
+}
+
+// Validate checks the geometry is valid GeoJSON geometry, see Geometry.
+func (g Geometry) Validate() error {
+	if g.Type == GeoGeometryCollectionType {
+		if len(g.Coordinates) > 0 {
+			return fmt.Errorf("%w: %s must not have coordinates", ErrInvalidGeometry, g.Type)
+		}
+
+		for _, member := range g.Geometries {
+			if err := member.Validate(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	depth, ok := geoDepth[g.Type]
+	if !ok {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidGeometry, g.Type)
+	}
+
+	if len(g.Geometries) > 0 {
+		return fmt.Errorf("%w: %s must not have geometries", ErrInvalidGeometry, g.Type)
+	}
+
+	var coordinates interface{}
+
+	if err := json.Unmarshal(g.Coordinates, &coordinates); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGeometry, err)
+	}
+
+	return validateCoordinates(g.Type, coordinates, depth)
+}
+
+// MarshalJSON marshals GeoJSON geometry object, it fails when the geometry is malformed.
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(geometry(g))
+}
+
+// UnmarshalJSON unmarshals GeoJSON geometry object, it fails when the geometry is malformed.
+func (g *Geometry) UnmarshalJSON(payload []byte) error {
+	var decoded geometry
+
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGeometry, err)
+	}
+
+	if err := Geometry(decoded).Validate(); err != nil {
+		return err
+	}
+
+	*g = Geometry(decoded)
+
+	return nil
+}
+
+func validateCoordinates(typ string, coordinates interface{}, depth int) error {
+	if depth == 1 {
+		return validatePosition(coordinates)
+	}
+
+	items, ok := coordinates.([]interface{})
+	if !ok {
+		return fmt.Errorf("%w: %s coordinates must be nested %d levels deep", ErrInvalidGeometry, typ, geoDepth[typ])
+	}
+
+	switch {
+	case depth == 2 && (typ == GeoLineStringType || typ == GeoMultiLineStringType) && len(items) < 2:
+		return fmt.Errorf("%w: line string must have two positions at least", ErrInvalidGeometry)
+	case depth == 2 && (typ == GeoPolygonType || typ == GeoMultiPolygonType):
+		if err := validateRing(items); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range items {
+		if err := validateCoordinates(typ, item, depth-1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateRing(positions []interface{}) error {
+	if len(positions) < 4 {
+		return fmt.Errorf("%w: polygon ring must have four positions at least", ErrInvalidGeometry)
+	}
+
+	first, _ := json.Marshal(positions[0])
+	last, _ := json.Marshal(positions[len(positions)-1])
+
+	if string(first) != string(last) {
+		return fmt.Errorf("%w: polygon ring must be closed", ErrInvalidGeometry)
+	}
+
+	return nil
+}
+
+func validatePosition(position interface{}) error {
+	values, ok := position.([]interface{})
+	if !ok || len(values) < 2 || len(values) > 3 {
+		return fmt.Errorf("%w: position must have two or three numbers", ErrInvalidGeometry)
+	}
+
+	numbers := make([]float64, 0, len(values))
+
+	for _, value := range values {
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%w: position must have two or three numbers", ErrInvalidGeometry)
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	if numbers[0] < -180 || numbers[0] > 180 {
+		return fmt.Errorf("%w: longitude must be within [-180, 180]: %v", ErrInvalidGeometry, numbers[0])
+	}
+
+	if numbers[1] < -90 || numbers[1] > 90 {
+		return fmt.Errorf("%w: latitude must be within [-90, 90]: %v", ErrInvalidGeometry, numbers[1])
+	}
+
+	return nil
+}
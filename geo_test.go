@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Venue struct {
+	ID       string    `json:"-"`
+	Location Geometry  `json:"location"`
+	Area     *Geometry `json:"area"`
+}
+
+func (v Venue) GetID() string {
+	return v.ID
+}
+
+func (v Venue) GetType() string {
+	return "venues"
+}
+
+func (v *Venue) SetID(id string) error {
+	v.ID = id
+	return nil
+}
+
+func (v *Venue) SetType(string) error {
+	return nil
+}
+
+type VenueView struct {
+	Venue Venue
+}
+
+func (v VenueView) GetData() interface{} {
+	return v.Venue
+}
+
+func (v *VenueView) SetData(to func(target interface{}) error) error {
+	return to(&v.Venue)
+}
+
+var _ = Describe("Geometry", func() {
+
+	It("marshals GeoJSON geometry attributes", func() {
+		view := VenueView{Venue: Venue{ID: "1", Location: NewPoint(30.5234, 50.4501)}}
+
+		result, err := Marshal(view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Data.One.Attributes).Should(MatchJSON(`{"location": {"type": "Point", "coordinates": [30.5234, 50.4501]}, "area": null}`))
+	})
+
+	It("unmarshals GeoJSON geometry attributes", func() {
+		payload := []byte(`
+      {
+        "data": {
+          "type": "venues",
+          "id": "1",
+          "attributes": {
+            "location": {"type": "Point", "coordinates": [30.5234, 50.4501, 179]},
+            "area": {"type": "Polygon", "coordinates": [[[30, 50], [31, 50], [31, 51], [30, 50]]]}
+          }
+        }
+      }
+    `)
+
+		result := VenueView{}
+
+		_, err := Unmarshal(payload, &result)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		longitude, latitude, ok := result.Venue.Location.Point()
+		Ω(ok).Should(BeTrue())
+		Ω(longitude).Should(Equal(30.5234))
+		Ω(latitude).Should(Equal(50.4501))
+
+		Ω(result.Venue.Area.Type).Should(Equal(GeoPolygonType))
+	})
+
+	It("validates geometries", func() {
+		valid := []string{
+			`{"type": "MultiPoint", "coordinates": [[1, 2], [3, 4]]}`,
+			`{"type": "LineString", "coordinates": [[1, 2], [3, 4]]}`,
+			`{"type": "MultiPolygon", "coordinates": [[[[0, 0], [1, 0], [1, 1], [0, 0]]]]}`,
+			`{"type": "GeometryCollection", "geometries": [{"type": "Point", "coordinates": [1, 2]}]}`,
+		}
+
+		for _, payload := range valid {
+			Ω(json.Unmarshal([]byte(payload), &Geometry{})).Should(Succeed(), payload)
+		}
+
+		invalid := []string{
+			`{"type": "Circle", "coordinates": [1, 2]}`,
+			`{"type": "Point", "coordinates": [1]}`,
+			`{"type": "Point", "coordinates": [181, 2]}`,
+			`{"type": "Point", "coordinates": [1, -91]}`,
+			`{"type": "Point", "coordinates": [[1, 2]]}`,
+			`{"type": "Point", "coordinates": ["1", "2"]}`,
+			`{"type": "LineString", "coordinates": [[1, 2]]}`,
+			`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}`,
+			`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [0, 0]]]}`,
+			`{"type": "GeometryCollection", "geometries": [{"type": "Point", "coordinates": [1]}]}`,
+			`"POINT(1 2)"`,
+		}
+
+		for _, payload := range invalid {
+			Ω(json.Unmarshal([]byte(payload), &Geometry{})).Should(MatchError(ErrInvalidGeometry), payload)
+		}
+	})
+
+	It("doesn't marshal malformed geometry", func() {
+		_, err := Marshal(VenueView{Venue: Venue{ID: "1"}})
+
+		Ω(err).Should(MatchError(ErrInvalidGeometry))
+	})
+})
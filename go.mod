@@ -1,6 +1,6 @@
 module github.com/pieoneers/jsonapi-go
 
-go 1.14
+go 1.18
 
 require (
 	github.com/onsi/ginkgo v1.12.0
@@ -9,3 +9,11 @@ require (
 	golang.org/x/text v0.3.2
 	gopkg.in/yaml.v2 v2.2.8
 )
+
+require (
+	github.com/hpcloud/tail v1.0.0 // indirect
+	golang.org/x/sys v0.0.0-20200323222414-85ca7c5b95cd // indirect
+	golang.org/x/xerrors v0.0.0-20190717185122-a985d3407aa7 // indirect
+	gopkg.in/fsnotify.v1 v1.4.7 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+)
@@ -0,0 +1,516 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// HALContentType describes HAL data content type.
+const HALContentType = "application/hal+json"
+
+const (
+	halLinks    = "_links"
+	halMeta     = "_meta"
+	halEmbedded = "_embedded"
+	halErrors   = "errors"
+)
+
+// ErrInvalidHAL returned when HAL document can't be converted into JSON API document, or JSON API document
+// can't be converted into HAL one, e.g. its resource type or relationship is "errors" which embeds the error objects.
+var ErrInvalidHAL = errors.New("jsonapi: invalid HAL document")
+
+// MarshalHAL converts JSON API document into HAL document https://tools.ietf.org/html/draft-kelly-json-hal
+//
+// The conversion is best-effort:
+//
+//   - resource attributes become HAL properties next to "id" and "type";
+//   - resource links become "_links", e.g. "self", and resource meta is kept apart from the properties in "_meta";
+//   - relationships are put into "_embedded", using the matching included resource when it is available
+//     or the resource identifier otherwise;
+//   - collections are embedded under their resource type;
+//   - document links and meta become root "_links" and "_meta" and errors are embedded under "errors".
+//
+// Single resource document root is the resource itself, so its links and meta are merged with the document ones,
+// the resource members take precedence. Nil document is converted as empty one.
+func MarshalHAL(doc *Document) ([]byte, error) {
+	if doc == nil {
+		doc = &Document{}
+	}
+
+	root := map[string]interface{}{}
+
+	included := map[ResourceObjectIdentifier]*ResourceObject{}
+
+	for _, ro := range doc.Included {
+		included[ro.ResourceObjectIdentifier] = ro
+	}
+
+	if doc.Data != nil {
+		if one := doc.Data.One; one != nil {
+			resource, err := halResource(one, included, true)
+			if err != nil {
+				return nil, err
+			}
+
+			root = resource
+		} else {
+			embedded := map[string]interface{}{}
+
+			for _, ro := range doc.Data.Many {
+				if ro.Type == halErrors {
+					return nil, fmt.Errorf("%w: %q resource type collides with embedded errors", ErrInvalidHAL, ro.Type)
+				}
+
+				resource, err := halResource(ro, included, true)
+				if err != nil {
+					return nil, err
+				}
+
+				items, _ := embedded[ro.Type].([]interface{})
+				embedded[ro.Type] = append(items, resource)
+			}
+
+			root[halEmbedded] = embedded
+		}
+	}
+
+	if len(doc.Errors) > 0 {
+		embedded, ok := root[halEmbedded].(map[string]interface{})
+		if !ok {
+			embedded = map[string]interface{}{}
+			root[halEmbedded] = embedded
+		}
+
+		embedded[halErrors] = doc.Errors
+	}
+
+	if len(doc.Links) > 0 {
+		links, _ := root[halLinks].(map[string]interface{})
+		if links == nil {
+			links = map[string]interface{}{}
+		}
+
+		for k, v := range halLinkObjects(doc.Links) {
+			if _, ok := links[k]; !ok {
+				links[k] = v
+			}
+		}
+
+		root[halLinks] = links
+	}
+
+	if len(doc.Meta) > 0 {
+		meta := map[string]json.RawMessage{}
+
+		if err := json.Unmarshal(doc.Meta, &meta); err != nil {
+			return nil, err
+		}
+
+		if resourceMeta, ok := root[halMeta].(map[string]json.RawMessage); ok {
+			for k, v := range resourceMeta {
+				meta[k] = v
+			}
+		}
+
+		root[halMeta] = meta
+	}
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(root)
+
+	return buf.Bytes(), err
+}
+
+func halResource(ro *ResourceObject, included map[ResourceObjectIdentifier]*ResourceObject, expand bool) (map[string]interface{}, error) {
+	resource := map[string]interface{}{}
+
+	if len(ro.Attributes) > 0 {
+		attributes := map[string]json.RawMessage{}
+
+		if err := json.Unmarshal(ro.Attributes, &attributes); err != nil {
+			return nil, err
+		}
+
+		for k, v := range attributes {
+			resource[k] = v
+		}
+	}
+
+	resource["type"] = ro.Type
+
+	if len(ro.ID) > 0 {
+		resource["id"] = ro.ID
+	}
+
+	if len(ro.LID) > 0 {
+		resource["lid"] = ro.LID
+	}
+
+	if len(ro.Links) > 0 {
+		resource[halLinks] = halLinkObjects(ro.Links)
+	}
+
+	if len(ro.Meta) > 0 {
+		meta := map[string]json.RawMessage{}
+
+		if err := json.Unmarshal(ro.Meta, &meta); err != nil {
+			return nil, err
+		}
+
+		resource[halMeta] = meta
+	}
+
+	embedded := map[string]interface{}{}
+
+	for key, rel := range ro.Relationships {
+		if key == halErrors {
+			return nil, fmt.Errorf("%w: %q relationship collides with embedded errors", ErrInvalidHAL, key)
+		}
+
+		if rel == nil || rel.Data == nil {
+			embedded[key] = nil
+			continue
+		}
+
+		if one := rel.Data.One; one != nil && (len(one.ID) > 0 || len(one.LID) > 0) {
+			embedded[key] = halRelated(one, included, expand)
+			continue
+		}
+
+		if many := rel.Data.Many; many != nil {
+			items := make([]interface{}, 0, len(many))
+
+			for _, roi := range many {
+				items = append(items, halRelated(roi, included, expand))
+			}
+
+			embedded[key] = items
+			continue
+		}
+
+		embedded[key] = nil
+	}
+
+	if len(embedded) > 0 {
+		resource[halEmbedded] = embedded
+	}
+
+	return resource, nil
+}
+
+// halLinkObjects returns HAL link objects of links, HAL links are always objects having "href".
+func halLinkObjects(links Links) map[string]interface{} {
+	objects := make(map[string]interface{}, len(links))
+
+	for k, link := range links {
+		if link != nil {
+			objects[k] = linkObject(*link)
+		}
+	}
+
+	return objects
+}
+
+func halRelated(roi *ResourceObjectIdentifier, included map[ResourceObjectIdentifier]*ResourceObject, expand bool) interface{} {
+	if ro, ok := included[*roi]; ok && expand {
+		if resource, err := halResource(ro, included, false); err == nil {
+			return resource
+		}
+	}
+
+	return roi
+}
+
+// UnmarshalHAL converts HAL document produced by MarshalHAL (or following the same conventions) into JSON API document.
+// HAL resources must contain "type" property, embedded resources with properties besides "id", "lid" and "type"
+// are added to the document included, document without single resource or "_embedded" has no primary data. "_links" and "_meta" become links and meta of the resource,
+// the root ones become document links and meta unless the root is a single resource,
+// then its "_links" are kept by the resource and "_meta" becomes document meta.
+// Link arrays, e.g. "curies", are skipped.
+func UnmarshalHAL(data []byte) (*Document, error) {
+	doc := &Document{}
+
+	root := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(data, &root); err != nil {
+		return doc, err
+	}
+
+	included := &halIncluded{seen: map[ResourceObjectIdentifier]bool{}}
+
+	if _, ok := root["type"]; ok {
+		one, err := unmarshalHALResource(root, included)
+		if err != nil {
+			return doc, err
+		}
+
+		doc.Data = &documentData{One: one}
+
+		doc.Meta, one.Meta = one.Meta, nil
+
+		if raw, ok := root[halEmbedded]; ok {
+			if err := unmarshalHALErrors(raw, doc); err != nil {
+				return doc, err
+			}
+		}
+	} else {
+		embedded := map[string]json.RawMessage{}
+
+		if raw, ok := root[halEmbedded]; ok {
+			if err := json.Unmarshal(raw, &embedded); err != nil {
+				return doc, err
+			}
+		}
+
+		var keys []string
+
+		for k := range embedded {
+			if k != halErrors {
+				keys = append(keys, k)
+			}
+		}
+
+		sort.Strings(keys)
+
+		// documents without "_embedded", e.g. having meta only, have no primary data
+		if _, ok := root[halEmbedded]; ok && (len(keys) > 0 || len(embedded) == 0) {
+			doc.Data = &documentData{Many: []*ResourceObject{}}
+		}
+
+		for _, k := range keys {
+			var items []map[string]json.RawMessage
+
+			if err := json.Unmarshal(embedded[k], &items); err != nil {
+				return doc, ErrInvalidHAL
+			}
+
+			for _, item := range items {
+				ro, err := unmarshalHALResource(item, included)
+				if err != nil {
+					return doc, err
+				}
+
+				doc.Data.Many = append(doc.Data.Many, ro)
+			}
+		}
+
+		if raw, ok := root[halEmbedded]; ok {
+			if err := unmarshalHALErrors(raw, doc); err != nil {
+				return doc, err
+			}
+		}
+
+		links, err := unmarshalHALLinks(root)
+		if err != nil {
+			return doc, err
+		}
+
+		doc.Links = links
+
+		if raw, ok := root[halMeta]; ok {
+			doc.Meta = raw
+		}
+	}
+
+	doc.Included = included.resources
+
+	return doc, nil
+}
+
+type halIncluded struct {
+	seen      map[ResourceObjectIdentifier]bool
+	resources []*ResourceObject
+}
+
+func (hi *halIncluded) add(ro *ResourceObject) {
+	if hi.seen[ro.ResourceObjectIdentifier] {
+		return
+	}
+
+	hi.seen[ro.ResourceObjectIdentifier] = true
+	hi.resources = append(hi.resources, ro)
+}
+
+func unmarshalHALResource(resource map[string]json.RawMessage, included *halIncluded) (*ResourceObject, error) {
+	ro := &ResourceObject{}
+
+	if err := json.Unmarshal(resource["type"], &ro.Type); err != nil || len(ro.Type) == 0 {
+		return nil, ErrInvalidHAL
+	}
+
+	if raw, ok := resource["id"]; ok {
+		if err := json.Unmarshal(raw, &ro.ID); err != nil {
+			return nil, ErrInvalidHAL
+		}
+	}
+
+	if raw, ok := resource["lid"]; ok {
+		if err := json.Unmarshal(raw, &ro.LID); err != nil {
+			return nil, ErrInvalidHAL
+		}
+	}
+
+	links, err := unmarshalHALLinks(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	ro.Links = links
+
+	if raw, ok := resource[halMeta]; ok {
+		ro.Meta = raw
+	}
+
+	attributes := map[string]json.RawMessage{}
+
+	for k, v := range resource {
+		switch k {
+		case "id", "lid", "type", halLinks, halMeta, halEmbedded:
+		default:
+			attributes[k] = v
+		}
+	}
+
+	if len(attributes) > 0 {
+		raw, err := json.Marshal(attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		ro.Attributes = raw
+	}
+
+	raw, ok := resource[halEmbedded]
+	if !ok {
+		return ro, nil
+	}
+
+	embedded := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(raw, &embedded); err != nil {
+		return nil, ErrInvalidHAL
+	}
+
+	for k, v := range embedded {
+		if k == halErrors {
+			continue
+		}
+
+		if ro.Relationships == nil {
+			ro.Relationships = map[string]*relationship{}
+		}
+
+		rel := &relationship{Data: &relationshipData{}}
+
+		switch jsonDelim(v) {
+		case '{':
+			related := map[string]json.RawMessage{}
+
+			if err := json.Unmarshal(v, &related); err != nil {
+				return nil, ErrInvalidHAL
+			}
+
+			one, err := unmarshalHALRelated(related, included)
+			if err != nil {
+				return nil, err
+			}
+
+			rel.Data.One = one
+		case '[':
+			var items []map[string]json.RawMessage
+
+			if err := json.Unmarshal(v, &items); err != nil {
+				return nil, ErrInvalidHAL
+			}
+
+			rel.Data.Many = make([]*ResourceObjectIdentifier, 0, len(items))
+
+			for _, related := range items {
+				one, err := unmarshalHALRelated(related, included)
+				if err != nil {
+					return nil, err
+				}
+
+				rel.Data.Many = append(rel.Data.Many, one)
+			}
+		}
+
+		ro.Relationships[k] = rel
+	}
+
+	return ro, nil
+}
+
+func unmarshalHALRelated(related map[string]json.RawMessage, included *halIncluded) (*ResourceObjectIdentifier, error) {
+	ro, err := unmarshalHALResource(related, included)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ro.Attributes) > 0 || len(ro.Relationships) > 0 || len(ro.Links) > 0 || len(ro.Meta) > 0 {
+		included.add(ro)
+	}
+
+	roi := ro.ResourceObjectIdentifier
+
+	return &roi, nil
+}
+
+func unmarshalHALErrors(raw json.RawMessage, doc *Document) error {
+	embedded := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(raw, &embedded); err != nil {
+		return ErrInvalidHAL
+	}
+
+	if errs, ok := embedded[halErrors]; ok {
+		return json.Unmarshal(errs, &doc.Errors)
+	}
+
+	return nil
+}
+
+func unmarshalHALLinks(resource map[string]json.RawMessage) (Links, error) {
+	raw, ok := resource[halLinks]
+	if !ok {
+		return nil, nil
+	}
+
+	objects := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(raw, &objects); err != nil {
+		return nil, ErrInvalidHAL
+	}
+
+	links := Links{}
+
+	for k, v := range objects {
+		if jsonDelim(v) != '{' {
+			continue
+		}
+
+		link := &Link{}
+
+		if err := json.Unmarshal(v, link); err != nil {
+			return nil, ErrInvalidHAL
+		}
+
+		links[k] = link
+	}
+
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	return links, nil
+}
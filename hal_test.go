@@ -0,0 +1,338 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("HAL", func() {
+
+	Describe("MarshalHAL", func() {
+
+		It("converts single resource object with included relationship", func() {
+			view := BookWithAuthorIncludedView{
+				BookWithAuthorView: BookWithAuthorView{
+					Book: BookWithAuthor{
+						Book: Book{
+							ID:    "1",
+							Title: "An Introduction to Programming in Go",
+							Year:  "2012",
+							Type:  "books",
+						},
+						Author: Author{
+							ID:   "1",
+							Name: "Caleb Doxsey",
+						},
+					},
+				},
+			}
+
+			payload, err := Marshal(view)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			doc, err := Unmarshal(payload, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			result, err := MarshalHAL(doc)
+
+			expected := `
+        {
+          "type": "books",
+          "id": "1",
+          "title": "An Introduction to Programming in Go",
+          "year": "2012",
+          "_embedded": {
+            "author": {
+              "type": "authors",
+              "id": "1",
+              "name": "Caleb Doxsey"
+            }
+          }
+        }
+      `
+
+			Ω(result).Should(MatchJSON(expected))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("converts resource objects collection with meta", func() {
+			payload := []byte(`
+        {
+          "data": [
+            { "type": "books", "id": "1", "attributes": { "title": "Go in Action" } },
+            { "type": "books", "id": "2", "attributes": { "title": "Go Programming Language" } }
+          ],
+          "meta": { "count": 2 }
+        }
+      `)
+
+			doc, err := Unmarshal(payload, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			result, err := MarshalHAL(doc)
+
+			expected := `
+        {
+          "_meta": { "count": 2 },
+          "_embedded": {
+            "books": [
+              { "type": "books", "id": "1", "title": "Go in Action" },
+              { "type": "books", "id": "2", "title": "Go Programming Language" }
+            ]
+          }
+        }
+      `
+
+			Ω(result).Should(MatchJSON(expected))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("converts nil document as empty one", func() {
+			result, err := MarshalHAL(nil)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result).Should(MatchJSON(`{}`))
+		})
+
+		It("rejects members colliding with embedded errors", func() {
+			collection, err := Unmarshal([]byte(`{"data": [{"type": "errors", "id": "1"}]}`), nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = MarshalHAL(collection)
+			Ω(err).Should(MatchError(ErrInvalidHAL))
+
+			one, err := Unmarshal([]byte(`{"data": {"type": "books", "id": "1", "relationships": {"errors": {"data": []}}}}`), nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = MarshalHAL(one)
+			Ω(err).Should(MatchError(ErrInvalidHAL))
+		})
+	})
+
+	Describe("UnmarshalHAL", func() {
+
+		It("converts HAL resource into JSON API document", func() {
+			payload := []byte(`
+        {
+          "type": "books",
+          "id": "1",
+          "title": "An Introduction to Programming in Go",
+          "_links": { "self": { "href": "/books/1" } },
+          "_embedded": {
+            "author": { "type": "authors", "id": "1", "name": "Caleb Doxsey" },
+            "readers": [ { "type": "people", "id": "2" } ]
+          }
+        }
+      `)
+
+			doc, err := UnmarshalHAL(payload)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			result, err := json.Marshal(doc)
+
+			expected := `
+        {
+          "data": {
+            "type": "books",
+            "id": "1",
+            "attributes": {
+              "title": "An Introduction to Programming in Go"
+            },
+            "links": { "self": "/books/1" },
+            "relationships": {
+              "author": {
+                "data": { "type": "authors", "id": "1" }
+              },
+              "readers": {
+                "data": [ { "type": "people", "id": "2" } ]
+              }
+            }
+          },
+          "included": [
+            {
+              "type": "authors",
+              "id": "1",
+              "attributes": {
+                "name": "Caleb Doxsey"
+              }
+            }
+          ]
+        }
+      `
+
+			Ω(result).Should(MatchJSON(expected))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("round trips links and meta", func() {
+			payload := []byte(`
+        {
+          "data": [
+            {
+              "type": "books",
+              "id": "1",
+              "attributes": { "title": "Go in Action" },
+              "links": { "self": "/books/1" },
+              "meta": { "stock": 3 }
+            }
+          ],
+          "links": { "self": "/books", "next": { "href": "/books?page[number]=2", "title": "Next" } },
+          "meta": { "count": 2 }
+        }
+      `)
+
+			doc, err := Unmarshal(payload, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			hal, err := MarshalHAL(doc)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(hal).Should(MatchJSON(`
+        {
+          "_links": {
+            "self": { "href": "/books" },
+            "next": { "href": "/books?page[number]=2", "title": "Next" }
+          },
+          "_meta": { "count": 2 },
+          "_embedded": {
+            "books": [
+              {
+                "type": "books",
+                "id": "1",
+                "title": "Go in Action",
+                "_links": { "self": { "href": "/books/1" } },
+                "_meta": { "stock": 3 }
+              }
+            ]
+          }
+        }
+      `))
+
+			converted, err := UnmarshalHAL(hal)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			result, err := json.Marshal(converted)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result).Should(MatchJSON(payload))
+		})
+
+		It("keeps single resource links and document meta apart from attributes", func() {
+			payload := []byte(`
+        {
+          "data": {
+            "type": "books",
+            "id": "1",
+            "attributes": { "title": "Go in Action" },
+            "links": { "self": "/books/1" }
+          },
+          "links": { "self": "/books/1?include=author", "related": "/authors/1" },
+          "meta": { "version": "1" }
+        }
+      `)
+
+			doc, err := Unmarshal(payload, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			hal, err := MarshalHAL(doc)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(hal).Should(MatchJSON(`
+        {
+          "type": "books",
+          "id": "1",
+          "title": "Go in Action",
+          "_links": {
+            "self": { "href": "/books/1" },
+            "related": { "href": "/authors/1" }
+          },
+          "_meta": { "version": "1" }
+        }
+      `))
+
+			converted, err := UnmarshalHAL(hal)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			result, err := json.Marshal(converted)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(result).Should(MatchJSON(`
+        {
+          "data": {
+            "type": "books",
+            "id": "1",
+            "attributes": { "title": "Go in Action" },
+            "links": { "self": "/books/1", "related": "/authors/1" }
+          },
+          "meta": { "version": "1" }
+        }
+      `))
+		})
+
+		It("round trips meta only document without primary data", func() {
+			doc, err := Unmarshal([]byte(`{"meta": {"count": 0}}`), nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			hal, err := MarshalHAL(doc)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hal).Should(MatchJSON(`{"_meta": {"count": 0}}`))
+
+			converted, err := UnmarshalHAL(hal)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(converted.Data).Should(BeNil())
+
+			result, err := json.Marshal(converted)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result).Should(MatchJSON(`{"meta": {"count": 0}}`))
+		})
+
+		It("keeps to-one relationship identified by lid", func() {
+			payload := []byte(`
+        {
+          "data": {
+            "type": "books",
+            "lid": "book-1",
+            "attributes": { "title": "Go in Action" },
+            "relationships": {
+              "author": { "data": { "type": "authors", "lid": "author-1" } }
+            }
+          }
+        }
+      `)
+
+			doc, err := Unmarshal(payload, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			hal, err := MarshalHAL(doc)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hal).Should(MatchJSON(`
+        {
+          "type": "books",
+          "lid": "book-1",
+          "title": "Go in Action",
+          "_embedded": { "author": { "type": "authors", "lid": "author-1" } }
+        }
+      `))
+
+			converted, err := UnmarshalHAL(hal)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			result, err := json.Marshal(converted)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result).Should(MatchJSON(payload))
+		})
+
+		It("returns error for HAL resource without type", func() {
+			_, err := UnmarshalHAL([]byte(`{ "_embedded": { "books": [ { "id": "1" } ] } }`))
+
+			Ω(err).Should(Equal(ErrInvalidHAL))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// BeforeMarshaler interface may be implemented to prepare the resource right before Marshal encodes it,
+// e.g. to compute derived attributes, tagged, adapted and extracted resources included. Resources given by value
+// having pointer receiver BeforeMarshal are copied, so the hook doesn't change the caller's value.
+// The error aborts Marshal as MarshalError.
+//
+// BeforeMarshal example:
+//
+//	func (b *Book) BeforeMarshal(ctx context.Context) error {
+//	  b.Slug = slug.Make(b.Title)
+//	  return nil
+//	}
+type BeforeMarshaler interface {
+	BeforeMarshal(ctx context.Context) error
+}
+
+// AfterUnmarshaler interface may be implemented to finish the resource right after Unmarshal sets its attributes
+// and relationships, before ResourceValidator validates it, e.g. to normalize or cross-check fields.
+// ValidationError returned by the hook is collected like ResourceValidator errors, other errors abort Unmarshal.
+//
+// AfterUnmarshal example:
+//
+//	func (b *Book) AfterUnmarshal(ctx context.Context) error {
+//	  b.Title = strings.TrimSpace(b.Title)
+//	  return nil
+//	}
+type AfterUnmarshaler interface {
+	AfterUnmarshal(ctx context.Context) error
+}
+
+// optionsContext returns Options.Context, context.Background() when it is nil.
+func optionsContext(opts *Options) context.Context {
+	if opts.Context == nil {
+		return context.Background()
+	}
+
+	return opts.Context
+}
+
+// beforeMarshal calls BeforeMarshal hook of the resource, on a copy of the resource given by value.
+// The wrappers are given the copy in place of the wrapped value.
+func beforeMarshal(mri MarshalResourceIdentifier, opts *Options) (MarshalResourceIdentifier, error) {
+	resource := unwrapResource(mri)
+
+	if bm, ok := resource.(BeforeMarshaler); ok {
+		return mri, bm.BeforeMarshal(optionsContext(opts))
+	}
+
+	// tagged struct given by pointer is wrapped as addressable value
+	if tr, ok := mri.(taggedResource); ok && tr.value.CanAddr() {
+		if bm, ok := tr.value.Addr().Interface().(BeforeMarshaler); ok {
+			return mri, bm.BeforeMarshal(optionsContext(opts))
+		}
+	}
+
+	value := reflect.ValueOf(resource)
+	if value.Kind() == reflect.Ptr {
+		return mri, nil
+	}
+
+	copied := reflect.New(value.Type())
+	copied.Elem().Set(value)
+
+	bm, ok := copied.Interface().(BeforeMarshaler)
+	if !ok {
+		return mri, nil
+	}
+
+	if err := bm.BeforeMarshal(optionsContext(opts)); err != nil {
+		return mri, err
+	}
+
+	if rw, ok := mri.(rewrappedResource); ok {
+		return rw.rewrap(copied.Elem().Interface()), nil
+	}
+
+	return copied.Interface().(MarshalResourceIdentifier), nil
+}
+
+// afterUnmarshal calls AfterUnmarshal hook of the resource, prefixing ValidationError source pointers
+// with the resource pointer like validateResource does.
+func afterUnmarshal(ui UnmarshalResourceIdentifier, pointer string, opts *Options) error {
+	au, ok := unwrapResource(ui).(AfterUnmarshaler)
+	if !ok {
+		return nil
+	}
+
+	err := au.AfterUnmarshal(optionsContext(opts))
+
+	var validationErr *ValidationError
+
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	enriched := make([]*ErrorObject, 0, len(validationErr.Errors))
+
+	for _, eo := range validationErr.Errors {
+		if eo == nil {
+			continue
+		}
+
+		copied := *eo
+		copied.Source.Pointer = resourcePointer(pointer, eo.Source.Pointer)
+
+		enriched = append(enriched, &copied)
+	}
+
+	return &ValidationError{Errors: enriched}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type hookKey struct{}
+
+type Recipe struct {
+	ID       string `json:"-"`
+	Title    string `json:"title"`
+	Servings int    `json:"servings"`
+	Summary  string `json:"summary,omitempty"`
+	Hooked   string `json:"-"`
+}
+
+func (r Recipe) GetID() string {
+	return r.ID
+}
+
+func (r Recipe) GetType() string {
+	return "recipes"
+}
+
+func (r *Recipe) BeforeMarshal(ctx context.Context) error {
+	if r.Servings < 0 {
+		return errors.New("negative servings")
+	}
+
+	r.Summary = r.Title + " for " + strconv.Itoa(r.Servings)
+
+	return nil
+}
+
+func (r *Recipe) SetID(id string) error {
+	r.ID = id
+	return nil
+}
+
+func (r *Recipe) SetType(string) error {
+	return nil
+}
+
+func (r *Recipe) SetData(to func(target interface{}) error) error {
+	return to(r)
+}
+
+func (r *Recipe) AfterUnmarshal(ctx context.Context) error {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Hooked, _ = ctx.Value(hookKey{}).(string)
+
+	if r.Servings == 0 {
+		return &ValidationError{Errors: []*ErrorObject{{
+			Status: strconv.Itoa(http.StatusUnprocessableEntity),
+			Title:  "must be positive",
+			Source: ErrorObjectSource{Pointer: "/attributes/servings"},
+		}}}
+	}
+
+	return nil
+}
+
+type RecipeView struct {
+	Recipe interface{}
+}
+
+func (v RecipeView) GetData() interface{} {
+	return v.Recipe
+}
+
+type Recipes []Recipe
+
+type Cookbook struct {
+	ID    string `json:"-" jsonapi:"id,type=cookbooks"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+func (c *Cookbook) BeforeMarshal(ctx context.Context) error {
+	c.Slug = strings.ToLower(strings.ReplaceAll(c.Title, " ", "-"))
+	return nil
+}
+
+func (r *Recipes) SetData(to func(target interface{}) error) error {
+	return to((*[]Recipe)(r))
+}
+
+var _ = Describe("Lifecycle hooks", func() {
+
+	It("calls BeforeMarshal on a copy of the resource given by value", func() {
+		recipe := Recipe{ID: "1", Title: "Pancakes", Servings: 4}
+
+		result, err := Marshal(RecipeView{Recipe: recipe})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "recipes", "id": "1", "attributes": {
+			"title": "Pancakes", "servings": 4, "summary": "Pancakes for 4"
+		}}}`))
+		Ω(recipe.Summary).Should(BeEmpty())
+	})
+
+	It("calls BeforeMarshal on the resource given by pointer", func() {
+		recipe := &Recipe{ID: "1", Title: "Pancakes", Servings: 4}
+
+		_, err := Marshal(RecipeView{Recipe: recipe})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(recipe.Summary).Should(Equal("Pancakes for 4"))
+	})
+
+	It("calls BeforeMarshal of tagged struct given by value and by pointer", func() {
+		expected := `{"data": {"type": "cookbooks", "id": "1", "attributes": {
+			"title": "Sweet Pastry", "slug": "sweet-pastry"
+		}}}`
+
+		cookbook := Cookbook{ID: "1", Title: "Sweet Pastry"}
+
+		result, err := Marshal(RecipeView{Recipe: cookbook})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(expected))
+		Ω(cookbook.Slug).Should(BeEmpty())
+
+		result, err = Marshal(RecipeView{Recipe: &cookbook})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(expected))
+		Ω(cookbook.Slug).Should(Equal("sweet-pastry"))
+	})
+
+	It("returns BeforeMarshal error as MarshalError", func() {
+		_, err := Marshal(RecipeView{Recipe: Recipe{ID: "1", Servings: -1}})
+
+		var marshalErr *MarshalError
+
+		Ω(errors.As(err, &marshalErr)).Should(BeTrue())
+		Ω(marshalErr.Type).Should(Equal("recipes"))
+		Ω(marshalErr.ID).Should(Equal("1"))
+		Ω(err).Should(MatchError(ContainSubstring("negative servings")))
+	})
+
+	It("calls AfterUnmarshal with options context", func() {
+		var recipe Recipe
+
+		ctx := context.WithValue(context.Background(), hookKey{}, "called")
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "recipes", "id": "1", "attributes": {"title": " Pancakes ", "servings": 4}}}`), &recipe, WithContext(ctx))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(recipe.Title).Should(Equal("Pancakes"))
+		Ω(recipe.Hooked).Should(Equal("called"))
+	})
+
+	It("collects AfterUnmarshal validation errors with resource pointers", func() {
+		var recipes Recipes
+
+		_, err := Unmarshal([]byte(`{"data": [
+			{"type": "recipes", "id": "1", "attributes": {"title": "Pancakes", "servings": 0}},
+			{"type": "recipes", "id": "2", "attributes": {"title": "Waffles", "servings": 2}},
+			{"type": "recipes", "id": "3", "attributes": {"title": "Crepes", "servings": 0}}
+		]}`), &recipes)
+
+		var validationErr *ValidationError
+
+		Ω(errors.As(err, &validationErr)).Should(BeTrue())
+		Ω(validationErr.Errors).Should(HaveLen(2))
+		Ω(validationErr.Errors[0].Source.Pointer).Should(Equal("/data/0/attributes/servings"))
+		Ω(validationErr.Errors[1].Source.Pointer).Should(Equal("/data/2/attributes/servings"))
+	})
+})
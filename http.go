@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// DefaultRequestMaxSize maximum size of the request document read by ReadRequest when Options.MaxSize is zero.
+const DefaultRequestMaxSize int64 = 1 << 20
+
+// ErrUnsupportedMediaType returned by ReadRequest when the request Content-Type isn't JSON API media type,
+// or it has parameters other than JSON API 1.1 "ext" and "profile".
+var ErrUnsupportedMediaType = errors.New("jsonapi: unsupported media type")
+
+// Write marshals the view and writes it into HTTP response with the status code and JSON API Content-Type,
+// Cache-Control and Surrogate-Key headers are set by the document cache hints, see SetCacheHeaders.
+// Nothing is written when the view fails to marshal, so the handler may still write error document.
+// 204 No Content is written without body.
+//
+// Write example:
+//
+//	func (h *Handler) ShowBook(w http.ResponseWriter, r *http.Request) {
+//	  book, err := h.store.Book(path.Base(r.URL.Path))
+//	  if err != nil {
+//	    _ = jsonapi.WriteErrors(w, r, []*jsonapi.ErrorObject{jsonapi.ErrorFromErr(err)})
+//	    return
+//	  }
+//
+//	  if err := jsonapi.Write(w, http.StatusOK, BookView{Book: book}); err != nil {
+//	    _ = jsonapi.WriteErrors(w, r, []*jsonapi.ErrorObject{jsonapi.ErrorFromErr(err)})
+//	  }
+//	}
+func Write(w http.ResponseWriter, status int, view interface{}, opts ...Option) error {
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return nil
+	}
+
+	options := newOptions(opts)
+
+	doc, err := marshalPayload(view, &options)
+	if err != nil {
+		return err
+	}
+
+	payload, err := encodeMarshaled(doc, &options, "", "")
+	if err != nil {
+		return err
+	}
+
+	SetCacheHeaders(w, doc)
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+
+	_, err = w.Write(payload)
+
+	return err
+}
+
+// ReadRequest unmarshals JSON API document of HTTP request body into target like UnmarshalReader does,
+// after it checks the request Content-Type, see ErrUnsupportedMediaType.
+// The document size is limited by Options.MaxSize, DefaultRequestMaxSize when it is zero,
+// Options.Context is the request context unless it is set.
+// The errors may be written as error document with ErrorFromErr, e.g. 415 Unsupported Media Type
+// or 413 Request Entity Too Large.
+//
+// ReadRequest example:
+//
+//	var book Book
+//
+//	if _, err := jsonapi.ReadRequest(r, &book); err != nil {
+//	  _ = jsonapi.WriteErrors(w, r, []*jsonapi.ErrorObject{jsonapi.ErrorFromErr(err)})
+//	  return
+//	}
+func ReadRequest(r *http.Request, target interface{}, opts ...Option) (*Document, error) {
+	if err := checkContentType(r.Header.Get("Content-Type")); err != nil {
+		return &Document{}, err
+	}
+
+	options := newOptions(opts)
+
+	if options.MaxSize == 0 {
+		options.MaxSize = DefaultRequestMaxSize
+	}
+
+	if options.Context == nil {
+		options.Context = r.Context()
+	}
+
+	body := r.Body
+	if body == nil {
+		body = http.NoBody
+	}
+
+	return UnmarshalReader(body, target, WithOptions(options))
+}
+
+// checkContentType checks that content type is JSON API media type, having only JSON API 1.1 parameters.
+func checkContentType(contentType string) error {
+	if len(contentType) == 0 {
+		return fmt.Errorf("%w: Content-Type is missing", ErrUnsupportedMediaType)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != ContentType {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+
+	for name := range params {
+		if name != "ext" && name != "profile" {
+			return fmt.Errorf("%w: media type parameter %q", ErrUnsupportedMediaType, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Write", func() {
+
+	It("writes marshaled view with status and content type", func() {
+		w := httptest.NewRecorder()
+
+		err := Write(w, http.StatusCreated, Employee{ID: "1", Name: "Jane"}, WithFields(map[string][]string{"employees": {"name"}}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(w.Code).Should(Equal(http.StatusCreated))
+		Ω(w.Header().Get("Content-Type")).Should(Equal(ContentType))
+		Ω(w.Body.String()).Should(MatchJSON(`{"data": {"type": "employees", "id": "1", "attributes": {"name": "Jane"}}}`))
+	})
+
+	It("writes cache headers of the document cache hints", func() {
+		w := httptest.NewRecorder()
+
+		view := CachedBooksView{Books: []CachedBook{{
+			BookWithMeta: BookWithMeta{Book: Book{ID: "1", Title: "Go in Action", Type: "books"}},
+			MaxAge:       time.Minute,
+		}}}
+
+		err := Write(w, http.StatusOK, view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(w.Code).Should(Equal(http.StatusOK))
+		Ω(w.Header().Get("Cache-Control")).Should(Equal("max-age=60"))
+		Ω(w.Header().Get("Surrogate-Key")).Should(Equal("books books:1"))
+	})
+
+	It("writes nothing when the view fails to marshal", func() {
+		w := httptest.NewRecorder()
+
+		err := Write(w, http.StatusOK, RecipeView{Recipe: Recipe{ID: "1", Servings: -1}})
+
+		Ω(err).Should(HaveOccurred())
+		Ω(w.Header().Get("Content-Type")).Should(BeEmpty())
+		Ω(w.Body.Len()).Should(BeZero())
+	})
+
+	It("writes no content without body", func() {
+		w := httptest.NewRecorder()
+
+		err := Write(w, http.StatusNoContent, nil)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(w.Code).Should(Equal(http.StatusNoContent))
+		Ω(w.Body.Len()).Should(BeZero())
+	})
+})
+
+var _ = Describe("ReadRequest", func() {
+	body := `{"data": {"type": "recipes", "id": "1", "attributes": {"title": " Pancakes ", "servings": 4}}}`
+
+	request := func(contentType, body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/recipes", strings.NewReader(body))
+
+		if len(contentType) > 0 {
+			r.Header.Set("Content-Type", contentType)
+		}
+
+		return r
+	}
+
+	It("unmarshals request document into target", func() {
+		for _, contentType := range []string{
+			ContentType,
+			ContentType + `; ext="https://jsonapi.org/ext/atomic"`,
+			ContentType + `; profile="https://example.com/profiles/flexible"`,
+		} {
+			var recipe Recipe
+
+			_, err := ReadRequest(request(contentType, body), &recipe)
+
+			Ω(err).ShouldNot(HaveOccurred(), contentType)
+			Ω(recipe.Title).Should(Equal("Pancakes"), contentType)
+		}
+	})
+
+	It("passes request context to hooks", func() {
+		var recipe Recipe
+
+		r := request(ContentType, body)
+		r = r.WithContext(context.WithValue(r.Context(), hookKey{}, "request"))
+
+		_, err := ReadRequest(r, &recipe)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(recipe.Hooked).Should(Equal("request"))
+	})
+
+	It("rejects unsupported media types", func() {
+		for _, contentType := range []string{
+			"",
+			"application/json",
+			ContentType + "; charset=utf-8",
+			"not a media type;",
+		} {
+			var recipe Recipe
+
+			_, err := ReadRequest(request(contentType, body), &recipe)
+
+			Ω(err).Should(MatchError(ErrUnsupportedMediaType), contentType)
+			Ω(ErrorFromErr(err).Status).Should(Equal("415"), contentType)
+		}
+	})
+
+	It("limits the document size", func() {
+		var recipe Recipe
+
+		_, err := ReadRequest(request(ContentType, body), &recipe, WithMaxSize(16))
+
+		Ω(err).Should(MatchError(ErrDocumentTooLarge))
+		Ω(ErrorFromErr(err).Status).Should(Equal("413"))
+
+		_, err = ReadRequest(request(ContentType, `{"data": {"type": "recipes", "id": "1", "attributes": {"title": "`+strings.Repeat("a", int(DefaultRequestMaxSize))+`"}}}`), &recipe)
+
+		Ω(err).Should(MatchError(ErrDocumentTooLarge))
+	})
+
+	It("rejects empty body", func() {
+		var recipe Recipe
+
+		_, err := ReadRequest(request(ContentType, ""), &recipe)
+
+		Ω(err).Should(HaveOccurred())
+		Ω(ErrorFromErr(err).Status).Should(Equal("400"))
+	})
+})
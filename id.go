@@ -0,0 +1,151 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrInvalidID returned when resource ID can't be converted from or into the Go value, the error is wrapped with the details.
+var ErrInvalidID = errors.New("jsonapi: invalid resource id")
+
+// FormatID converts numeric, UUID and other primary keys into resource ID: integers are formatted in base 10,
+// encoding.TextMarshaler and fmt.Stringer values, e.g. UUIDs, are formatted by them, zero integers, nil UUID
+// and nil pointers give empty ID, which is then marshaled according to Options.EmptyID.
+//
+// FormatID example:
+//
+//	func(b Book) GetID() string {
+//	  id, _ := jsonapi.FormatID(b.ID)
+//	  return id
+//	}
+func FormatID(v interface{}) (string, error) {
+	if isNil(v) {
+		return "", nil
+	}
+
+	switch id := v.(type) {
+	case string:
+		return id, nil
+	case UUID:
+		if id.IsZero() {
+			return "", nil
+		}
+
+		return id.String(), nil
+	case encoding.TextMarshaler:
+		text, err := id.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidID, err)
+		}
+
+		return string(text), nil
+	case fmt.Stringer:
+		return id.String(), nil
+	}
+
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() == 0 {
+			return "", nil
+		}
+
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() == 0 {
+			return "", nil
+		}
+
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Ptr:
+		return FormatID(value.Elem().Interface())
+	}
+
+	return "", fmt.Errorf("%w: %T can't be resource id", ErrInvalidID, v)
+}
+
+// ParseID converts resource ID into the primary key target points to: integers are parsed in base 10
+// with range checks, encoding.TextUnmarshaler targets, e.g. UUIDs, parse it themselves. Empty ID leaves the target unchanged.
+//
+// ParseID example:
+//
+//	func(b *Book) SetID(id string) error {
+//	  return jsonapi.ParseID(id, &b.ID)
+//	}
+func ParseID(id string, target interface{}) error {
+	if len(id) == 0 {
+		return nil
+	}
+
+	if tu, ok := target.(encoding.TextUnmarshaler); ok {
+		if err := tu.UnmarshalText([]byte(id)); err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidID, id, err)
+		}
+
+		return nil
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("%w: %T isn't a pointer", ErrInvalidID, target)
+	}
+
+	return setID(value.Elem(), id)
+}
+
+func setID(field reflect.Value, id string) error {
+	if field.CanAddr() {
+		if tu, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if len(id) == 0 {
+				return nil
+			}
+
+			if err := tu.UnmarshalText([]byte(id)); err != nil {
+				return fmt.Errorf("%w: %q: %v", ErrInvalidID, id, err)
+			}
+
+			return nil
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(id)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if len(id) == 0 {
+			return nil
+		}
+
+		n, err := strconv.ParseInt(id, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidID, id, err)
+		}
+
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if len(id) == 0 {
+			return nil
+		}
+
+		n, err := strconv.ParseUint(id, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidID, id, err)
+		}
+
+		field.SetUint(n)
+	default:
+		return fmt.Errorf("%w: %s can't hold resource id", ErrInvalidID, field.Type())
+	}
+
+	return nil
+}
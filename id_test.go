@@ -0,0 +1,185 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type SKU struct {
+	Prefix, Number string
+}
+
+func (s SKU) MarshalText() ([]byte, error) {
+	return []byte(s.Prefix + "-" + s.Number), nil
+}
+
+func (s *SKU) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), "-", 2)
+	if len(parts) != 2 {
+		return errors.New("sku must be prefix-number")
+	}
+
+	s.Prefix, s.Number = parts[0], parts[1]
+
+	return nil
+}
+
+type Gizmo struct {
+	ID    int64  `json:"-" jsonapi:"id,type=gizmos"`
+	Label string `json:"label"`
+}
+
+type GizmoView struct {
+	Gizmo Gizmo
+}
+
+func (v GizmoView) GetData() interface{} {
+	return v.Gizmo
+}
+
+func (v *GizmoView) SetData(to func(target interface{}) error) error {
+	return to(&v.Gizmo)
+}
+
+type Sprocket struct {
+	ID *UUID `json:"-" jsonapi:"id,type=sprockets"`
+}
+
+type Assembly struct {
+	ID       string    `json:"-" jsonapi:"id,type=assemblies"`
+	Sprocket *Sprocket `json:"-" jsonapi:"relationship=sprocket"`
+}
+
+type AssemblyView struct {
+	Assembly Assembly
+}
+
+func (v AssemblyView) GetData() interface{} {
+	return v.Assembly
+}
+
+var _ = Describe("Resource IDs", func() {
+
+	It("formats primary keys", func() {
+		for v, expected := range map[interface{}]string{
+			"a1":             "a1",
+			int64(42):        "42",
+			uint8(7):         "7",
+			0:                "",
+			SKU{"AB", "12"}:  "AB-12",
+			&SKU{"CD", "34"}: "CD-34",
+		} {
+			id, err := FormatID(v)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(id).Should(Equal(expected))
+		}
+
+		_, err := FormatID(1.5)
+		Ω(err).Should(MatchError(ErrInvalidID))
+	})
+
+	It("formats nil and pointer primary keys", func() {
+		var (
+			uuid *UUID
+			sku  *SKU
+			n    *int64
+		)
+
+		seven := int64(7)
+
+		for v, expected := range map[interface{}]string{
+			uuid:   "",
+			sku:    "",
+			n:      "",
+			&seven: "7",
+		} {
+			id, err := FormatID(v)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(id).Should(Equal(expected))
+		}
+	})
+
+	It("marshals nil pointer id according to Options.EmptyID", func() {
+		view := AssemblyView{Assembly: Assembly{ID: "1", Sprocket: &Sprocket{}}}
+
+		result, err := Marshal(view)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`
+      {
+        "data": {
+          "type": "assemblies",
+          "id": "1",
+          "relationships": {"sprocket": {"data": null}}
+        }
+      }
+    `))
+
+		result, err = Marshal(view, WithEmptyID(EmptyIDKeep))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`
+      {
+        "data": {
+          "type": "assemblies",
+          "id": "1",
+          "relationships": {"sprocket": {"data": {"type": "sprockets"}}}
+        }
+      }
+    `))
+
+		_, err = Marshal(view, WithEmptyID(EmptyIDError))
+		Ω(err).Should(MatchError(ErrEmptyID))
+	})
+
+	It("parses primary keys", func() {
+		var (
+			n   int64
+			u   uint16
+			s   string
+			sku SKU
+		)
+
+		Ω(ParseID("42", &n)).Should(Succeed())
+		Ω(ParseID("7", &u)).Should(Succeed())
+		Ω(ParseID("a1", &s)).Should(Succeed())
+		Ω(ParseID("AB-12", &sku)).Should(Succeed())
+		Ω(ParseID("", &n)).Should(Succeed())
+
+		Ω(n).Should(Equal(int64(42)))
+		Ω(u).Should(Equal(uint16(7)))
+		Ω(s).Should(Equal("a1"))
+		Ω(sku).Should(Equal(SKU{"AB", "12"}))
+
+		Ω(ParseID("abc", &n)).Should(MatchError(ErrInvalidID))
+		Ω(ParseID("70000", &u)).Should(MatchError(ErrInvalidID))
+		Ω(ParseID("AB", &sku)).Should(MatchError(ErrInvalidID))
+		Ω(ParseID("1", n)).Should(MatchError(ErrInvalidID))
+	})
+
+	It("converts numeric id of tagged struct", func() {
+		result, err := Marshal(GizmoView{Gizmo: Gizmo{ID: 42, Label: "Lever"}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "gizmos", "id": "42", "attributes": {"label": "Lever"}}}`))
+
+		view := GizmoView{}
+
+		_, err = Unmarshal([]byte(`{"data": {"type": "gizmos", "id": "43", "attributes": {"label": "Lever"}}}`), &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Gizmo.ID).Should(Equal(int64(43)))
+
+		_, err = Unmarshal([]byte(`{"data": {"type": "gizmos", "id": "x"}}`), &view)
+
+		Ω(err).Should(MatchError(ErrInvalidID))
+	})
+})
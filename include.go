@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"strings"
+)
+
+func filterIncluded(doc *Document, include []string) []*ResourceObject {
+	index := make(map[ResourceObjectIdentifier]*ResourceObject, len(doc.Included))
+
+	for _, ro := range doc.Included {
+		index[ro.ResourceObjectIdentifier] = ro
+	}
+
+	var primary []*ResourceObject
+
+	if doc.Data != nil {
+		if doc.Data.One != nil {
+			primary = append(primary, doc.Data.One)
+		}
+
+		primary = append(primary, doc.Data.Many...)
+	}
+
+	requested := map[ResourceObjectIdentifier]bool{}
+
+	for _, path := range include {
+		current := primary
+
+		for _, name := range strings.Split(path, ".") {
+			var next []*ResourceObject
+
+			for _, ro := range current {
+				for _, roi := range relationshipIdentifiers(ro, name) {
+					if related, ok := index[*roi]; ok {
+						requested[*roi] = true
+						next = append(next, related)
+					}
+				}
+			}
+
+			current = next
+		}
+	}
+
+	var included []*ResourceObject
+
+	for _, ro := range doc.Included {
+		if requested[ro.ResourceObjectIdentifier] {
+			included = append(included, ro)
+		}
+	}
+
+	return included
+}
+
+func relationshipIdentifiers(ro *ResourceObject, name string) []*ResourceObjectIdentifier {
+	rel, ok := ro.Relationships[name]
+	if !ok || rel == nil || rel.Data == nil {
+		return nil
+	}
+
+	if rel.Data.One != nil {
+		return []*ResourceObjectIdentifier{rel.Data.One}
+	}
+
+	return rel.Data.Many
+}
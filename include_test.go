@@ -0,0 +1,121 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Include", func() {
+
+	view := BookWithAuthorIncludedView{
+		BookWithAuthorView: BookWithAuthorView{
+			Book: BookWithAuthor{
+				Book: Book{
+					ID:    "1",
+					Title: "An Introduction to Programming in Go",
+					Year:  "2012",
+					Type:  "books",
+				},
+				Author: Author{
+					ID:   "1",
+					Name: "Caleb Doxsey",
+				},
+			},
+		},
+	}
+
+	It("marshals included resources of requested paths", func() {
+		result, err := MarshalWithOptions(view, Options{Include: []string{"author"}})
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "title": "An Introduction to Programming in Go",
+            "year": "2012"
+          },
+          "relationships": {
+            "author": {
+              "data": { "type": "authors", "id": "1" }
+            }
+          }
+        },
+        "included": [
+          {
+            "type": "authors",
+            "id": "1",
+            "attributes": {
+              "name": "Caleb Doxsey"
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("omits included resources of not requested paths", func() {
+		result, err := MarshalWithOptions(view, Options{Include: []string{"readers"}})
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "title": "An Introduction to Programming in Go",
+            "year": "2012"
+          },
+          "relationships": {
+            "author": {
+              "data": { "type": "authors", "id": "1" }
+            }
+          }
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("follows requested paths even when sparse fieldsets omit relationships", func() {
+		result, err := MarshalWithOptions(view, Options{
+			Include: []string{"author"},
+			Fields:  map[string][]string{"books": {"title"}},
+		})
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "title": "An Introduction to Programming in Go"
+          }
+        },
+        "included": [
+          {
+            "type": "authors",
+            "id": "1",
+            "attributes": {
+              "name": "Caleb Doxsey"
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"sync"
+)
+
+// Interner deduplicates resource type strings, and optionally relationship keys, of unmarshaled documents,
+// so documents of thousands of resources of few types kept by long-lived client caches and stores
+// don't hold thousands of copies of the same strings. It is safe for concurrent use.
+//
+// Interner example:
+//
+//	var interner = jsonapi.NewInterner(true)
+//
+//	_, err := jsonapi.Unmarshal(data, &view, jsonapi.WithInterner(interner))
+//
+// The interned strings are kept for the Interner lifetime, it should be shared by the documents of the same API
+// rather than by arbitrary ones.
+type Interner struct {
+	keys bool
+
+	mu      sync.RWMutex
+	strings map[string]string
+}
+
+// NewInterner creates Interner of resource types, it interns relationship keys as well when keys is true.
+func NewInterner(keys bool) *Interner {
+	return &Interner{
+		keys:    keys,
+		strings: map[string]string{},
+	}
+}
+
+// Intern returns the interned string equal to s.
+func (in *Interner) Intern(s string) string {
+	in.mu.RLock()
+	interned, ok := in.strings[s]
+	in.mu.RUnlock()
+
+	if ok {
+		return interned
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if interned, ok := in.strings[s]; ok {
+		return interned
+	}
+
+	in.strings[s] = s
+
+	return s
+}
+
+// Len returns number of the interned strings.
+func (in *Interner) Len() int {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+
+	return len(in.strings)
+}
+
+// internDocument replaces resource types, linkage types and relationship keys of the document with the interned ones.
+func internDocument(doc *Document, in *Interner) {
+	for _, ro := range documentResources(doc) {
+		ro.Type = in.Intern(ro.Type)
+
+		if in.keys && len(ro.Relationships) > 0 {
+			relationships := make(map[string]*relationship, len(ro.Relationships))
+
+			for key, rel := range ro.Relationships {
+				relationships[in.Intern(key)] = rel
+			}
+
+			ro.Relationships = relationships
+		}
+
+		for _, rel := range ro.Relationships {
+			if rel == nil || rel.Data == nil {
+				continue
+			}
+
+			if rel.Data.One != nil {
+				rel.Data.One.Type = in.Intern(rel.Data.One.Type)
+			}
+
+			for _, roi := range rel.Data.Many {
+				roi.Type = in.Intern(roi.Type)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"reflect"
+	"unsafe"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+var _ = Describe("Interner", func() {
+
+	payload := []byte(`{
+		"data": [
+			{"type": "books", "id": "1", "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}},
+			{"type": "books", "id": "2", "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}}
+		]
+	}`)
+
+	It("interns resource and linkage types", func() {
+		interner := NewInterner(false)
+
+		doc, err := Unmarshal(payload, &BooksView{}, WithInterner(interner))
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		first, second := doc.Data.Many[0], doc.Data.Many[1]
+		Ω(stringData(first.Type)).Should(Equal(stringData(second.Type)))
+		Ω(stringData(first.Relationships["author"].Data.One.Type)).Should(Equal(stringData(second.Relationships["author"].Data.One.Type)))
+		Ω(interner.Len()).Should(Equal(2))
+	})
+
+	It("interns relationship keys when it is asked", func() {
+		interner := NewInterner(true)
+
+		_, err := Unmarshal(payload, &BooksView{}, WithInterner(interner))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(interner.Len()).Should(Equal(3))
+	})
+
+	It("returns the same string for equal ones", func() {
+		interner := NewInterner(false)
+
+		a := interner.Intern(string([]byte("books")))
+		b := interner.Intern(string([]byte("books")))
+
+		Ω(a).Should(Equal(b))
+		Ω(stringData(a)).Should(Equal(stringData(b)))
+	})
+})
@@ -7,12 +7,29 @@ package jsonapi
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 )
 
 // ContentType describes data content type.
 const ContentType = "application/vnd.api+json"
 
+// ErrEmptyID returned when to-one relationship linkage has neither ID nor LID and Options.EmptyID is EmptyIDError.
+var ErrEmptyID = errors.New("jsonapi: resource identifier has empty id")
+
+// ErrInvalidRawAttributes returned when raw attributes of MarshalRawAttributes aren't JSON object.
+var ErrInvalidRawAttributes = errors.New("jsonapi: raw attributes must be JSON object")
+
+// ErrNilResource returned when resources collection contains nil resource and Options.SkipNil is not set,
+// the error is wrapped with pointer to the resource.
+var ErrNilResource = errors.New("jsonapi: nil resource")
+
+// ErrDataAndErrors returned in strict mode, by DocumentBuilder and MergeDocuments when JSON API document contains both data and errors.
+var ErrDataAndErrors = errors.New("jsonapi: document must not contain both data and errors")
+
 // MarshalResourceIdentifier interface should be implemented to be able marshal Go struct into JSON API document.
 //
 // GetID example:
@@ -63,6 +80,32 @@ type UnmarshalResourceIdentifier interface {
 	SetType(string) error
 }
 
+// MarshalLocalIdentifier interface may be implemented along with MarshalResourceIdentifier to marshal resource local identifier (lid),
+// which identifies a resource not having ID yet within the JSON API document, e.g. when it is being created.
+//
+// GetLID example:
+//
+//    func(s SomeStruct) GetLID() string {
+//      return s.LID
+//    }
+//
+type MarshalLocalIdentifier interface {
+	GetLID() string
+}
+
+// UnmarshalLocalIdentifier interface may be implemented along with UnmarshalResourceIdentifier to unmarshal resource local identifier (lid).
+//
+// SetLID example:
+//
+//    func(s *SomeStruct) SetLID(lid string) error {
+//      s.LID = lid
+//      return nil
+//    }
+//
+type UnmarshalLocalIdentifier interface {
+	SetLID(string) error
+}
+
 // MarshalRelationships interface should be implemented to be able marshal JSON API document relationships.
 //
 // GetRelationships example:
@@ -78,26 +121,124 @@ type UnmarshalResourceIdentifier interface {
 //      return relationships
 //    }
 //
+// Relationship values may be pointers, nil and nil pointer are marshaled as empty to-one relationship, "data": null.
+// ResourceObjectIdentifier values, pointers and slices of them are accepted as is, when only IDs are at hand.
+// Relationships are marshaled in sorted keys order, so documents are byte-stable between runs.
+//
 type MarshalRelationships interface {
 	GetRelationships() map[string]interface{}
 }
 
+// MarshalAttributes interface may be implemented to marshal attributes given as a map instead of the struct fields,
+// e.g. by types which attribute set is dynamic, like user-defined fields.
+//
+// GetAttributes example:
+//
+//    func(s SomeStruct) GetAttributes() map[string]interface{} {
+//      attributes := map[string]interface{}{"name": s.Name}
+//
+//      for key, value := range s.CustomFields {
+//        attributes[key] = value
+//      }
+//
+//      return attributes
+//    }
+//
+type MarshalAttributes interface {
+	GetAttributes() map[string]interface{}
+}
+
+// UnmarshalAttributes interface may be implemented to unmarshal attributes into a map instead of the struct fields.
+//
+// SetAttributes example:
+//
+//    func(s *SomeStruct) SetAttributes(attributes map[string]interface{}) error {
+//      s.CustomFields = attributes
+//      return nil
+//    }
+//
+type UnmarshalAttributes interface {
+	SetAttributes(map[string]interface{}) error
+}
+
+// MarshalRawAttributes interface may be implemented to marshal pre-encoded attributes as is,
+// e.g. stored in JSONB column, without decoding and encoding them again. Empty or null attributes are omitted.
+//
+// GetRawAttributes example:
+//
+//    func(s SomeStruct) GetRawAttributes() json.RawMessage {
+//      return s.Document
+//    }
+//
+type MarshalRawAttributes interface {
+	GetRawAttributes() json.RawMessage
+}
+
+// UnmarshalRawAttributes interface may be implemented to unmarshal attributes as raw JSON object, they are not decoded.
+//
+// SetRawAttributes example:
+//
+//    func(s *SomeStruct) SetRawAttributes(attributes json.RawMessage) error {
+//      s.Document = append(json.RawMessage{}, attributes...)
+//      return nil
+//    }
+//
+type UnmarshalRawAttributes interface {
+	SetRawAttributes(json.RawMessage) error
+}
+
+// MarshalRelationshipCounts interface should be implemented to add count of related resources
+// into to-many relationships meta, e.g. "relationships": {"comments": {"meta": {"count": 42}}}.
+// A counted relationship missing from GetRelationships is marshaled with meta only,
+// so large collections don't have to be loaded.
+//
+// GetRelationshipCounts example:
+//
+//    func(s SomeStruct) GetRelationshipCounts() map[string]int {
+//      return map[string]int{
+//        "comments": s.CommentsCount,
+//      }
+//    }
+//
+type MarshalRelationshipCounts interface {
+	GetRelationshipCounts() map[string]int
+}
+
 // UnmarshalRelationships interface should be implemented to be able unmarshal JSON API document relationships into Go struct.
 //
 // SetRelationships example:
 //
 //    func (s *SomeStruct) SetRelationships(relationships map[string]interface{}) error {
-//    	if relationship, ok := relationships["relation"]; ok {
-//    		s.RealtionID = relationship.(*jsonapi.ResourceObjectIdentifier).ID
+//    	if relationship, ok := jsonapi.ToOne(relationships, "relation"); ok {
+//    		s.RealtionID = relationship.ID
 //    	}
 //
 //    	return nil
 //    }
 //
+// ToOne and ToMany return false instead of panicking when the relationship linkage is missing or malformed.
+//
 type UnmarshalRelationships interface {
 	SetRelationships(map[string]interface{}) error
 }
 
+// UnmarshalTypedRelationships interface may be implemented instead of UnmarshalRelationships
+// to receive relationships as Relationship values, without type assertions.
+//
+// SetRelationships example:
+//
+//    func (s *SomeStruct) SetRelationships(relationships map[string]jsonapi.Relationship) error {
+//    	if author := relationships["author"]; author.One != nil {
+//    		s.AuthorID = author.One.ID
+//    	}
+//
+//    	return nil
+//    }
+//
+type UnmarshalTypedRelationships interface {
+	SetRelationships(map[string]Relationship) error
+}
+
 // MarshalData interface should be implemented to be able get data from Go struct and marshal it.
 //
 // GetData example:
@@ -235,6 +376,20 @@ type MarshalMeta interface {
 	GetMeta() interface{}
 }
 
+// MarshalLinks interface should be implemented to be able marshal JSON API document links.
+//
+// GetLinks example:
+//
+//    func(v SomeView) GetLinks() jsonapi.Links {
+//      return jsonapi.Links{
+//        "self": {Href: "/books?page[number]=2"},
+//      }
+//    }
+//
+type MarshalLinks interface {
+	GetLinks() Links
+}
+
 // Document describes Go representation of JSON API document.
 type Document struct {
 	// Document data
@@ -245,6 +400,8 @@ type Document struct {
 	Included []*ResourceObject `json:"included,omitempty"`
 	// Document meta
 	Meta json.RawMessage `json:"meta,omitempty"`
+	// Document links
+	Links Links `json:"links,omitempty"`
 }
 
 type documentData struct {
@@ -253,7 +410,32 @@ type documentData struct {
 }
 
 type relationship struct {
-	Data *relationshipData `json:"data"`
+	Data  *relationshipData `json:"data,omitempty"`
+	Meta  json.RawMessage   `json:"meta,omitempty"`
+	Links Links             `json:"links,omitempty"`
+
+	// null tells the unmarshaled relationship data is null, Data is nil then as for missing data.
+	null bool
+}
+
+type relationshipObject relationship
+
+func (r *relationship) UnmarshalJSON(payload []byte) error {
+	var members struct {
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(payload, &members); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(payload, (*relationshipObject)(r)); err != nil {
+		return err
+	}
+
+	r.null = bytes.Equal(members.Data, []byte("null"))
+
+	return nil
 }
 
 type relationshipData struct {
@@ -265,6 +447,10 @@ type relationshipData struct {
 type ResourceObjectIdentifier struct {
 	Type string `json:"type"`
 	ID   string `json:"id,omitempty"`
+	// LID identifies the resource locally within the document when the resource has no ID yet (JSON API 1.1).
+	LID string `json:"lid,omitempty"`
+	// Missing tells relationship linkage refers to a resource which was not found, marshaled as "meta": {"exists": false}.
+	Missing bool `json:"-"`
 }
 
 // GetID method returns ResourceObjectIdentifier ID.
@@ -272,6 +458,11 @@ func (roi ResourceObjectIdentifier) GetID() string {
 	return roi.ID
 }
 
+// GetLID method returns ResourceObjectIdentifier LID.
+func (roi ResourceObjectIdentifier) GetLID() string {
+	return roi.LID
+}
+
 // GetType method returns ResourceObjectIdentifier Type.
 func (roi ResourceObjectIdentifier) GetType() string {
 	return roi.Type
@@ -286,22 +477,56 @@ type ResourceObject struct {
 	Meta json.RawMessage `json:"meta,omitempty"`
 	// Relationships JSON API document relationships raw data.
 	Relationships map[string]*relationship `json:"relationships,omitempty"`
+	// Links JSON API resource links, e.g. "self".
+	Links Links `json:"links,omitempty"`
+
+	// emptyRelationships forces empty "relationships" object, see Options.EmptyRelationships.
+	emptyRelationships bool
+}
+
+type resourceObject ResourceObject
+
+// MarshalJSON marshals the resource object, empty relationships are omitted unless they are forced.
+func (ro *ResourceObject) MarshalJSON() ([]byte, error) {
+	if !ro.emptyRelationships || len(ro.Relationships) > 0 {
+		return encodeRaw((*resourceObject)(ro))
+	}
+
+	return encodeRaw(struct {
+		*resourceObject
+		Relationships map[string]*relationship `json:"relationships"`
+	}{
+		resourceObject: (*resourceObject)(ro),
+		Relationships:  map[string]*relationship{},
+	})
 }
 
 // ErrorObject JSON API error object https://jsonapi.org/format/#error-objects
 type ErrorObject struct {
+	// Status the HTTP status code applicable to this problem, expressed as a string value.
+	Status string `json:"status,omitempty"`
 	// Title a short, human-readable summary of the problem.
 	Title string `json:"title,omitempty"`
+	// Detail a human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
 	// Code application specified value to identify the error.
 	Code string `json:"code,omitempty"`
 	// Source an object containing references to the source of the error.
 	Source ErrorObjectSource `json:"source,omitempty"`
+	// Meta non-standard meta-information about the error.
+	Meta map[string]interface{} `json:"meta,omitempty"`
+	// Links "about" link leading to further details about the problem and "type" link identifying its type (JSON API 1.1).
+	Links Links `json:"links,omitempty"`
 }
 
 // ErrorObjectSource includes pointer ErrorObject.Source
 type ErrorObjectSource struct {
 	// Pointer a JSON Pointer [RFC6901] to the associated entity in the request document [e.g. "/data" for a primary data object, or "/data/attributes/title" for a specific attribute].
 	Pointer string `json:"pointer,omitempty"`
+	// Parameter a string indicating which URI query parameter caused the error.
+	Parameter string `json:"parameter,omitempty"`
+	// Header a string indicating the name of a single request header which caused the error.
+	Header string `json:"header,omitempty"`
 }
 
 func (d *documentData) MarshalJSON() ([]byte, error) {
@@ -323,44 +548,147 @@ func (d *documentData) MarshalJSON() ([]byte, error) {
 }
 
 func (d *documentData) UnmarshalJSON(payload []byte) error {
-	if bytes.HasPrefix(payload, []byte("{")) {
+	switch jsonDelim(payload) {
+	case '{':
 		return json.Unmarshal(payload, &d.One)
-	}
-
-	if bytes.HasPrefix(payload, []byte("[")) {
+	case '[':
 		return json.Unmarshal(payload, &d.Many)
 	}
 
 	return nil
 }
 
+// jsonDelim returns the delimiter the JSON value begins with, '{' or '[', ignoring leading whitespace,
+// it returns zero for the other values.
+func jsonDelim(payload []byte) json.Delim {
+	token, err := json.NewDecoder(bytes.NewReader(payload)).Token()
+	if err != nil {
+		return 0
+	}
+
+	delim, _ := token.(json.Delim)
+
+	return delim
+}
+
 func (d *relationshipData) MarshalJSON() ([]byte, error) {
-	if d.One != nil && len(d.One.ID) > 0 {
-		return json.Marshal(d.One)
+	if d.One != nil {
+		return json.Marshal(newIdentifierObject(d.One))
+	}
+
+	if d.Many == nil {
+		return json.Marshal(nil)
+	}
+
+	many := make([]*identifierObject, 0, len(d.Many))
+
+	for _, roi := range d.Many {
+		many = append(many, newIdentifierObject(roi))
 	}
-	return json.Marshal(d.Many)
+
+	return json.Marshal(many)
 }
 
 func (d *relationshipData) UnmarshalJSON(payload []byte) error {
-	if bytes.HasPrefix(payload, []byte("{")) {
-		return json.Unmarshal(payload, &d.One)
-	}
+	switch jsonDelim(payload) {
+	case '{':
+		one := &identifierObject{}
 
-	if bytes.HasPrefix(payload, []byte("[")) {
-		return json.Unmarshal(payload, &d.Many)
+		if err := json.Unmarshal(payload, one); err != nil {
+			return err
+		}
+
+		d.One = one.identifier()
+	case '[':
+		var many []*identifierObject
+
+		if err := json.Unmarshal(payload, &many); err != nil {
+			return err
+		}
+
+		d.Many = make([]*ResourceObjectIdentifier, 0, len(many))
+
+		for _, one := range many {
+			d.Many = append(d.Many, one.identifier())
+		}
 	}
 
 	return nil
 }
 
+// identifierObject is JSON representation of relationship linkage resource identifier, including its meta.
+type identifierObject struct {
+	Type string          `json:"type"`
+	ID   string          `json:"id,omitempty"`
+	LID  string          `json:"lid,omitempty"`
+	Meta *identifierMeta `json:"meta,omitempty"`
+}
+
+type identifierMeta struct {
+	Exists *bool `json:"exists,omitempty"`
+}
+
+func newIdentifierObject(roi *ResourceObjectIdentifier) *identifierObject {
+	if roi == nil {
+		return nil
+	}
+
+	one := &identifierObject{Type: roi.Type, ID: roi.ID, LID: roi.LID}
+
+	if roi.Missing {
+		exists := false
+		one.Meta = &identifierMeta{Exists: &exists}
+	}
+
+	return one
+}
+
+func (one *identifierObject) identifier() *ResourceObjectIdentifier {
+	if one == nil {
+		return nil
+	}
+
+	roi := &ResourceObjectIdentifier{Type: one.Type, ID: one.ID, LID: one.LID}
+
+	if one.Meta != nil && one.Meta.Exists != nil && !*one.Meta.Exists {
+		roi.Missing = true
+	}
+
+	return roi
+}
+
 // Marshal serialize Go struct into []byte JSON API document
 // If the corresponding interfaces are implemented the output will contain, relationships, included, meta and errors.
-func Marshal(payload interface{}) ([]byte, error) {
-	var (
-		doc *Document
-		err error
-	)
+// Marshal behavior may be changed by options, e.g. jsonapi.Marshal(view, jsonapi.WithInclude("author")).
+func Marshal(payload interface{}, opts ...Option) ([]byte, error) {
+	return MarshalWithOptions(payload, newOptions(opts))
+}
+
+// MarshalWithOptions serialize Go struct into []byte JSON API document like Marshal does, using given options.
+func MarshalWithOptions(payload interface{}, opts Options) ([]byte, error) {
+	return marshal(payload, &opts, "", "")
+}
+
+// MarshalIndent serialize Go struct into []byte JSON API document like Marshal does,
+// each JSON element begins on a new line starting with prefix followed by copies of indent according to the nesting.
+// It is intended for debugging, logs and documentation examples.
+func MarshalIndent(payload interface{}, prefix, indent string, opts ...Option) ([]byte, error) {
+	options := newOptions(opts)
+
+	return marshal(payload, &options, prefix, indent)
+}
+
+func marshal(payload interface{}, opts *Options, prefix, indent string) ([]byte, error) {
+	doc, err := marshalPayload(payload, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeMarshaled(doc, opts, prefix, indent)
+}
 
+// marshalPayload returns JSON API document of the payload, the one pointer points to is marshaled for a pointer.
+func marshalPayload(payload interface{}, opts *Options) (*Document, error) {
 	val := reflect.ValueOf(payload)
 	i := val.Interface()
 
@@ -369,21 +697,42 @@ func Marshal(payload interface{}) ([]byte, error) {
 		i = val.Interface()
 	}
 
-	doc, err = marshalDocument(i)
+	doc, err := marshalDocument(i, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := checkVersion(doc, nil, opts.Version); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// encodeMarshaled encodes the document produced by marshalPayload according to the options.
+func encodeMarshaled(doc *Document, opts *Options, prefix, indent string) ([]byte, error) {
+	if opts.Compact {
+		prefix, indent = "", ""
+	}
+
 	buf := &bytes.Buffer{}
 	enc := json.NewEncoder(buf)
-	enc.SetEscapeHTML(false)
+	// the document encoder escapes the members encoded beforehand, e.g. attributes and meta, as well
+	enc.SetEscapeHTML(opts.EscapeHTML)
+	enc.SetIndent(prefix, indent)
 
-	err = enc.Encode(doc)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
 
-	return buf.Bytes(), err
+	if opts.Compact {
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+
+	return buf.Bytes(), nil
 }
 
-func marshalDocument(payload interface{}) (*Document, error) {
+func marshalDocument(payload interface{}, opts *Options) (*Document, error) {
 	doc := &Document{}
 
 	switch asserted := payload.(type) {
@@ -392,32 +741,77 @@ func marshalDocument(payload interface{}) (*Document, error) {
 
 		data := asserted.GetData()
 
-		switch reflect.TypeOf(data).Kind() {
-		case reflect.Struct:
-			if one, err := marshalResourceObject(data.(MarshalResourceIdentifier)); err == nil {
+		if me, ok := payload.(MarshalErrors); ok && opts.Strict && len(me.GetErrors()) > 0 {
+			return nil, ErrDataAndErrors
+		}
+
+		value := reflect.ValueOf(data)
+
+		// pointer to resources collection is marshaled as the collection
+		if value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Slice {
+			data, value = value.Elem().Interface(), value.Elem()
+		}
+
+		// nil resource is marshaled as null
+		switch kind := value.Kind(); {
+		case kind == reflect.Struct || (kind == reflect.Ptr && !value.IsNil()):
+			mri, err := resourceIdentifier(data)
+			if err != nil {
+				return nil, err
+			}
+
+			if one, err := marshalResourceObject(mri, opts); err == nil {
 				doc.Data.One = &one
 			} else {
 				return nil, err
 			}
-		case reflect.Slice:
-			if many, err := marshalResourceObjects(data); err == nil {
+		case kind == reflect.Slice:
+			if many, err := marshalResourceObjects(data, opts); err == nil {
 				doc.Data.Many = many
 			} else {
 				return nil, err
 			}
 		}
 	case MarshalErrors:
-		doc.Errors = asserted.GetErrors()
+		errs := TranslateErrors(asserted.GetErrors(), opts.Locale, opts.ErrorTranslator)
+		doc.Errors = stampRequestID(FormatErrors(errs), opts.RequestID)
 	}
 
-	if mi, ok := payload.(MarshalIncluded); ok {
-		if included, err := marshalIncluded(mi); err == nil {
-			doc.Included = included
-		} else {
+	failures, err := marshalDocumentIncluded(payload, doc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Strict {
+		if err := validateMemberNames(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Include != nil {
+		doc.Included = filterIncluded(doc, opts.Include)
+	}
+
+	if opts.Fields != nil {
+		if err := filterDocumentFields(doc, opts.Fields); err != nil {
 			return nil, err
 		}
 	}
 
+	if opts.EmptyAttributes {
+		for _, ro := range documentResources(doc) {
+			if len(ro.Attributes) == 0 {
+				ro.Attributes = json.RawMessage("{}")
+			}
+		}
+	}
+
+	if opts.EmptyRelationships {
+		for _, ro := range documentResources(doc) {
+			ro.emptyRelationships = true
+		}
+	}
+
 	if mm, ok := payload.(MarshalMeta); ok {
 		if meta, err := marshalMeta(mm); err == nil {
 			if !bytes.Equal(meta, []byte("{}\n")) {
@@ -428,102 +822,435 @@ func marshalDocument(payload interface{}) (*Document, error) {
 		}
 	}
 
+	if len(failures) > 0 {
+		meta, err := marshalPartial(doc.Meta, failures)
+		if err != nil {
+			return nil, err
+		}
+
+		doc.Meta = meta
+	}
+
+	if ml, ok := payload.(MarshalLinks); ok {
+		if links := ml.GetLinks(); len(links) > 0 {
+			doc.Links = links
+		}
+	}
+
 	return doc, nil
 }
 
 func marshalResourceObjectIdentifier(mri MarshalResourceIdentifier) ResourceObjectIdentifier {
-	return ResourceObjectIdentifier{ID: mri.GetID(), Type: mri.GetType()}
-}
+	roi := ResourceObjectIdentifier{ID: mri.GetID(), Type: mri.GetType()}
 
-func marshalResourceObject(mri MarshalResourceIdentifier) (ResourceObject, error) {
-	one := ResourceObject{
-		ResourceObjectIdentifier: marshalResourceObjectIdentifier(mri),
+	if ml, ok := unwrapResource(mri).(MarshalLocalIdentifier); ok {
+		roi.LID = ml.GetLID()
+	} else if ml, ok := mri.(MarshalLocalIdentifier); ok {
+		roi.LID = ml.GetLID()
 	}
 
-	buf := &bytes.Buffer{}
-	enc := json.NewEncoder(buf)
-	enc.SetEscapeHTML(false)
+	return roi
+}
 
-	err := enc.Encode(mri)
+func marshalAttributes(mri MarshalResourceIdentifier, opts *Options) (json.RawMessage, error) {
+	attributes, err := encodeAttributes(mri)
 	if err != nil {
-		return one, err
+		return nil, err
 	}
 
-	attributes := buf.Bytes()
-
-	if !bytes.Equal(attributes, []byte("{}\n")) {
-		one.Attributes = attributes
+	if opts.Scalars != nil && hasStructAttributes(mri) {
+		attributes, err = encodeScalars(attributes, unwrapResource(mri), opts.Scalars)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if mm, ok := mri.(MarshalMeta); ok {
-		if meta, err := marshalMeta(mm); err == nil {
-			if !bytes.Equal(meta, []byte("{}\n")) {
-				one.Meta = meta
-			}
-		} else {
-			return one, err
+	if opts.OmitZero && hasStructAttributes(mri) {
+		attributes, err = omitZero(attributes, unwrapResource(mri))
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	if mr, ok := mri.(MarshalRelationships); ok {
-		one.Relationships = marshalRelationships(mr)
+	if opts.Translator != nil && len(opts.Locale) > 0 {
+		attributes, err = translateAttributes(mri.GetType(), attributes, resourceType(mri), opts)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return one, nil
-}
-
-func marshalResourceObjects(payload interface{}) ([]*ResourceObject, error) {
-	many := []*ResourceObject{}
+	if opts.Cipher != nil {
+		attributes, err = encryptAttributes(mri.GetType(), attributes, resourceType(mri), opts)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	value := reflect.ValueOf(payload)
+	if opts.TimeZone != nil {
+		attributes, err = normalizeTimes(attributes, resourceType(mri), opts.TimeZone, "")
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	for i := 0; i < value.Len(); i++ {
-		one, err := marshalResourceObject(value.Index(i).Interface().(MarshalResourceIdentifier))
+	if opts.NumberFormat != nil {
+		attributes, err = formatNumbers(attributes, resourceType(mri), *opts.NumberFormat)
 		if err != nil {
-			return many, err
+			return nil, err
 		}
+	}
 
-		many = append(many, &one)
+	if opts.Visibility != nil {
+		attributes, err = hideAttributes(mri.GetType(), attributes, opts.Visibility)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return many, nil
+	return attributes, nil
 }
 
-func marshalRelationships(mr MarshalRelationships) map[string]*relationship {
-	relationships := map[string]*relationship{}
-
-	for key, value := range mr.GetRelationships() {
-		relationships[key] = marshalRelationship(value)
-	}
+func encodeAttributes(mri MarshalResourceIdentifier) (json.RawMessage, error) {
+	if mr, ok := mri.(MarshalRawAttributes); ok {
+		raw := mr.GetRawAttributes()
 
-	return relationships
-}
+		switch string(bytes.TrimSpace(raw)) {
+		case "", "null":
+			return json.RawMessage("{}\n"), nil
+		}
 
-func marshalRelationship(payload interface{}) *relationship {
-	var relationship *relationship
+		if jsonDelim(raw) != '{' || !json.Valid(raw) {
+			return nil, fmt.Errorf("%w: %q resource", ErrInvalidRawAttributes, mri.GetType())
+		}
 
-	switch reflect.TypeOf(payload).Kind() {
-	case reflect.Struct:
-		relationship = marshalRelationshipStruct(payload)
-	case reflect.Slice:
-		relationship = marshalRelationshipSlice(payload)
+		return raw, nil
 	}
 
-	return relationship
-}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	var v interface{} = mri
+
+	if ma, ok := mri.(MarshalAttributes); ok {
+		attributes := ma.GetAttributes()
+		if attributes == nil {
+			attributes = map[string]interface{}{}
+		}
+
+		present := make(map[string]interface{}, len(attributes))
+
+		for name, value := range attributes {
+			if a, ok := value.(absenter); !ok || !a.absent() {
+				present[name] = value
+			}
+		}
+
+		v = present
+	}
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	if _, ok := v.(map[string]interface{}); ok {
+		return buf.Bytes(), nil
+	}
+
+	return omitAbsent(buf.Bytes(), unwrapResource(v))
+}
+
+func marshalResourceObject(mri MarshalResourceIdentifier, opts *Options) (ResourceObject, error) {
+	mri, err := beforeMarshal(mri, opts)
+	if err != nil {
+		return ResourceObject{}, marshalError(mri, "", err)
+	}
+
+	one := ResourceObject{
+		ResourceObjectIdentifier: marshalResourceObjectIdentifier(mri),
+	}
+
+	// the optional interfaces are implemented by the wrapped value of tagged, adapted and extracted resources
+	resource := unwrapResource(mri)
+
+	// resources without attributes, e.g. ones having relationships only, skip attributes encoding
+	if hasAttributes(mri) {
+		attributes, err := marshalAttributes(mri, opts)
+		if err != nil {
+			return one, marshalError(mri, "/attributes", err)
+		}
+
+		if !isEmptyObject(attributes) {
+			one.Attributes = attributes
+		}
+	}
+
+	if mm, ok := mri.(MarshalMeta); ok {
+		if meta, err := marshalMeta(mm); err == nil {
+			if !isEmptyObject(meta) {
+				one.Meta = meta
+			}
+		} else {
+			return one, marshalError(mri, "/meta", err)
+		}
+	}
+
+	if opts.Formatter != nil && len(opts.Locale) > 0 {
+		meta, err := marshalFormatted(one.Meta, mri, opts)
+		if err != nil {
+			return one, marshalError(mri, "/meta", err)
+		}
+
+		one.Meta = meta
+	}
 
-func marshalRelationshipStruct(payload interface{}) *relationship {
+	if mc, ok := resource.(MarshalCacheHints); ok {
+		meta, err := marshalCacheHints(one.Meta, mc)
+		if err != nil {
+			return one, marshalError(mri, "/meta", err)
+		}
+
+		one.Meta = meta
+	}
+
+	if mr, ok := mri.(MarshalRelationships); ok {
+		relationships, err := marshalRelationships(mr, opts)
+		if err != nil {
+			return one, marshalError(mri, "/relationships", err)
+		}
+
+		one.Relationships = relationships
+	}
+
+	if mc, ok := resource.(MarshalRelationshipCounts); ok {
+		relationships, err := marshalRelationshipCounts(one.Relationships, mc)
+		if err != nil {
+			return one, marshalError(mri, "/relationships", err)
+		}
+
+		one.Relationships = relationships
+	}
+
+	if opts.LinkageLimit != nil {
+		if err := limitLinkage(&one, *opts.LinkageLimit); err != nil {
+			return one, marshalError(mri, "/relationships", err)
+		}
+	}
+
+	if mf, ok := resource.(MarshalFiles); ok && opts.FileLinks != nil {
+		if err := marshalFileLinks(&one, mf, opts); err != nil {
+			return one, marshalError(mri, "/links", err)
+		}
+	}
+
+	if opts.Naming != nil {
+		if err := nameMembers(&one, opts.Naming); err != nil {
+			return one, marshalError(mri, "/attributes", err)
+		}
+	}
+
+	if opts.TypeNaming != nil {
+		nameTypes(&one, opts.TypeNaming)
+	}
+
+	return one, nil
+}
+
+func marshalResourceObjects(payload interface{}, opts *Options) ([]*ResourceObject, error) {
+	many := []*ResourceObject{}
+
+	value := reflect.ValueOf(payload)
+
+	for i := 0; i < value.Len(); i++ {
+		item := value.Index(i).Interface()
+
+		if isNil(item) {
+			if opts.SkipNil {
+				continue
+			}
+
+			return many, fmt.Errorf("%w: %s", ErrNilResource, CollectionPointer(i))
+		}
+
+		mri, err := resourceIdentifier(item)
+		if err != nil {
+			return many, err
+		}
+
+		one, err := marshalResourceObject(mri, opts)
+		if err != nil {
+			return many, err
+		}
+
+		many = append(many, &one)
+	}
+
+	return many, nil
+}
+
+func marshalRelationships(mr MarshalRelationships, opts *Options) (map[string]*relationship, error) {
+	relationships := map[string]*relationship{}
+	values := mr.GetRelationships()
+
+	// relationships are marshaled in sorted keys order, so the same error is reported on every run,
+	// the keys order of the encoded relationships object is sorted by encoding/json as well
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		relationship, err := marshalRelationship(values[key], opts)
+		if err != nil {
+			return relationships, &MarshalError{Member: Pointer("relationships", key), Err: err}
+		}
+
+		relationships[key] = relationship
+	}
+
+	return relationships, nil
+}
+
+func marshalRelationshipCounts(relationships map[string]*relationship, mc MarshalRelationshipCounts) (map[string]*relationship, error) {
+	counts := mc.GetRelationshipCounts()
+
+	if len(counts) > 0 && relationships == nil {
+		relationships = map[string]*relationship{}
+	}
+
+	keys := make([]string, 0, len(counts))
+
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		count := counts[key]
+
+		meta, err := json.Marshal(map[string]int{"count": count})
+		if err != nil {
+			return relationships, err
+		}
+
+		if relationship, ok := relationships[key]; ok && relationship != nil {
+			relationship.Meta = meta
+			continue
+		}
+
+		relationships[key] = &relationship{Meta: meta}
+	}
+
+	return relationships, nil
+}
+
+func marshalRelationship(payload interface{}, opts *Options) (*relationship, error) {
+	switch roi := payload.(type) {
+	case ResourceObjectIdentifier:
+		return marshalRelationshipIdentifier(roi, opts)
+	case *ResourceObjectIdentifier:
+		if roi != nil {
+			return marshalRelationshipIdentifier(*roi, opts)
+		}
+	case []ResourceObjectIdentifier:
+		many := make([]*ResourceObjectIdentifier, 0, len(roi))
+
+		for i := range roi {
+			one := roi[i]
+			many = append(many, &one)
+		}
+
+		return &relationship{Data: &relationshipData{Many: many}}, nil
+	case []*ResourceObjectIdentifier:
+		many := make([]*ResourceObjectIdentifier, 0, len(roi))
+
+		for i, one := range roi {
+			if one == nil {
+				if opts.SkipNil {
+					continue
+				}
+
+				return nil, fmt.Errorf("%w: %s", ErrNilResource, Pointer("data", strconv.Itoa(i)))
+			}
+
+			copied := *one
+			many = append(many, &copied)
+		}
+
+		return &relationship{Data: &relationshipData{Many: many}}, nil
+	}
+
+	value := reflect.ValueOf(payload)
+
+	// nil and nil pointer are empty to-one relationship, marshaled as null
+	if isNil(payload) {
+		return &relationship{Data: &relationshipData{}}, nil
+	}
+
+	// pointer to resources collection is marshaled as the collection
+	if value.Kind() == reflect.Ptr && value.Elem().Kind() == reflect.Slice {
+		return marshalRelationshipSlice(value.Elem().Interface(), opts)
+	}
+
+	switch value.Kind() {
+	case reflect.Struct, reflect.Ptr:
+		return marshalRelationshipStruct(payload, opts)
+	case reflect.Slice:
+		return marshalRelationshipSlice(payload, opts)
+	}
+
+	return nil, nil
+}
+
+// isNil tells v is nil or nil pointer.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(v)
+
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+func marshalRelationshipStruct(payload interface{}, opts *Options) (*relationship, error) {
+	mri, err := resourceIdentifier(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	one := marshalResourceObjectIdentifier(mri)
+	one.Missing = isMissing(payload)
+
+	return marshalRelationshipIdentifier(one, opts)
+}
+
+// marshalRelationshipIdentifier returns to-one relationship of the identifier, it is empty or rejected according to Options.EmptyID
+// when the identifier has neither ID nor LID.
+func marshalRelationshipIdentifier(one ResourceObjectIdentifier, opts *Options) (*relationship, error) {
 	relationship := &relationship{
 		Data: &relationshipData{},
 	}
 
-	one := marshalResourceObjectIdentifier(payload.(MarshalResourceIdentifier))
+	if len(one.ID) == 0 && len(one.LID) == 0 {
+		switch opts.EmptyID {
+		case EmptyIDNull:
+			return relationship, nil
+		case EmptyIDError:
+			return nil, ErrEmptyID
+		}
+	}
+
 	relationship.Data.One = &one
 
-	return relationship
+	return relationship, nil
 }
 
-func marshalRelationshipSlice(payload interface{}) *relationship {
+func marshalRelationshipSlice(payload interface{}, opts *Options) (*relationship, error) {
 	relationship := &relationship{
 		Data: &relationshipData{
 			Many: make([]*ResourceObjectIdentifier, 0),
@@ -533,18 +1260,84 @@ func marshalRelationshipSlice(payload interface{}) *relationship {
 	value := reflect.ValueOf(payload)
 
 	for i := 0; i < value.Len(); i++ {
-		one := marshalResourceObjectIdentifier(value.Index(i).Interface().(MarshalResourceIdentifier))
+		item := value.Index(i).Interface()
+
+		if isNil(item) {
+			if opts.SkipNil {
+				continue
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrNilResource, Pointer("data", strconv.Itoa(i)))
+		}
+
+		mri, err := resourceIdentifier(item)
+		if err != nil {
+			return nil, err
+		}
+
+		one := marshalResourceObjectIdentifier(mri)
+		one.Missing = isMissing(item)
+
 		relationship.Data.Many = append(relationship.Data.Many, &one)
 	}
 
-	return relationship
+	return relationship, nil
+}
+
+func marshalDocumentIncluded(payload interface{}, doc *Document, opts *Options) ([]includeFailure, error) {
+	var key includedKey
+
+	if opts.IncludedCache != nil {
+		var err error
+
+		if key, err = includedCacheKey(payload, doc, opts); err != nil {
+			return nil, err
+		}
+
+		if included, ok := opts.IncludedCache.load(key); ok {
+			doc.Included = included
+			return nil, nil
+		}
+	}
+
+	if mi, ok := payload.(MarshalIncluded); ok {
+		if included, err := marshalIncluded(mi, opts); err == nil {
+			doc.Included = included
+		} else {
+			return nil, err
+		}
+	}
+
+	failures, err := resolveIncluded(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(failures) == 0 {
+		opts.IncludedCache.store(key, doc.Included)
+	}
+
+	return failures, nil
 }
 
-func marshalIncluded(mi MarshalIncluded) ([]*ResourceObject, error) {
+func marshalIncluded(mi MarshalIncluded, opts *Options) ([]*ResourceObject, error) {
 	var included []*ResourceObject
 
-	for _, value := range mi.GetIncluded() {
-		ro, err := marshalResourceObject(value.(MarshalResourceIdentifier))
+	for i, value := range mi.GetIncluded() {
+		if isNil(value) {
+			if opts.SkipNil {
+				continue
+			}
+
+			return included, fmt.Errorf("%w: %s", ErrNilResource, Pointer("included", strconv.Itoa(i)))
+		}
+
+		mri, err := resourceIdentifier(value)
+		if err != nil {
+			return included, err
+		}
+
+		ro, err := marshalResourceObject(mri, opts)
 		if err != nil {
 			return included, err
 		}
@@ -569,18 +1362,32 @@ func marshalMeta(mm MarshalMeta) (json.RawMessage, error) {
 
 // Unmarshal deserialize JSON API document into Gu sturct
 // If the corresponding interfaces are implemented target will contain data from JSON API document relationships and errors.
-func Unmarshal(data []byte, target interface{}) (*Document, error) {
-	doc := &Document{}
+// Unmarshal behavior may be changed by options, e.g. jsonapi.Unmarshal(data, &view, jsonapi.WithStrict()).
+func Unmarshal(data []byte, target interface{}, opts ...Option) (*Document, error) {
+	return UnmarshalWithOptions(data, target, newOptions(opts))
+}
 
-	if err := json.Unmarshal(data, doc); err != nil {
+// UnmarshalWithOptions deserialize JSON API document into Go struct like Unmarshal does, using given options.
+func UnmarshalWithOptions(data []byte, target interface{}, opts Options) (*Document, error) {
+	doc, err := decodeDocument(data, &opts)
+	if err != nil {
 		return doc, err
 	}
 
+	if opts.Sidepost && len(doc.Included) > 0 {
+		sideposted, err := resolveSideposted(doc, &opts)
+		if err != nil {
+			return doc, err
+		}
+
+		opts.sideposted = sideposted
+	}
+
 	if asserted, ok := target.(UnmarshalData); ok && doc.Data != nil {
 
 		if one := doc.Data.One; one != nil {
 			if err := asserted.SetData(func(target interface{}) error {
-				return unmarshalOne(one, target)
+				return unmarshalOne(one, target, &opts)
 			}); err != nil {
 				return doc, err
 			}
@@ -588,7 +1395,7 @@ func Unmarshal(data []byte, target interface{}) (*Document, error) {
 
 		if many := doc.Data.Many; many != nil {
 			if err := asserted.SetData(func(target interface{}) error {
-				return unmarshalMany(many, target)
+				return unmarshalMany(many, target, &opts)
 			}); err != nil {
 				return doc, err
 			}
@@ -599,14 +1406,76 @@ func Unmarshal(data []byte, target interface{}) (*Document, error) {
 		asserted.SetErrors(doc.Errors)
 	}
 
+	if opts.Existence != nil {
+		errs, err := opts.Existence.Validate(doc)
+		if err != nil {
+			return doc, err
+		}
+
+		if len(errs) > 0 {
+			return doc, &ValidationError{Errors: errs}
+		}
+	}
+
+	return doc, nil
+}
+
+// decodeDocument decodes JSON API document checking its conformance according to the options.
+func decodeDocument(data []byte, opts *Options) (*Document, error) {
+	doc := &Document{}
+
+	if err := json.Unmarshal(data, doc); err != nil {
+		return doc, err
+	}
+
+	if opts.Strict {
+		if err := validateDocument(data); err != nil {
+			return doc, err
+		}
+
+		if err := validateMemberNames(doc); err != nil {
+			return doc, err
+		}
+	}
+
+	if err := checkVersion(doc, data, opts.Version); err != nil {
+		return doc, err
+	}
+
+	if opts.Interner != nil {
+		internDocument(doc, opts.Interner)
+	}
+
 	return doc, nil
 }
 
-func unmarshalOne(one *ResourceObject, target interface{}) error {
-	return unmarshalResourceObject(one, target.(UnmarshalResourceIdentifier))
+func validateDocument(data []byte) error {
+	members := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	_, hasData := members["data"]
+	_, hasErrors := members["errors"]
+
+	if hasData && hasErrors {
+		return ErrDataAndErrors
+	}
+
+	return nil
+}
+
+func unmarshalOne(one *ResourceObject, target interface{}, opts *Options) error {
+	ui, err := unmarshalTarget(target)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalResourceObject(one, ui, "/data", opts)
 }
 
-func unmarshalMany(many []*ResourceObject, target interface{}) error {
+func unmarshalMany(many []*ResourceObject, target interface{}, opts *Options) error {
 	ptr := reflect.ValueOf(target)
 	val := ptr.Elem()
 
@@ -617,13 +1486,26 @@ func unmarshalMany(many []*ResourceObject, target interface{}) error {
 		typ = typ.Elem()
 	}
 
-	for _, one := range many {
+	var errs []*ErrorObject
+
+	for i, one := range many {
 		new := reflect.New(typ)
 
-		if err := unmarshalResourceObject(one, new.Interface().(UnmarshalResourceIdentifier)); err != nil {
+		ui, err := unmarshalTarget(new.Interface())
+		if err != nil {
 			return err
 		}
 
+		if err := unmarshalResourceObject(one, ui, CollectionPointer(i), opts); err != nil {
+			var validationErr *ValidationError
+
+			if !errors.As(err, &validationErr) {
+				return err
+			}
+
+			errs = append(errs, validationErr.Errors...)
+		}
+
 		if knd == reflect.Struct {
 			new = new.Elem()
 		}
@@ -633,12 +1515,118 @@ func unmarshalMany(many []*ResourceObject, target interface{}) error {
 
 	ptr.Elem().Set(val)
 
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
 	return nil
 }
 
-func unmarshalResourceObject(ro *ResourceObject, ui UnmarshalResourceIdentifier) error {
-	if len(ro.Attributes) > 0 {
-		if err := json.Unmarshal(ro.Attributes, ui); err != nil {
+func unmarshalResourceObject(ro *ResourceObject, ui UnmarshalResourceIdentifier, pointer string, opts *Options) error {
+	if opts.Unnaming != nil {
+		unnamed, err := renameResourceMembers(ro, opts.Unnaming)
+		if err != nil {
+			return err
+		}
+
+		ro = renameTypes(unnamed, opts.Unnaming)
+	} else {
+		if opts.Naming != nil {
+			unnamed, err := unnameMembers(ro, ui, opts.Naming)
+			if err != nil {
+				return err
+			}
+
+			ro = unnamed
+		}
+
+		if opts.TypeNaming != nil {
+			ro = unnameTypes(ro, ui, opts.TypeNaming)
+		}
+	}
+
+	// the report tells the member names the target knows, the ones named back
+	if opts.report != nil {
+		if err := opts.report.add(ro, ui, pointer); err != nil {
+			return err
+		}
+	}
+
+	if opts.trace != nil {
+		opts.trace.add(ro, ui, pointer)
+	}
+
+	attributes := ro.Attributes
+
+	if opts.Cipher != nil {
+		decrypted, err := decryptAttributes(ro.Type, attributes, resourceType(ui), opts)
+		if err != nil {
+			return err
+		}
+
+		attributes = decrypted
+	}
+
+	var defaulted []string
+
+	if opts.Create && isPrimaryPointer(pointer) {
+		withDefaults, names, err := applyDefaults(ro.Type, attributes, resourceType(ui), opts)
+		if err != nil {
+			return err
+		}
+
+		attributes, defaulted = withDefaults, names
+
+		if opts.report != nil {
+			opts.report.defaulted(pointer, defaulted)
+		}
+	}
+
+	if opts.TimeZone != nil {
+		normalized, err := normalizeTimes(attributes, resourceType(ui), opts.TimeZone, pointer+"/attributes")
+		if err != nil {
+			return err
+		}
+
+		attributes = normalized
+	}
+
+	errs, err := validateEnums(ro.Type, attributes, resourceType(ui), pointer+"/attributes", opts)
+	if err != nil {
+		return err
+	}
+
+	blobErrs, err := validateBlobs(attributes, resourceType(ui), pointer+"/attributes", opts)
+	if err != nil {
+		return err
+	}
+
+	errs = append(errs, blobErrs...)
+
+	if opts.DisallowUnknownFields {
+		unknownErrs, err := validateUnknownAttributes(attributes, ui, pointer+"/attributes")
+		if err != nil {
+			return err
+		}
+
+		errs = append(errs, unknownErrs...)
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	if len(attributes) > 0 && opts.Scalars != nil && hasStructAttributes(ui) {
+		decoded, err := decodeScalars(attributes, unwrapResource(ui), opts.Scalars, pointer+"/attributes")
+		if err != nil {
+			return err
+		}
+
+		attributes = decoded
+	}
+
+	if len(attributes) > 0 {
+		if err := unmarshalAttributes(attributes, ui, opts); err != nil {
 			return err
 		}
 	}
@@ -651,16 +1639,75 @@ func unmarshalResourceObject(ro *ResourceObject, ui UnmarshalResourceIdentifier)
 		return err
 	}
 
+	if ul, ok := ui.(UnmarshalLocalIdentifier); ok {
+		if err := ul.SetLID(ro.LID); err != nil {
+			return err
+		}
+	}
+
 	if ur, ok := ui.(UnmarshalRelationships); ok {
-		if err := unmarshalRelationships(ro, ur); err != nil {
+		if err := unmarshalRelationships(ro, ur, opts); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	if ut, ok := ui.(UnmarshalTypedRelationships); ok {
+		if err := ut.SetRelationships(typedRelationships(ro, opts)); err != nil {
+			return err
+		}
+	}
+
+	if up, ok := unwrapResource(ui).(UnmarshalPresentFields); ok {
+		if err := up.SetPresentFields(ro.PresentFields()); err != nil {
+			return err
+		}
+	}
+
+	if ud, ok := unwrapResource(ui).(UnmarshalDefaulted); ok && opts.Create && isPrimaryPointer(pointer) {
+		if err := ud.SetDefaulted(defaulted); err != nil {
+			return err
+		}
+	}
+
+	if err := afterUnmarshal(ui, pointer, opts); err != nil {
+		return err
+	}
+
+	return validateResource(ui, pointer, opts)
+}
+
+func unmarshalAttributes(attributes json.RawMessage, ui UnmarshalResourceIdentifier, opts *Options) error {
+	if ur, ok := ui.(UnmarshalRawAttributes); ok {
+		return ur.SetRawAttributes(attributes)
+	}
+
+	ua, ok := ui.(UnmarshalAttributes)
+	if !ok {
+		return decodeAttributes(attributes, ui, opts)
+	}
+
+	members := map[string]interface{}{}
+
+	if err := decodeAttributes(attributes, &members, opts); err != nil {
+		return err
+	}
+
+	return ua.SetAttributes(members)
 }
 
-func unmarshalRelationships(ro *ResourceObject, ur UnmarshalRelationships) error {
+// decodeAttributes decodes attributes into target, numbers are decoded as json.Number with UseNumber option.
+func decodeAttributes(attributes json.RawMessage, target interface{}, opts *Options) error {
+	if !opts.UseNumber {
+		return json.Unmarshal(attributes, target)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(attributes))
+	dec.UseNumber()
+
+	return dec.Decode(target)
+}
+
+func unmarshalRelationships(ro *ResourceObject, ur UnmarshalRelationships, opts *Options) error {
 	relationships := map[string]interface{}{}
 
 	for k, v := range ro.Relationships {
@@ -669,10 +1716,18 @@ func unmarshalRelationships(ro *ResourceObject, ur UnmarshalRelationships) error
 		if data != nil {
 			if one := data.One; one != nil {
 				relationships[k] = one
+
+				if resolved, ok := opts.sideposted[sidepostKey(*one)]; ok {
+					relationships[k] = resolved
+				}
 			}
 
 			if many := data.Many; many != nil {
 				relationships[k] = many
+
+				if sideposted := unmarshalSidepostedMany(many, opts); sideposted != nil {
+					relationships[k] = sideposted
+				}
 			}
 		}
 	}
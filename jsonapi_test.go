@@ -5,10 +5,15 @@
 package jsonapi_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/pieoneers/jsonapi-go"
 	"sort"
+	"strings"
+	"testing"
 )
 
 type Book struct {
@@ -351,6 +356,88 @@ func (v *ErrorsView) SetErrors(errors []*ErrorObject) error {
 	return nil
 }
 
+type BookWithLocalAuthorView struct {
+	Book BookWithLocalAuthor `json:"-"`
+}
+
+func (v BookWithLocalAuthorView) GetData() interface{} {
+	return v.Book
+}
+
+func (v *BookWithLocalAuthorView) SetData(to func(target interface{}) error) error {
+	return to(&v.Book)
+}
+
+type BookWithLocalAuthor struct {
+	Book
+	LID       string `json:"-"`
+	AuthorLID string `json:"-"`
+}
+
+func (b BookWithLocalAuthor) GetLID() string {
+	return b.LID
+}
+
+func (b *BookWithLocalAuthor) SetLID(lid string) error {
+	b.LID = lid
+	return nil
+}
+
+func (b BookWithLocalAuthor) GetRelationships() map[string]interface{} {
+	return map[string]interface{}{
+		"author": ResourceObjectIdentifier{Type: "authors", LID: b.AuthorLID},
+	}
+}
+
+type BookWithErrorsView struct {
+	BookView
+	ErrorsView
+}
+
+type BookWithCountsView struct {
+	Book BookWithCounts `json:"-"`
+}
+
+func (v BookWithCountsView) GetData() interface{} {
+	return v.Book
+}
+
+type BookWithCounts struct {
+	BookWithReaders
+	ReviewsCount int `json:"-"`
+}
+
+func (b BookWithCounts) GetRelationshipCounts() map[string]int {
+	return map[string]int{
+		"readers": len(b.Readers),
+		"reviews": b.ReviewsCount,
+	}
+}
+
+type OrdersView struct {
+	Orders []Order
+}
+
+func (v OrdersView) GetData() interface{} {
+	return v.Orders
+}
+
+type Receipt struct {
+	Order
+}
+
+func (r Receipt) MarshalJSON() ([]byte, error) {
+	return []byte(`{"total":"9.99"}`), nil
+}
+
+type ReceiptView struct {
+	Receipt Receipt
+}
+
+func (v ReceiptView) GetData() interface{} {
+	return v.Receipt
+}
+
 var _ = Describe("JSONAPI", func() {
 
 	Describe("Marshal", func() {
@@ -566,6 +653,35 @@ var _ = Describe("JSONAPI", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 
+		It("marshals attributes of resource without JSON members but with custom marshaling", func() {
+			view := ReceiptView{
+				Receipt: Receipt{Order: Order{ID: "1"}},
+			}
+
+			result, err := Marshal(view)
+
+			Ω(err).ShouldNot(HaveOccurred())
+
+			doc := Document{}
+			Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+			Ω(doc.Data.One.Attributes).Should(MatchJSON(`{"total": "9.99"}`))
+		})
+
+		Measure("it should marshal resource objects without attributes efficiently", func(b Benchmarker) {
+			orders := make([]Order, 1000)
+
+			for i := range orders {
+				orders[i] = Order{ID: "1", Book: Book{ID: "1"}, Reader: Reader{ID: "1"}}
+			}
+
+			runtime := b.Time("runtime", func() {
+				_, err := Marshal(OrdersView{Orders: orders})
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			Ω(runtime.Seconds()).Should(BeNumerically("<", 0.1), "Marshal() shouldn't take too long.")
+		}, 10)
+
 		It("marshals single resource object with to-one relationship included", func() {
 			view := BookWithAuthorIncludedView{
 				BookWithAuthorView: BookWithAuthorView{
@@ -1383,6 +1499,232 @@ var _ = Describe("JSONAPI", func() {
 			Ω(result).Should(MatchJSON(expected))
 			Ω(err).ShouldNot(HaveOccurred())
 		})
+
+		It("marshals only data of view implementing both data and errors", func() {
+			view := BookWithErrorsView{
+				BookView: BookView{
+					Book: Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+				},
+				ErrorsView: ErrorsView{
+					ValidationErrors: []*ErrorObject{{Title: "is required"}},
+				},
+			}
+
+			result, err := Marshal(view)
+
+			expected := `
+        {
+          "data": {
+            "type": "books",
+            "id": "1",
+            "attributes": {
+              "title": "Go in Action",
+              "year": "2015"
+            }
+          }
+        }
+      `
+
+			Ω(result).Should(MatchJSON(expected))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("returns error for view with both data and errors in strict mode", func() {
+			view := BookWithErrorsView{
+				BookView: BookView{
+					Book: Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+				},
+				ErrorsView: ErrorsView{
+					ValidationErrors: []*ErrorObject{{Title: "is required"}},
+				},
+			}
+
+			_, err := MarshalWithOptions(view, Options{Strict: true})
+
+			Ω(err).Should(Equal(ErrDataAndErrors))
+		})
+
+		It("marshals resource object and to-one relationship with local identifiers", func() {
+			view := BookWithLocalAuthorView{
+				Book: BookWithLocalAuthor{
+					Book:      Book{Title: "Go in Action", Year: "2015", Type: "books"},
+					LID:       "book-1",
+					AuthorLID: "author-1",
+				},
+			}
+
+			result, err := Marshal(view)
+
+			expected := `
+        {
+          "data": {
+            "type": "books",
+            "lid": "book-1",
+            "attributes": {
+              "title": "Go in Action",
+              "year": "2015"
+            },
+            "relationships": {
+              "author": {
+                "data": { "type": "authors", "lid": "author-1" }
+              }
+            }
+          }
+        }
+      `
+
+			Ω(result).Should(MatchJSON(expected))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("marshals empty to-one relationship as identifier when empty ID is kept", func() {
+			view := BookWithAuthorView{
+				Book: BookWithAuthor{
+					Book: Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+				},
+			}
+
+			result, err := MarshalWithOptions(view, Options{EmptyID: EmptyIDKeep})
+
+			expected := `
+        {
+          "data": {
+            "type": "books",
+            "id": "1",
+            "attributes": {
+              "title": "Go in Action",
+              "year": "2015"
+            },
+            "relationships": {
+              "author": {
+                "data": { "type": "authors" }
+              }
+            }
+          }
+        }
+      `
+
+			Ω(result).Should(MatchJSON(expected))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("returns error for empty to-one relationship when empty ID is rejected", func() {
+			view := BookWithAuthorView{
+				Book: BookWithAuthor{
+					Book: Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+				},
+			}
+
+			_, err := MarshalWithOptions(view, Options{EmptyID: EmptyIDError})
+
+			Ω(errors.Is(err, ErrEmptyID)).Should(BeTrue())
+		})
+
+		It("marshals indented document", func() {
+			view := BookView{
+				Book: Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+			}
+
+			result, err := MarshalIndent(view, "", "  ")
+
+			expected := "{\n" +
+				"  \"data\": {\n" +
+				"    \"type\": \"books\",\n" +
+				"    \"id\": \"1\",\n" +
+				"    \"attributes\": {\n" +
+				"      \"title\": \"Go in Action\",\n" +
+				"      \"year\": \"2015\"\n" +
+				"    }\n" +
+				"  }\n" +
+				"}\n"
+
+			Ω(string(result)).Should(Equal(expected))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("marshals canonical compact output", func() {
+			view := BookWithMetaView{
+				Book: BookWithMeta{
+					Book: Book{ID: "1", Title: "Go", Year: "2015", Type: "books"},
+				},
+			}
+
+			result, err := MarshalIndent(view, "", "  ", WithCompact())
+
+			Ω(err).ShouldNot(HaveOccurred())
+
+			compact := &bytes.Buffer{}
+			Ω(json.Compact(compact, result)).Should(Succeed())
+			Ω(result).Should(Equal(compact.Bytes()))
+		})
+
+		It("doesn't escape HTML by default", func() {
+			view := BookWithMetaView{
+				Book: BookWithMeta{
+					Book: Book{ID: "1", Title: "Tom & Jerry <3", Year: "2015", Type: "books"},
+				},
+			}
+
+			result, err := Marshal(view)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(result)).Should(ContainSubstring(`"Tom & Jerry <3"`))
+		})
+
+		It("escapes HTML when requested", func() {
+			view := BookWithMetaView{
+				Book: BookWithMeta{
+					Book: Book{ID: "1", Title: "Tom & Jerry <3", Year: "2015", Type: "books"},
+				},
+			}
+
+			result, err := Marshal(view, WithEscapeHTML())
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(result)).Should(ContainSubstring(`"Tom \u0026 Jerry \u003c3"`))
+		})
+
+		It("marshals relationship counts meta", func() {
+			view := BookWithCountsView{
+				Book: BookWithCounts{
+					BookWithReaders: BookWithReaders{
+						Book:    Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+						Readers: Readers{{ID: "1"}, {ID: "2"}},
+					},
+					ReviewsCount: 42,
+				},
+			}
+
+			result, err := Marshal(view)
+
+			expected := `
+        {
+          "data": {
+            "type": "books",
+            "id": "1",
+            "attributes": {
+              "title": "Go in Action",
+              "year": "2015"
+            },
+            "relationships": {
+              "readers": {
+                "data": [
+                  { "type": "people", "id": "1" },
+                  { "type": "people", "id": "2" }
+                ],
+                "meta": { "count": 2 }
+              },
+              "reviews": {
+                "meta": { "count": 42 }
+              }
+            }
+          }
+        }
+      `
+
+			Ω(result).Should(MatchJSON(expected))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
 	})
 
 	Describe("Unmarshal", func() {
@@ -1455,6 +1797,31 @@ var _ = Describe("JSONAPI", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 
+		It("unmarshals whitespace padded data", func() {
+			doc, err := Unmarshal([]byte(`{"data": {"type": "books", "id": "1", "relationships": {"author": {"data": {"type": "authors", "id": "2"}}}}}`), &BookWithAuthorView{})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(doc.Data.UnmarshalJSON([]byte("\n\t [{\"type\": \"books\", \"id\": \"2\"}]"))).Should(Succeed())
+			Ω(doc.Data.Many).Should(HaveLen(1))
+			Ω(doc.Data.Many[0].ID).Should(Equal("2"))
+
+			data := doc.Data.One.Relationships["author"].Data
+			Ω(data.UnmarshalJSON([]byte("  {\"type\": \"authors\", \"id\": \"1\"}"))).Should(Succeed())
+			Ω(data.One).Should(Equal(&ResourceObjectIdentifier{Type: "authors", ID: "1"}))
+		})
+
+		It("unmarshals streamed padded document", func() {
+			payload := strings.NewReader("\r\n  {\"data\":\n  [ {\"type\": \"books\", \"id\": \"1\", \"attributes\": {\"title\": \"Go\"}} ]}\n")
+
+			result := BooksView{}
+
+			_, err := UnmarshalReader(payload, &result)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result.Books).Should(HaveLen(1))
+			Ω(result.Books[0].Title).Should(Equal("Go"))
+		})
+
 		It("unmarshals resource object with empty to-one relationship", func() {
 			payload := []byte(`
         {
@@ -1701,5 +2068,120 @@ var _ = Describe("JSONAPI", func() {
 			Ω(result).Should(Equal(expected))
 			Ω(err).ShouldNot(HaveOccurred())
 		})
+
+		It("returns error for document with both data and errors in strict mode", func() {
+			payload := []byte(`
+        {
+          "data": null,
+          "errors": [
+            { "title": "is required" }
+          ]
+        }
+      `)
+
+			_, err := UnmarshalWithOptions(payload, &BookView{}, Options{Strict: true})
+
+			Ω(err).Should(Equal(ErrDataAndErrors))
+		})
+
+		It("unmarshals resource object with local identifier", func() {
+			payload := []byte(`
+        {
+          "data": {
+            "type": "books",
+            "lid": "book-1",
+            "attributes": {
+              "title": "Go in Action",
+              "year": "2015"
+            }
+          }
+        }
+      `)
+
+			result := BookWithLocalAuthorView{}
+
+			_, err := Unmarshal(payload, &result)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result.Book.LID).Should(Equal("book-1"))
+			Ω(result.Book.Title).Should(Equal("Go in Action"))
+		})
 	})
 })
+
+type PointerBooksView struct {
+	Books interface{}
+}
+
+func (v PointerBooksView) GetData() interface{} {
+	return v.Books
+}
+
+var _ = Describe("Nil resources", func() {
+
+	book := &Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"}
+
+	It("fails on nil resource of pointers collection", func() {
+		_, err := Marshal(PointerBooksView{Books: []*Book{book, nil}})
+
+		Ω(err).Should(MatchError(ErrNilResource))
+		Ω(err.Error()).Should(Equal("jsonapi: nil resource: /data/1"))
+	})
+
+	It("fails on nil resource of interfaces collection", func() {
+		_, err := Marshal(PointerBooksView{Books: []interface{}{nil, book}})
+
+		Ω(err).Should(MatchError(ErrNilResource))
+		Ω(err.Error()).Should(Equal("jsonapi: nil resource: /data/0"))
+	})
+
+	It("skips nil resources when it is asked", func() {
+		result, err := Marshal(PointerBooksView{Books: []interface{}{nil, book, (*Book)(nil)}}, WithSkipNil())
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": [{"type": "books", "id": "1", "attributes": {"title": "Go in Action", "year": "2015"}}]}`))
+	})
+
+	It("marshals pointers to resource and collection", func() {
+		books := []*Book{book}
+
+		result, err := Marshal(PointerBooksView{Books: &books})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": [{"type": "books", "id": "1", "attributes": {"title": "Go in Action", "year": "2015"}}]}`))
+
+		result, err = Marshal(PointerBooksView{Books: book})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "books", "id": "1", "attributes": {"title": "Go in Action", "year": "2015"}}}`))
+	})
+
+	It("marshals nil resource as null", func() {
+		for _, data := range []interface{}{nil, (*Book)(nil)} {
+			result, err := Marshal(PointerBooksView{Books: data})
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result).Should(MatchJSON(`{"data": null}`))
+		}
+	})
+})
+
+// BenchmarkMarshalRelationshipsOnly marshals resources having relationships only, which skip attributes encoding.
+func BenchmarkMarshalRelationshipsOnly(b *testing.B) {
+	orders := make([]Order, 1000)
+
+	for i := range orders {
+		orders[i] = Order{ID: "1", Book: Book{ID: "1"}, Reader: Reader{ID: "1"}}
+	}
+
+	view := OrdersView{Orders: orders}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(view); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
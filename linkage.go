@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// LinkageLimit describes limiting of to-many relationships linkage, protecting response size of resources with enormous relations.
+// The truncated relationship has the first identifiers only, "related" link to fetch all of them
+// and "meta": {"count": 12345, "truncated": true}.
+//
+// LinkageLimit example:
+//
+//	payload, err := jsonapi.Marshal(view, jsonapi.WithLinkageLimit(jsonapi.LinkageLimit{
+//	  Max:     100,
+//	  BaseURL: "https://api.example.com",
+//	  Relationships: map[string]map[string]int{
+//	    "authors": {"books": 20},
+//	  },
+//	}))
+type LinkageLimit struct {
+	// Max maximum number of to-many relationship identifiers, unlimited when it is zero.
+	Max int
+	// Relationships maximum number of identifiers by resource type and relationship name, taking precedence over Max.
+	Relationships map[string]map[string]int
+	// BaseURL prefix of "related" links of the truncated relationships, the links follow JSON API recommendations,
+	// e.g. "https://api.example.com/authors/1/books". Relationships having "related" link already keep it.
+	BaseURL string
+}
+
+func (l LinkageLimit) limit(resourceType, name string) int {
+	if limit, ok := l.Relationships[resourceType][name]; ok {
+		return limit
+	}
+
+	return l.Max
+}
+
+func (l LinkageLimit) related(roi ResourceObjectIdentifier, name string) string {
+	return strings.TrimSuffix(l.BaseURL, "/") + "/" + url.PathEscape(roi.Type) + "/" + url.PathEscape(roi.ID) + "/" + url.PathEscape(name)
+}
+
+// limitLinkage truncates to-many relationships of the resource object exceeding their limits.
+func limitLinkage(one *ResourceObject, limit LinkageLimit) error {
+	for name, rel := range one.Relationships {
+		if rel == nil || rel.Data == nil || rel.Data.Many == nil {
+			continue
+		}
+
+		max, count := limit.limit(one.Type, name), len(rel.Data.Many)
+		if max <= 0 || count <= max {
+			continue
+		}
+
+		rel.Data.Many = rel.Data.Many[:max:max]
+
+		meta, err := transformAttributes(rel.Meta, func(members map[string]json.RawMessage) error {
+			members["count"] = json.RawMessage(strconv.Itoa(count))
+			members["truncated"] = json.RawMessage("true")
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		rel.Meta = meta
+
+		if rel.Links == nil {
+			rel.Links = Links{}
+		}
+
+		if _, ok := rel.Links["related"]; !ok && len(one.ID) > 0 {
+			rel.Links["related"] = &Link{Href: limit.related(one.ResourceObjectIdentifier, name)}
+		}
+	}
+
+	return nil
+}
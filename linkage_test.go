@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("LinkageLimit", func() {
+
+	people := func(ids ...string) []ResourceObjectIdentifier {
+		var identifiers []ResourceObjectIdentifier
+
+		for _, id := range ids {
+			identifiers = append(identifiers, ResourceObjectIdentifier{Type: "people", ID: id})
+		}
+
+		return identifiers
+	}
+
+	view := MemoView{Memo: Memo{ID: "1", Relationships: map[string]interface{}{
+		"readers":  people("1", "2", "3"),
+		"watchers": people("4", "5", "6"),
+		"editors":  people("7"),
+	}}}
+
+	It("truncates linkage exceeding the limits with related link and count", func() {
+		result, err := Marshal(view, WithLinkageLimit(LinkageLimit{
+			Max:           2,
+			BaseURL:       "https://api.example.com/",
+			Relationships: map[string]map[string]int{"memos": {"watchers": 1}},
+		}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "memos", "id": "1", "relationships": {
+			"readers": {
+				"data": [{"type": "people", "id": "1"}, {"type": "people", "id": "2"}],
+				"links": {"related": "https://api.example.com/memos/1/readers"},
+				"meta": {"count": 3, "truncated": true}
+			},
+			"watchers": {
+				"data": [{"type": "people", "id": "4"}],
+				"links": {"related": "https://api.example.com/memos/1/watchers"},
+				"meta": {"count": 3, "truncated": true}
+			},
+			"editors": {"data": [{"type": "people", "id": "7"}]}
+		}}}`))
+	})
+
+	It("doesn't truncate linkage without limits", func() {
+		result, err := Marshal(view, WithLinkageLimit(LinkageLimit{Relationships: map[string]map[string]int{"memos": {"readers": 0}}}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).ShouldNot(ContainSubstring("truncated"))
+	})
+})
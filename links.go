@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Links JSON API links object https://jsonapi.org/format/#document-links
+type Links map[string]*Link
+
+// Link JSON API link, it is marshaled as a string when it has href only and as a link object otherwise.
+type Link struct {
+	// Href the link's URL.
+	Href string `json:"href"`
+	// Title human-readable link destination identifier (JSON API 1.1).
+	Title string `json:"title,omitempty"`
+	// Meta non-standard meta-information about the link.
+	Meta map[string]interface{} `json:"meta,omitempty"`
+}
+
+type linkObject Link
+
+// MarshalJSON marshals link as a string or a link object.
+func (l Link) MarshalJSON() ([]byte, error) {
+	if len(l.Title) == 0 && len(l.Meta) == 0 {
+		return json.Marshal(l.Href)
+	}
+
+	return json.Marshal(linkObject(l))
+}
+
+// UnmarshalJSON unmarshals link given as a string or a link object.
+func (l *Link) UnmarshalJSON(payload []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(payload), []byte(`"`)) {
+		return json.Unmarshal(payload, &l.Href)
+	}
+
+	return json.Unmarshal(payload, (*linkObject)(l))
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormattedMeta is the resource meta member containing locale formatted attributes.
+const FormattedMeta = "formatted"
+
+// Formatter interface should be implemented to format numeric, Money and time attributes for the locale,
+// the formatted strings are marshaled into resource meta, e.g. "meta": {"formatted": {"price": "1 234,50"}}.
+// Format returns false when the value is not formatted for the locale.
+type Formatter interface {
+	Format(locale, attribute string, value interface{}) (string, bool)
+}
+
+// LocaleFormat describes how numbers and times are formatted for a locale.
+type LocaleFormat struct {
+	// Decimal decimal separator, "." when it is empty.
+	Decimal string
+	// Group digit group separator, digits are not grouped when it is empty.
+	Group string
+	// Precision number of digits after the decimal separator for floating point numbers,
+	// the smallest number of digits necessary to represent the value when it is negative.
+	Precision int
+	// TimeLayout time.Time layout, times are not formatted when it is empty.
+	TimeLayout string
+}
+
+// LocaleFormats implements Formatter by locale, e.g. "de" or "en-US".
+// When the locale is not found its language part is looked up, e.g. "de" for "de-AT".
+//
+// LocaleFormats example:
+//
+//	formats := jsonapi.LocaleFormats{
+//	  "en": {Decimal: ".", Group: ",", Precision: 2, TimeLayout: "Jan 2, 2006"},
+//	  "de": {Decimal: ",", Group: ".", Precision: 2, TimeLayout: "02.01.2006"},
+//	}
+//
+//	payload, err := jsonapi.MarshalWithOptions(view, jsonapi.Options{Locale: "de-AT", Formatter: formats})
+type LocaleFormats map[string]LocaleFormat
+
+// Format formats numeric, Money and time values for the locale, Money keeps the amount decimal places.
+func (lf LocaleFormats) Format(locale, attribute string, value interface{}) (string, bool) {
+	format, ok := lf[locale]
+	if !ok {
+		format, ok = lf[strings.SplitN(locale, "-", 2)[0]]
+	}
+
+	if !ok {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case Money:
+		return format.formatNumber(v.Amount) + " " + v.Currency, true
+	case time.Time:
+		if len(format.TimeLayout) == 0 {
+			return "", false
+		}
+
+		return v.Format(format.TimeLayout), true
+	case float32:
+		return format.formatNumber(strconv.FormatFloat(float64(v), 'f', format.Precision, 32)), true
+	case float64:
+		return format.formatNumber(strconv.FormatFloat(v, 'f', format.Precision, 64)), true
+	}
+
+	val := reflect.ValueOf(value)
+
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return format.formatNumber(strconv.FormatInt(val.Int(), 10)), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return format.formatNumber(strconv.FormatUint(val.Uint(), 10)), true
+	}
+
+	return "", false
+}
+
+func (format LocaleFormat) formatNumber(number string) string {
+	sign := ""
+
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+
+	integer, fraction := number, ""
+
+	if i := strings.Index(number, "."); i >= 0 {
+		integer, fraction = number[:i], number[i+1:]
+	}
+
+	if len(format.Group) > 0 {
+		var groups []string
+
+		for len(integer) > 3 {
+			groups = append([]string{integer[len(integer)-3:]}, groups...)
+			integer = integer[:len(integer)-3]
+		}
+
+		integer = strings.Join(append([]string{integer}, groups...), format.Group)
+	}
+
+	if len(fraction) == 0 {
+		return sign + integer
+	}
+
+	decimal := format.Decimal
+	if len(decimal) == 0 {
+		decimal = "."
+	}
+
+	return sign + integer + decimal + fraction
+}
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the request locale, e.g. "de-AT".
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the request locale attached to ctx by WithLocale.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	locale, ok := ctx.Value(localeKey{}).(string)
+
+	return locale, ok && len(locale) > 0
+}
+
+// marshalFormatted adds locale formatted numeric, Money and time attributes of the resource into its meta.
+func marshalFormatted(meta json.RawMessage, mri MarshalResourceIdentifier, opts *Options) (json.RawMessage, error) {
+	value := reflect.ValueOf(unwrapResource(mri))
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return meta, nil
+		}
+
+		value = value.Elem()
+	}
+
+	formatted := map[string]string{}
+
+	for name, field := range jsonFields(value.Type()) {
+		// hidden attributes aren't leaked through their formatted values
+		if opts.Visibility != nil && !opts.Visibility(mri.GetType(), name) {
+			continue
+		}
+
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if !ok {
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+
+			fieldValue = fieldValue.Elem()
+		}
+
+		if !isFormattable(fieldValue.Type()) {
+			continue
+		}
+
+		if s, ok := opts.Formatter.Format(opts.Locale, name, fieldValue.Interface()); ok {
+			formatted[name] = s
+		}
+	}
+
+	if len(formatted) == 0 {
+		return meta, nil
+	}
+
+	return transformAttributes(meta, func(members map[string]json.RawMessage) error {
+		raw, err := json.Marshal(formatted)
+		if err != nil {
+			return err
+		}
+
+		members[FormattedMeta] = raw
+
+		return nil
+	})
+}
+
+func isFormattable(typ reflect.Type) bool {
+	if typ == timeType || typ == moneyType {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
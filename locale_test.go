@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Product struct {
+	ID           string    `json:"-"`
+	Title        string    `json:"title"`
+	Price        float64   `json:"price"`
+	Stock        int       `json:"stock"`
+	ReleasedAt   time.Time `json:"released_at"`
+	Discontinued *int      `json:"discontinued_at"`
+}
+
+func (p Product) GetID() string {
+	return p.ID
+}
+
+func (p Product) GetType() string {
+	return "products"
+}
+
+type ProductView struct {
+	Product Product
+}
+
+func (v ProductView) GetData() interface{} {
+	return v.Product
+}
+
+var _ = Describe("Locale formatting", func() {
+
+	formats := LocaleFormats{
+		"en": {Decimal: ".", Group: ",", Precision: 2, TimeLayout: "Jan 2, 2006"},
+		"de": {Decimal: ",", Group: ".", Precision: 2, TimeLayout: "02.01.2006"},
+	}
+
+	view := ProductView{
+		Product: Product{
+			ID:         "1",
+			Title:      "Gopher",
+			Price:      1234.5,
+			Stock:      -12000,
+			ReleasedAt: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	It("marshals formatted attributes into resource meta", func() {
+		result, err := MarshalWithOptions(view, Options{Locale: "de-AT", Formatter: formats})
+
+		expected := `
+      {
+        "data": {
+          "type": "products",
+          "id": "1",
+          "attributes": {
+            "title": "Gopher",
+            "price": 1234.5,
+            "stock": -12000,
+            "released_at": "2020-05-01T00:00:00Z",
+            "discontinued_at": null
+          },
+          "meta": {
+            "formatted": {
+              "price": "1.234,50",
+              "stock": "-12.000",
+              "released_at": "01.05.2020"
+            }
+          }
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("doesn't format attributes for unknown locale", func() {
+		result, err := MarshalWithOptions(view, Options{Locale: "fr", Formatter: formats})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(result)).ShouldNot(ContainSubstring("formatted"))
+	})
+
+	It("carries locale in context", func() {
+		ctx := WithLocale(context.Background(), "en-US")
+
+		locale, ok := LocaleFromContext(ctx)
+
+		Ω(ok).Should(BeTrue())
+		Ω(locale).Should(Equal("en-US"))
+
+		formatted, _ := formats.Format(locale, "price", 1234567.891)
+
+		Ω(formatted).Should(Equal("1,234,567.89"))
+	})
+})
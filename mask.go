@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// DefaultMaskPlaceholder replaces masked attribute values when MaskPolicy.Placeholder is empty.
+const DefaultMaskPlaceholder = "[MASKED]"
+
+// MaskAllTypes is MaskPolicy.Attributes key which applies to resources of every type.
+const MaskAllTypes = "*"
+
+// MaskPolicy describes attributes to be masked by MaskDocument.
+//
+// MaskPolicy example:
+//
+//	policy := jsonapi.MaskPolicy{
+//	  Attributes: map[string][]string{
+//	    "users":          {"email", "phone"},
+//	    jsonapi.MaskAllTypes: {"password"},
+//	  },
+//	}
+//
+//	masked, err := jsonapi.MaskDocument(doc, policy)
+//	payload, _ := json.Marshal(masked)
+//	log.Printf("response: %s", payload)
+type MaskPolicy struct {
+	// Attributes masked attribute names by resource type.
+	Attributes map[string][]string
+	// Placeholder replaces masked values, DefaultMaskPlaceholder when empty.
+	Placeholder string
+}
+
+// MaskDocument returns a copy of the document with attributes of primary data and included resources masked
+// according to the policy, so the document can be logged or audited safely. The given document is not changed.
+func MaskDocument(doc *Document, policy MaskPolicy) (*Document, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	placeholder := policy.Placeholder
+	if len(placeholder) == 0 {
+		placeholder = DefaultMaskPlaceholder
+	}
+
+	encoded, err := json.Marshal(placeholder)
+	if err != nil {
+		return nil, err
+	}
+
+	masker := func(ro *ResourceObject) (*ResourceObject, error) {
+		if ro == nil {
+			return nil, nil
+		}
+
+		masked := *ro
+
+		names := append(append([]string{}, policy.Attributes[MaskAllTypes]...), policy.Attributes[ro.Type]...)
+		if len(names) == 0 || len(ro.Attributes) == 0 {
+			return &masked, nil
+		}
+
+		attributes, err := transformAttributes(ro.Attributes, func(members map[string]json.RawMessage) error {
+			for _, name := range names {
+				if value, ok := members[name]; ok && !bytes.Equal(value, []byte("null")) {
+					members[name] = encoded
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		masked.Attributes = attributes
+
+		return &masked, nil
+	}
+
+	copied := *doc
+
+	if doc.Data != nil {
+		copied.Data = &documentData{}
+
+		if copied.Data.One, err = masker(doc.Data.One); err != nil {
+			return nil, err
+		}
+
+		if doc.Data.Many != nil {
+			copied.Data.Many = make([]*ResourceObject, 0, len(doc.Data.Many))
+
+			for _, ro := range doc.Data.Many {
+				masked, err := masker(ro)
+				if err != nil {
+					return nil, err
+				}
+
+				copied.Data.Many = append(copied.Data.Many, masked)
+			}
+		}
+	}
+
+	if doc.Included != nil {
+		copied.Included = make([]*ResourceObject, 0, len(doc.Included))
+
+		for _, ro := range doc.Included {
+			masked, err := masker(ro)
+			if err != nil {
+				return nil, err
+			}
+
+			copied.Included = append(copied.Included, masked)
+		}
+	}
+
+	return &copied, nil
+}
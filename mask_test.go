@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("MaskDocument", func() {
+
+	payload := []byte(`
+    {
+      "data": {
+        "type": "books",
+        "id": "1",
+        "attributes": {
+          "title": "An Introduction to Programming in Go",
+          "year": "2012"
+        },
+        "relationships": {
+          "author": {
+            "data": { "type": "authors", "id": "1" }
+          }
+        }
+      },
+      "included": [
+        {
+          "type": "authors",
+          "id": "1",
+          "attributes": {
+            "name": "Caleb Doxsey",
+            "email": null
+          }
+        }
+      ]
+    }
+  `)
+
+	It("masks attributes according to policy", func() {
+		doc, err := Unmarshal(payload, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		masked, err := MaskDocument(doc, MaskPolicy{
+			Attributes: map[string][]string{
+				"authors":    {"name", "email"},
+				MaskAllTypes: {"year"},
+			},
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(masked)
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "title": "An Introduction to Programming in Go",
+            "year": "[MASKED]"
+          },
+          "relationships": {
+            "author": {
+              "data": { "type": "authors", "id": "1" }
+            }
+          }
+        },
+        "included": [
+          {
+            "type": "authors",
+            "id": "1",
+            "attributes": {
+              "name": "[MASKED]",
+              "email": null
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		original, err := json.Marshal(doc)
+
+		Ω(original).Should(MatchJSON(payload))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidMemberName returned in strict mode when attribute or relationship name isn't a valid JSON API member name,
+// the error is wrapped with JSON pointer of the member.
+var ErrInvalidMemberName = errors.New("jsonapi: invalid member name")
+
+// ValidMemberName tells whether the name is a valid JSON API member name https://jsonapi.org/format/#document-member-names
+// Such names are safe to use in JSON pointers, query parameters (e.g. fields[books]=title,year) and include paths.
+func ValidMemberName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+
+	runes := []rune(name)
+
+	for i, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r >= 0x80:
+		case r == '-' || r == '_' || r == ' ':
+			if i == 0 || i == len(runes)-1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// QueryKey returns query parameter name of the family and the path segments, e.g. QueryKey("fields", "books") gives "fields[books]".
+// The segments are expected to be valid member names, see ValidMemberName.
+func QueryKey(family string, path ...string) string {
+	var b strings.Builder
+
+	b.WriteString(family)
+
+	for _, segment := range path {
+		b.WriteString("[")
+		b.WriteString(segment)
+		b.WriteString("]")
+	}
+
+	return b.String()
+}
+
+// validateMemberNames checks attribute and relationship names of the document resources,
+// the first invalid one is reported with ErrInvalidMemberName.
+func validateMemberNames(doc *Document) error {
+	check := func(pointer string, ro *ResourceObject) error {
+		attributes := map[string]json.RawMessage{}
+
+		if len(ro.Attributes) > 0 {
+			if err := json.Unmarshal(ro.Attributes, &attributes); err != nil {
+				return err
+			}
+		}
+
+		members := map[string][]string{}
+
+		for name := range attributes {
+			members["attributes"] = append(members["attributes"], name)
+		}
+
+		for name := range ro.Relationships {
+			members["relationships"] = append(members["relationships"], name)
+		}
+
+		for _, kind := range []string{"attributes", "relationships"} {
+			names := members[kind]
+			sort.Strings(names)
+
+			for _, name := range names {
+				if !ValidMemberName(name) {
+					return fmt.Errorf("%w: %s", ErrInvalidMemberName, pointer+Pointer(kind, name))
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if doc.Data != nil {
+		if doc.Data.One != nil {
+			if err := check("/data", doc.Data.One); err != nil {
+				return err
+			}
+		}
+
+		for i, ro := range doc.Data.Many {
+			if err := check(CollectionPointer(i), ro); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, ro := range doc.Included {
+		if err := check(fmt.Sprintf("/included/%d", i), ro); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
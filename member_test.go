@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Contact struct {
+	ID        string `json:"-"`
+	FirstName string `json:"first.name"`
+}
+
+func (c Contact) GetID() string {
+	return c.ID
+}
+
+func (c Contact) GetType() string {
+	return "contacts"
+}
+
+type ContactView struct {
+	Contact Contact
+}
+
+func (v ContactView) GetData() interface{} {
+	return v.Contact
+}
+
+var _ = Describe("Member names", func() {
+
+	It("validates member names", func() {
+		for _, name := range []string{"title", "first-name", "first_name", "first name", "Año", "2fa"} {
+			Ω(ValidMemberName(name)).Should(BeTrue(), name)
+		}
+
+		for _, name := range []string{"", "-title", "title_", " title", "first.name", "first/name", "fields[title]", "a,b", "~"} {
+			Ω(ValidMemberName(name)).Should(BeFalse(), name)
+		}
+	})
+
+	It("builds query keys", func() {
+		Ω(QueryKey("include")).Should(Equal("include"))
+		Ω(QueryKey("fields", "books")).Should(Equal("fields[books]"))
+		Ω(QueryKey("filter", "year", "gte")).Should(Equal("filter[year][gte]"))
+	})
+
+	It("rejects invalid attribute names in strict mode on Marshal", func() {
+		view := ContactView{Contact: Contact{ID: "1", FirstName: "Rob"}}
+
+		_, err := Marshal(view)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = Marshal(view, WithStrict())
+		Ω(err).Should(MatchError(ErrInvalidMemberName))
+		Ω(err).Should(MatchError("jsonapi: invalid member name: /data/attributes/first.name"))
+	})
+
+	It("rejects invalid relationship names in strict mode on Unmarshal", func() {
+		payload := []byte(`
+      {
+        "data": [
+          {"type": "books", "id": "1"},
+          {"type": "books", "id": "2", "relationships": {"author/editor": {"data": null}}}
+        ]
+      }
+    `)
+
+		_, err := Unmarshal(payload, &BooksView{}, WithStrict())
+
+		Ω(err).Should(MatchError(ErrInvalidMemberName))
+		Ω(err).Should(MatchError("jsonapi: invalid member name: /data/1/relationships/author~1editor"))
+	})
+})
@@ -0,0 +1,145 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// IncludedCache memoizes included resources, produced by GetIncluded and IncludeResolver, for the TTL.
+// The resources are keyed by the view type, primary data resource identifiers and Include and Fields options,
+// so identical requests to hot endpoints don't compute them again. It is safe for concurrent use.
+//
+// IncludedCache example:
+//
+//	var included = jsonapi.NewIncludedCache(10 * time.Second)
+//
+//	payload, err := jsonapi.Marshal(view, jsonapi.WithIncludedCache(included))
+//
+// The documents with included resources which failed to resolve, see IncludePartial, are not memoized.
+type IncludedCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[includedKey]includedEntry
+	swept   time.Time
+}
+
+type includedKey struct {
+	view    reflect.Type
+	request string
+}
+
+type includedEntry struct {
+	included []*ResourceObject
+	expires  time.Time
+}
+
+// NewIncludedCache creates IncludedCache memoizing included resources for the TTL.
+func NewIncludedCache(ttl time.Duration) *IncludedCache {
+	return &IncludedCache{
+		ttl:     ttl,
+		entries: map[includedKey]includedEntry{},
+		swept:   time.Now(),
+	}
+}
+
+// Purge forgets all the memoized included resources, e.g. when the underlying data changes.
+func (c *IncludedCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[includedKey]includedEntry{}
+}
+
+func (c *IncludedCache) load(key includedKey) ([]*ResourceObject, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return copyResourceObjects(entry.included), true
+}
+
+func (c *IncludedCache) store(key includedKey, included []*ResourceObject) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(c.swept) > c.ttl {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+
+		c.swept = now
+	}
+
+	c.entries[key] = includedEntry{
+		included: copyResourceObjects(included),
+		expires:  now.Add(c.ttl),
+	}
+}
+
+func includedCacheKey(payload interface{}, doc *Document, opts *Options) (includedKey, error) {
+	var data []ResourceObjectIdentifier
+
+	for _, ro := range documentResources(&Document{Data: doc.Data}) {
+		data = append(data, ro.ResourceObjectIdentifier)
+	}
+
+	request, err := json.Marshal(struct {
+		Data    []ResourceObjectIdentifier `json:"data"`
+		Include []string                   `json:"include"`
+		Fields  map[string][]string        `json:"fields"`
+	}{data, opts.Include, opts.Fields})
+	if err != nil {
+		return includedKey{}, err
+	}
+
+	return includedKey{view: reflect.TypeOf(payload), request: string(request)}, nil
+}
+
+// copyResourceObjects copies the resource objects deep enough for the copies to be filtered independently.
+func copyResourceObjects(resources []*ResourceObject) []*ResourceObject {
+	copies := make([]*ResourceObject, 0, len(resources))
+
+	for _, ro := range resources {
+		c := *ro
+
+		if ro.Relationships != nil {
+			c.Relationships = make(map[string]*relationship, len(ro.Relationships))
+
+			for name, rel := range ro.Relationships {
+				c.Relationships[name] = rel
+			}
+		}
+
+		copies = append(copies, &c)
+	}
+
+	return copies
+}
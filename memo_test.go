@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type MemoizedBookView struct {
+	BookWithAuthorView
+	Calls *int
+}
+
+func (v MemoizedBookView) GetIncluded() []interface{} {
+	*v.Calls++
+
+	return []interface{}{v.Book.Author}
+}
+
+var _ = Describe("IncludedCache", func() {
+
+	var (
+		calls int
+		view  MemoizedBookView
+	)
+
+	BeforeEach(func() {
+		calls = 0
+
+		view = MemoizedBookView{
+			BookWithAuthorView: BookWithAuthorView{
+				Book: BookWithAuthor{
+					Book:   Book{ID: "1", Title: "Go", Year: "2015", Type: "books"},
+					Author: Author{ID: "1", Name: "Rob"},
+				},
+			},
+			Calls: &calls,
+		}
+	})
+
+	It("memoizes included resources of identical requests", func() {
+		cache := NewIncludedCache(time.Minute)
+
+		first, err := Marshal(view, WithIncludedCache(cache))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		second, err := Marshal(view, WithIncludedCache(cache))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(calls).Should(Equal(1))
+		Ω(second).Should(MatchJSON(first))
+	})
+
+	It("keys included resources by fields", func() {
+		cache := NewIncludedCache(time.Minute)
+
+		_, err := Marshal(view, WithIncludedCache(cache), WithFields(map[string][]string{"authors": {}}))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := Marshal(view, WithIncludedCache(cache))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(calls).Should(Equal(2))
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Included[0].Attributes).Should(MatchJSON(`{"name": "Rob"}`))
+	})
+
+	It("doesn't reuse filtered included resources", func() {
+		cache := NewIncludedCache(time.Minute)
+		fields := WithFields(map[string][]string{"authors": {}})
+
+		_, err := Marshal(view, WithIncludedCache(cache), fields)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = Marshal(view, WithIncludedCache(cache), fields)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(calls).Should(Equal(1))
+	})
+
+	It("expires included resources after TTL", func() {
+		cache := NewIncludedCache(time.Millisecond)
+
+		_, err := Marshal(view, WithIncludedCache(cache))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		time.Sleep(5 * time.Millisecond)
+
+		_, err = Marshal(view, WithIncludedCache(cache))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(calls).Should(Equal(2))
+	})
+
+	It("purges included resources", func() {
+		cache := NewIncludedCache(time.Minute)
+
+		_, err := Marshal(view, WithIncludedCache(cache))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		cache.Purge()
+
+		_, err = Marshal(view, WithIncludedCache(cache))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(calls).Should(Equal(2))
+	})
+})
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+)
+
+// MergeDocuments combines documents into one, e.g. to respond with resources assembled from several upstream JSON API responses.
+//
+// Primary data of the documents is combined into a collection, included resources and error objects are concatenated,
+// resources are deduplicated by type and ID, see Document.AddIncluded. Meta members are merged, the later documents win
+// when they have the same member. Links are not merged, as they don't describe the merged document.
+//
+// The merged document must not contain both data and errors, it fails with ErrDataAndErrors otherwise.
+func MergeDocuments(docs ...*Document) (*Document, error) {
+	merged := &Document{}
+
+	var many []*ResourceObject
+
+	primary := map[ResourceObjectIdentifier]bool{}
+	hasData := false
+
+	for _, doc := range docs {
+		if doc.Data == nil {
+			continue
+		}
+
+		hasData = true
+
+		for _, ro := range doc.PrimaryResources() {
+			if key := resourceKey(ro); !primary[key] {
+				primary[key] = true
+				many = append(many, ro)
+			}
+		}
+	}
+
+	if hasData {
+		merged.SetMany(many...)
+	}
+
+	meta := map[string]json.RawMessage{}
+
+	for _, doc := range docs {
+		merged.AddIncluded(doc.Included...)
+		merged.Errors = append(merged.Errors, doc.Errors...)
+
+		if len(doc.Meta) > 0 {
+			if err := json.Unmarshal(doc.Meta, &meta); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if merged.Data != nil && len(merged.Errors) > 0 {
+		return nil, ErrDataAndErrors
+	}
+
+	if len(meta) > 0 {
+		if err := merged.SetMeta(meta); err != nil {
+			return nil, err
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("MergeDocuments", func() {
+
+	parse := func(payload string) *Document {
+		doc := &Document{}
+		Ω(json.Unmarshal([]byte(payload), doc)).Should(Succeed())
+
+		return doc
+	}
+
+	It("merges documents", func() {
+		first := parse(`
+      {
+        "data": {"type": "books", "id": "1", "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}},
+        "included": [{"type": "authors", "id": "1", "attributes": {"name": "Rob"}}],
+        "meta": {"source": "first", "region": "eu"},
+        "links": {"self": "/books/1"}
+      }
+    `)
+
+		second := parse(`
+      {
+        "data": [
+          {"type": "books", "id": "1"},
+          {"type": "books", "id": "2", "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}}
+        ],
+        "included": [{"type": "authors", "id": "1", "attributes": {"name": "Rob"}}],
+        "meta": {"source": "second"}
+      }
+    `)
+
+		merged, err := MergeDocuments(first, second)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(merged)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		expected := `
+      {
+        "data": [
+          {"type": "books", "id": "1", "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}},
+          {"type": "books", "id": "2", "relationships": {"author": {"data": {"type": "authors", "id": "1"}}}}
+        ],
+        "included": [{"type": "authors", "id": "1", "attributes": {"name": "Rob"}}],
+        "meta": {"source": "second", "region": "eu"}
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+	})
+
+	It("merges empty collections", func() {
+		merged, err := MergeDocuments(parse(`{"data": []}`), parse(`{"meta": {"total": 0}}`))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(merged)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": [], "meta": {"total": 0}}`))
+	})
+
+	It("merges errors", func() {
+		merged, err := MergeDocuments(parse(`{"errors": [{"title": "First"}]}`), parse(`{"errors": [{"title": "Second"}]}`))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(merged.Errors).Should(HaveLen(2))
+	})
+
+	It("rejects merging data and errors", func() {
+		_, err := MergeDocuments(parse(`{"data": []}`), parse(`{"errors": [{"title": "Oops"}]}`))
+
+		Ω(err).Should(Equal(ErrDataAndErrors))
+	})
+})
@@ -0,0 +1,100 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// ErrInvalidMoney returned when Money value is malformed, the error is wrapped with the details.
+var ErrInvalidMoney = errors.New("jsonapi: invalid money")
+
+var (
+	moneyType = reflect.TypeOf(Money{})
+
+	decimalPattern  = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// Money is a monetary attribute value, its amount is kept and marshaled as decimal string to avoid floating point rounding,
+// e.g. "price": {"amount": "10.50", "currency": "EUR"}.
+// Unmarshal is strict: the amount must be a decimal string, the currency ISO 4217 code, other members are rejected.
+//
+// Money attributes are formatted for the locale by LocaleFormats, e.g. "meta": {"formatted": {"price": "10,50 EUR"}}.
+type Money struct {
+	// Amount decimal amount, e.g. "10.50" or "-3".
+	Amount string
+	// Currency ISO 4217 currency code, e.g. "EUR".
+	Currency string
+}
+
+type moneyObject struct {
+	Amount   *string `json:"amount"`
+	Currency *string `json:"currency"`
+}
+
+// ParseMoney returns Money of the decimal amount and the currency code, it fails with ErrInvalidMoney when they are malformed.
+func ParseMoney(amount, currency string) (Money, error) {
+	m := Money{Amount: amount, Currency: currency}
+
+	return m, m.Validate()
+}
+
+// Validate checks the amount is a decimal string and the currency is ISO 4217 code.
+func (m Money) Validate() error {
+	if !decimalPattern.MatchString(m.Amount) {
+		return fmt.Errorf("%w: amount must be a decimal string: %q", ErrInvalidMoney, m.Amount)
+	}
+
+	if !currencyPattern.MatchString(m.Currency) {
+		return fmt.Errorf("%w: currency must be ISO 4217 code: %q", ErrInvalidMoney, m.Currency)
+	}
+
+	return nil
+}
+
+// String returns the amount followed by the currency, e.g. "10.50 EUR".
+func (m Money) String() string {
+	return m.Amount + " " + m.Currency
+}
+
+// MarshalJSON marshals Money as object with amount and currency, it fails when the value is malformed.
+func (m Money) MarshalJSON() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(moneyObject{Amount: &m.Amount, Currency: &m.Currency})
+}
+
+// UnmarshalJSON unmarshals Money object strictly, see Money.
+func (m *Money) UnmarshalJSON(payload []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+
+	object := moneyObject{}
+
+	if err := dec.Decode(&object); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMoney, err)
+	}
+
+	if object.Amount == nil || object.Currency == nil {
+		return fmt.Errorf("%w: amount and currency are required", ErrInvalidMoney)
+	}
+
+	money, err := ParseMoney(*object.Amount, *object.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Offer struct {
+	ID    string `json:"-"`
+	Price Money  `json:"price"`
+}
+
+func (o Offer) GetID() string {
+	return o.ID
+}
+
+func (o Offer) GetType() string {
+	return "offers"
+}
+
+func (o *Offer) SetID(id string) error {
+	o.ID = id
+	return nil
+}
+
+func (o *Offer) SetType(string) error {
+	return nil
+}
+
+type OfferView struct {
+	Offer Offer
+}
+
+func (v OfferView) GetData() interface{} {
+	return v.Offer
+}
+
+func (v *OfferView) SetData(to func(target interface{}) error) error {
+	return to(&v.Offer)
+}
+
+var _ = Describe("Money", func() {
+
+	It("parses money", func() {
+		m, err := ParseMoney("1234.50", "EUR")
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(m.String()).Should(Equal("1234.50 EUR"))
+
+		for _, amount := range []string{"", "1,5", "01", "1.", ".5", "1e3", "abc"} {
+			_, err := ParseMoney(amount, "EUR")
+			Ω(err).Should(MatchError(ErrInvalidMoney), amount)
+		}
+
+		_, err = ParseMoney("1", "eur")
+		Ω(err).Should(MatchError(ErrInvalidMoney))
+	})
+
+	It("marshals amount as string with currency and formatted meta", func() {
+		view := OfferView{Offer: Offer{ID: "1", Price: Money{Amount: "1234.50", Currency: "EUR"}}}
+
+		result, err := Marshal(view, WithFormatter("de-AT", LocaleFormats{"de": {Decimal: ",", Group: "."}}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Data.One.Attributes).Should(MatchJSON(`{"price": {"amount": "1234.50", "currency": "EUR"}}`))
+		Ω(doc.Data.One.Meta).Should(MatchJSON(`{"formatted": {"price": "1.234,50 EUR"}}`))
+	})
+
+	It("doesn't marshal malformed money", func() {
+		view := OfferView{Offer: Offer{ID: "1", Price: Money{Amount: "10.5"}}}
+
+		_, err := Marshal(view)
+
+		Ω(err).Should(MatchError(ErrInvalidMoney))
+	})
+
+	It("unmarshals money strictly", func() {
+		result := OfferView{}
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "offers", "id": "1", "attributes": {"price": {"amount": "9.99", "currency": "USD"}}}}`), &result)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result.Offer.Price).Should(Equal(Money{Amount: "9.99", Currency: "USD"}))
+
+		for _, price := range []string{`{"amount": 9.99, "currency": "USD"}`, `{"amount": "9.99"}`, `{"amount": "9.99", "currency": "USD", "rate": 1}`, `"9.99 USD"`} {
+			_, err := Unmarshal([]byte(`{"data": {"type": "offers", "id": "1", "attributes": {"price": `+price+`}}}`), &OfferView{})
+			Ω(err).Should(MatchError(ErrInvalidMoney), price)
+		}
+	})
+})
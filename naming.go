@@ -0,0 +1,281 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy transforms attribute and relationship names given by the json tags and GetRelationships keys
+// into the document member names, e.g. SnakeCase, CamelCase or KebabCase, see Options.Naming.
+type NamingStrategy func(name string) string
+
+// SnakeCase names members in snake case, e.g. "publishedAt" and "published-at" give "published_at".
+func SnakeCase(name string) string {
+	return strings.Join(nameWords(name), "_")
+}
+
+// KebabCase names members in kebab case, e.g. "publishedAt" and "published_at" give "published-at".
+func KebabCase(name string) string {
+	return strings.Join(nameWords(name), "-")
+}
+
+// CamelCase names members in lower camel case, e.g. "published_at" and "published-at" give "publishedAt".
+func CamelCase(name string) string {
+	words := nameWords(name)
+
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+
+	return strings.Join(words, "")
+}
+
+// nameWords splits name into lower case words on underscores, hyphens, spaces and case changes,
+// acronyms are kept together, e.g. "ISBNCode" gives "isbn" and "code".
+func nameWords(name string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	runes := []rune(name)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	flush()
+
+	return words
+}
+
+// nameMembers renames attributes and relationships of the marshaled resource object.
+func nameMembers(ro *ResourceObject, naming NamingStrategy) error {
+	if len(ro.Attributes) > 0 {
+		attributes, err := transformAttributes(ro.Attributes, func(members map[string]json.RawMessage) error {
+			renameMembers(members, naming)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		ro.Attributes = attributes
+	}
+
+	if len(ro.Relationships) > 0 {
+		renameMembers(ro.Relationships, naming)
+	}
+
+	return nil
+}
+
+// unnameMembers returns copy of the unmarshaled resource object with attributes and relationships named back
+// as ui knows them: by the json tags of its fields, its tagged relationships and RelationshipNames.
+// Members ui doesn't know by any name are left as is.
+func unnameMembers(ro *ResourceObject, ui UnmarshalResourceIdentifier, naming NamingStrategy) (*ResourceObject, error) {
+	names := map[string]string{}
+
+	for _, name := range knownMembers(ui) {
+		names[naming(name)] = name
+	}
+
+	return renameResourceMembers(ro, func(name string) string {
+		if known, ok := names[name]; ok {
+			return known
+		}
+
+		return name
+	})
+}
+
+// renameResourceMembers returns copy of the resource object with attributes and relationships renamed.
+func renameResourceMembers(ro *ResourceObject, rename func(string) string) (*ResourceObject, error) {
+	unnamed := *ro
+
+	if len(ro.Attributes) > 0 {
+		attributes, err := transformAttributes(ro.Attributes, func(members map[string]json.RawMessage) error {
+			renameMembers(members, rename)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		unnamed.Attributes = attributes
+	}
+
+	if len(ro.Relationships) > 0 {
+		unnamed.Relationships = make(map[string]*relationship, len(ro.Relationships))
+
+		for name, rel := range ro.Relationships {
+			unnamed.Relationships[rename(name)] = rel
+		}
+	}
+
+	return &unnamed, nil
+}
+
+// UnmarshalRelationshipNames interface may be implemented to name relationships back on Unmarshal with Options.Naming,
+// when they aren't tagged relationships of the struct, see Options.Naming.
+//
+// RelationshipNames example:
+//
+//	func (s *SomeStruct) RelationshipNames() []string {
+//	  return []string{"author", "comments"}
+//	}
+type UnmarshalRelationshipNames interface {
+	RelationshipNames() []string
+}
+
+// knownMembers returns attribute and relationship names v is unmarshaled by: json tags of its fields,
+// its tagged relationships and RelationshipNames.
+func knownMembers(v interface{}) []string {
+	var names []string
+
+	if hasStructAttributes(v) {
+		for name := range jsonFields(resourceType(v)) {
+			names = append(names, name)
+		}
+	}
+
+	if ts, ok := taggedStructOf(resourceType(v)); ok {
+		for _, tr := range ts.relationships {
+			names = append(names, tr.name)
+		}
+	}
+
+	if un, ok := unwrapResource(v).(UnmarshalRelationshipNames); ok {
+		names = append(names, un.RelationshipNames()...)
+	}
+
+	return names
+}
+
+func renameMembers[T any](members map[string]T, naming func(string) string) {
+	renamed := make(map[string]T, len(members))
+
+	for name, member := range members {
+		renamed[naming(name)] = member
+	}
+
+	for name := range members {
+		delete(members, name)
+	}
+
+	for name, member := range renamed {
+		members[name] = member
+	}
+}
+
+// nameTypes renames types of the marshaled resource object and its relationships linkage.
+func nameTypes(ro *ResourceObject, naming NamingStrategy) {
+	if len(ro.Type) > 0 {
+		ro.Type = naming(ro.Type)
+	}
+
+	for name, rel := range ro.Relationships {
+		ro.Relationships[name] = renameLinkage(rel, naming)
+	}
+}
+
+// unnameTypes names types of the unmarshaled resource object and its relationships linkage back
+// by the tagged types of ui. Types ui doesn't know are left as is.
+func unnameTypes(ro *ResourceObject, ui UnmarshalResourceIdentifier, naming NamingStrategy) *ResourceObject {
+	types := map[string]string{}
+
+	if ts, ok := taggedStructOf(resourceType(ui)); ok {
+		types[naming(ts.typeName)] = ts.typeName
+
+		for _, tr := range ts.relationships {
+			types[naming(tr.typeName)] = tr.typeName
+		}
+	}
+
+	return renameTypes(ro, func(typ string) string {
+		if known, ok := types[typ]; ok {
+			return known
+		}
+
+		return typ
+	})
+}
+
+// renameTypes returns copy of the resource object with types of the resource and its relationships linkage renamed.
+func renameTypes(ro *ResourceObject, rename func(string) string) *ResourceObject {
+	renamed := *ro
+
+	if len(ro.Type) > 0 {
+		renamed.Type = rename(ro.Type)
+	}
+
+	if len(ro.Relationships) > 0 {
+		renamed.Relationships = make(map[string]*relationship, len(ro.Relationships))
+
+		for name, rel := range ro.Relationships {
+			renamed.Relationships[name] = renameLinkage(rel, rename)
+		}
+	}
+
+	return &renamed
+}
+
+// renameLinkage returns copy of the relationship with linkage types renamed.
+func renameLinkage(rel *relationship, naming func(string) string) *relationship {
+	if rel == nil || rel.Data == nil {
+		return rel
+	}
+
+	renamed := *rel
+	renamed.Data = &relationshipData{}
+
+	rename := func(roi *ResourceObjectIdentifier) *ResourceObjectIdentifier {
+		if roi == nil {
+			return nil
+		}
+
+		copied := *roi
+		if len(copied.Type) > 0 {
+			copied.Type = naming(copied.Type)
+		}
+
+		return &copied
+	}
+
+	renamed.Data.One = rename(rel.Data.One)
+
+	if rel.Data.Many != nil {
+		renamed.Data.Many = make([]*ResourceObjectIdentifier, len(rel.Data.Many))
+
+		for i, roi := range rel.Data.Many {
+			renamed.Data.Many[i] = rename(roi)
+		}
+	}
+
+	return &renamed
+}
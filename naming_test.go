@@ -0,0 +1,383 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Stadium struct {
+	ID         string `json:"-"`
+	Name       string `json:"name"`
+	SeatCount  int    `json:"seat_count"`
+	OpenedAt   string `json:"opened_at,omitempty"`
+	HomeTeamID string `json:"-"`
+}
+
+func (v Stadium) GetID() string {
+	return v.ID
+}
+
+func (v Stadium) GetType() string {
+	return "stadiums"
+}
+
+func (v Stadium) GetRelationships() map[string]interface{} {
+	return map[string]interface{}{
+		"home_team": ResourceObjectIdentifier{Type: "teams", ID: v.HomeTeamID},
+	}
+}
+
+func (v *Stadium) SetID(id string) error {
+	v.ID = id
+	return nil
+}
+
+func (v *Stadium) SetType(t string) error {
+	if t != "stadiums" {
+		return fmt.Errorf("unexpected type %q", t)
+	}
+
+	return nil
+}
+
+func (v *Stadium) SetRelationships(relationships map[string]interface{}) error {
+	if team, ok := ToOne(relationships, "home_team"); ok {
+		v.HomeTeamID = team.ID
+	}
+
+	return nil
+}
+
+func (v *Stadium) RelationshipNames() []string {
+	return []string{"home_team"}
+}
+
+func (v *Stadium) SetData(to func(target interface{}) error) error {
+	return to(v)
+}
+
+func (v Stadium) GetData() interface{} {
+	return v
+}
+
+type Columnist struct {
+	ID string
+}
+
+type Column struct {
+	ID         string     `json:"-"`
+	Title      string     `json:"title"`
+	Columnist  *Columnist `json:"-"`
+	RelatedIDs []string   `json:"-"`
+}
+
+func (c Column) GetID() string {
+	return c.ID
+}
+
+func (c Column) GetType() string {
+	return "columns"
+}
+
+// GetRelationships dereferences the columnist, it panics on zero value.
+func (c Column) GetRelationships() map[string]interface{} {
+	related := make([]ResourceObjectIdentifier, 0, len(c.RelatedIDs))
+
+	for _, id := range c.RelatedIDs {
+		related = append(related, ResourceObjectIdentifier{Type: "columns", ID: id})
+	}
+
+	return map[string]interface{}{
+		"columnist":       ResourceObjectIdentifier{Type: "columnists", ID: c.Columnist.ID},
+		"related_columns": related,
+	}
+}
+
+func (c *Column) SetID(id string) error {
+	c.ID = id
+	return nil
+}
+
+func (c *Column) SetType(string) error {
+	return nil
+}
+
+func (c *Column) SetRelationships(relationships map[string]interface{}) error {
+	if columnist, ok := ToOne(relationships, "columnist"); ok {
+		c.Columnist = &Columnist{ID: columnist.ID}
+	}
+
+	if related, ok := ToMany(relationships, "related_columns"); ok {
+		for _, roi := range related {
+			c.RelatedIDs = append(c.RelatedIDs, roi.ID)
+		}
+	}
+
+	return nil
+}
+
+func (c *Column) RelationshipNames() []string {
+	return []string{"columnist", "related_columns"}
+}
+
+func (c Column) GetData() interface{} {
+	return c
+}
+
+func (c *Column) SetData(to func(target interface{}) error) error {
+	return to(c)
+}
+
+type Patron struct {
+	ID   string `json:"-"`
+	Type string `json:"-"`
+}
+
+func (s Patron) GetID() string {
+	return s.ID
+}
+
+func (s Patron) GetType() string {
+	return s.Type
+}
+
+type Periodical struct {
+	ID      string   `json:"-"`
+	Type    string   `json:"-"`
+	Title   string   `json:"title"`
+	Patrons []Patron `json:"-"`
+}
+
+func (p Periodical) GetID() string {
+	return p.ID
+}
+
+func (p Periodical) GetType() string {
+	return p.Type
+}
+
+func (p Periodical) GetRelationships() map[string]interface{} {
+	return map[string]interface{}{
+		"patrons": p.Patrons,
+	}
+}
+
+func (p *Periodical) SetID(id string) error {
+	p.ID = id
+	return nil
+}
+
+func (p *Periodical) SetType(t string) error {
+	p.Type = t
+	return nil
+}
+
+func (p *Periodical) SetRelationships(relationships map[string]interface{}) error {
+	if patrons, ok := ToMany(relationships, "patrons"); ok {
+		for _, roi := range patrons {
+			p.Patrons = append(p.Patrons, Patron{ID: roi.ID, Type: roi.Type})
+		}
+	}
+
+	return nil
+}
+
+func (p Periodical) GetData() interface{} {
+	return p
+}
+
+func (p *Periodical) SetData(to func(target interface{}) error) error {
+	return to(p)
+}
+
+var _ = Describe("Naming", func() {
+	It("transforms names into snake, kebab and camel case", func() {
+		for name, expected := range map[string][3]string{
+			"publishedAt":   {"published_at", "published-at", "publishedAt"},
+			"published_at":  {"published_at", "published-at", "publishedAt"},
+			"published-at":  {"published_at", "published-at", "publishedAt"},
+			"ISBNCode":      {"isbn_code", "isbn-code", "isbnCode"},
+			"userID":        {"user_id", "user-id", "userId"},
+			"title":         {"title", "title", "title"},
+			"address2_line": {"address2_line", "address2-line", "address2Line"},
+		} {
+			Ω(SnakeCase(name)).Should(Equal(expected[0]), name)
+			Ω(KebabCase(name)).Should(Equal(expected[1]), name)
+			Ω(CamelCase(name)).Should(Equal(expected[2]), name)
+		}
+	})
+
+	stadium := Stadium{ID: "1", Name: "Arena", SeatCount: 20000, HomeTeamID: "7"}
+
+	It("names attributes and relationships on marshal", func() {
+		payload, err := Marshal(stadium, WithNaming(CamelCase))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`
+			{
+				"data": {
+					"type": "stadiums",
+					"id": "1",
+					"attributes": {"name": "Arena", "seatCount": 20000},
+					"relationships": {"homeTeam": {"data": {"type": "teams", "id": "7"}}}
+				}
+			}
+		`))
+
+		payload, err = Marshal(stadium, WithNaming(KebabCase))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`
+			{
+				"data": {
+					"type": "stadiums",
+					"id": "1",
+					"attributes": {"name": "Arena", "seat-count": 20000},
+					"relationships": {"home-team": {"data": {"type": "teams", "id": "7"}}}
+				}
+			}
+		`))
+	})
+
+	It("names members back on unmarshal", func() {
+		payload, err := Marshal(stadium, WithNaming(CamelCase))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var unmarshaled Stadium
+
+		doc, err := Unmarshal(payload, &unmarshaled, WithNaming(CamelCase))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(unmarshaled).Should(Equal(stadium))
+		Ω(doc.Data.One.Relationships).Should(HaveKey("homeTeam"))
+	})
+
+	It("names relationships back by RelationshipNames without calling GetRelationships", func() {
+		column := Column{ID: "1", Title: "Opinion", Columnist: &Columnist{ID: "2"}, RelatedIDs: []string{"3"}}
+
+		payload, err := Marshal(column, WithNaming(CamelCase))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`
+			{
+				"data": {
+					"type": "columns",
+					"id": "1",
+					"attributes": {"title": "Opinion"},
+					"relationships": {
+						"columnist": {"data": {"type": "columnists", "id": "2"}},
+						"relatedColumns": {"data": [{"type": "columns", "id": "3"}]}
+					}
+				}
+			}
+		`))
+
+		var unmarshaled Column
+
+		_, err = Unmarshal(payload, &unmarshaled, WithNaming(CamelCase))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(unmarshaled).Should(Equal(column))
+	})
+
+	It("applies sparse fieldsets and includes by the transformed names", func() {
+		payload, err := Marshal(stadium, WithNaming(CamelCase), WithFields(map[string][]string{"stadiums": {"seatCount"}}))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`{"data": {"type": "stadiums", "id": "1", "attributes": {"seatCount": 20000}}}`))
+	})
+
+	It("applies naming hook to members and types, names them back on unmarshal", func() {
+		hook := func(name string) string {
+			return "x_" + name
+		}
+
+		unhook := func(name string) string {
+			return strings.TrimPrefix(name, "x_")
+		}
+
+		payload, err := Marshal(stadium, WithNamingFunc(hook, unhook))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`
+			{
+				"data": {
+					"type": "x_stadiums",
+					"id": "1",
+					"attributes": {"x_name": "Arena", "x_seat_count": 20000},
+					"relationships": {"x_home_team": {"data": {"type": "x_teams", "id": "7"}}}
+				}
+			}
+		`))
+
+		var unmarshaled Stadium
+
+		doc, err := Unmarshal(payload, &unmarshaled, WithNamingFunc(hook, unhook))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(unmarshaled).Should(Equal(stadium))
+		Ω(doc.Data.One.Type).Should(Equal("x_stadiums"))
+	})
+
+	It("names field-backed types and to-many linkage types back by the inverse", func() {
+		periodical := Periodical{ID: "1", Type: "periodicals", Title: "Weekly", Patrons: []Patron{{ID: "2", Type: "people"}}}
+
+		naming := WithNamingFunc(func(name string) string {
+			return "x_" + name
+		}, func(name string) string {
+			return strings.TrimPrefix(name, "x_")
+		})
+
+		payload, err := Marshal(periodical, naming)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`
+			{
+				"data": {
+					"type": "x_periodicals",
+					"id": "1",
+					"attributes": {"x_title": "Weekly"},
+					"relationships": {"x_patrons": {"data": [{"type": "x_people", "id": "2"}]}}
+				}
+			}
+		`))
+
+		var unmarshaled Periodical
+
+		_, err = Unmarshal(payload, &unmarshaled, naming)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(unmarshaled).Should(Equal(periodical))
+
+		empty := Periodical{ID: "2", Type: "periodicals", Title: "Monthly"}
+
+		payload, err = Marshal(empty, naming)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`
+			{
+				"data": {
+					"type": "x_periodicals",
+					"id": "2",
+					"attributes": {"x_title": "Monthly"},
+					"relationships": {"x_patrons": {"data": []}}
+				}
+			}
+		`))
+
+		unmarshaled = Periodical{}
+
+		_, err = Unmarshal(payload, &unmarshaled, naming)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(unmarshaled).Should(Equal(empty))
+	})
+})
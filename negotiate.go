@@ -0,0 +1,159 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Negotiate checks request Content-Type and Accept headers according to JSON API content negotiation rules,
+// see NegotiateContentType and NegotiateAccept. Extensions are URIs of the JSON API 1.1 extensions the server supports.
+// It returns error object ready to be written, e.g. by WriteErrors, when negotiation fails, nil otherwise.
+//
+// Negotiate example:
+//
+//	if err := jsonapi.Negotiate(r); err != nil {
+//	  _ = jsonapi.WriteErrors(w, r, []*jsonapi.ErrorObject{err})
+//	  return
+//	}
+func Negotiate(r *http.Request, extensions ...string) *ErrorObject {
+	if err := NegotiateContentType(r, extensions...); err != nil {
+		return err
+	}
+
+	return NegotiateAccept(r, extensions...)
+}
+
+// NegotiateContentType returns 415 Unsupported Media Type error object when the request Content-Type is JSON API
+// media type having parameters other than "ext" and "profile", or "ext" having unsupported extensions.
+// Requests of other content types, e.g. without body, are not checked.
+func NegotiateContentType(r *http.Request, extensions ...string) *ErrorObject {
+	contentType := r.Header.Get("Content-Type")
+	if len(contentType) == 0 {
+		return nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != ContentType {
+		return nil
+	}
+
+	if problem, ok := unsupportedMediaTypeParams(params, extensions, false); ok {
+		return negotiationError(http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type", problem)
+	}
+
+	return nil
+}
+
+// NegotiateAccept returns 406 Not Acceptable error object when the request Accept header has JSON API media type,
+// and every instance of it has parameters other than "ext" and "profile", or "ext" having unsupported extensions.
+// Requests not accepting JSON API media type explicitly, e.g. "*/*", are not checked.
+func NegotiateAccept(r *http.Request, extensions ...string) *ErrorObject {
+	var problems []string
+
+	for _, header := range r.Header.Values("Accept") {
+		for _, accept := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+			if err != nil || mediaType != ContentType {
+				continue
+			}
+
+			problem, ok := unsupportedMediaTypeParams(params, extensions, true)
+			if !ok {
+				return nil
+			}
+
+			problems = append(problems, problem)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return negotiationError(http.StatusNotAcceptable, "not_acceptable", "Accept", strings.Join(problems, ", "))
+}
+
+// unsupportedMediaTypeParams describes the first JSON API media type parameter the server doesn't support,
+// Accept header quality parameter is allowed when accept is true.
+func unsupportedMediaTypeParams(params map[string]string, extensions []string, accept bool) (string, bool) {
+	names := make([]string, 0, len(params))
+
+	for name := range params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch {
+		case name == "profile", accept && name == "q":
+			continue
+		case name == "ext":
+			for _, ext := range strings.Fields(params[name]) {
+				if !hasString(extensions, ext) {
+					return fmt.Sprintf("extension %q is not supported", ext), true
+				}
+			}
+		default:
+			return fmt.Sprintf("media type parameter %q is not supported", name), true
+		}
+	}
+
+	return "", false
+}
+
+func negotiationError(status int, code, header, detail string) *ErrorObject {
+	return &ErrorObject{
+		Status: strconv.Itoa(status),
+		Title:  http.StatusText(status),
+		Detail: strings.ToUpper(detail[:1]) + detail[1:] + ".",
+		Code:   code,
+		Source: ErrorObjectSource{Header: header},
+	}
+}
+
+func hasString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Middleware returns net/http middleware negotiating JSON API content, see Negotiate.
+// Requests failing negotiation are responded with error document, 415 Unsupported Media Type or 406 Not Acceptable,
+// the others are passed to the handler with the response Content-Type set to JSON API media type
+// and Vary header listing Accept. Extensions are URIs of the JSON API 1.1 extensions the server supports.
+//
+// Middleware example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/books", jsonapi.Middleware()(booksHandler))
+//
+//	log.Fatal(http.ListenAndServe(":8080", mux))
+func Middleware(extensions ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept")
+
+			if err := Negotiate(r, extensions...); err != nil {
+				_ = WriteErrors(w, r, []*ErrorObject{err})
+				return
+			}
+
+			w.Header().Set("Content-Type", ContentType)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
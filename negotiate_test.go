@@ -0,0 +1,163 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Negotiate", func() {
+	const atomic = "https://jsonapi.org/ext/atomic"
+
+	request := func(contentType string, accept ...string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/books", nil)
+
+		if len(contentType) > 0 {
+			r.Header.Set("Content-Type", contentType)
+		}
+
+		for _, a := range accept {
+			r.Header.Add("Accept", a)
+		}
+
+		return r
+	}
+
+	It("accepts conforming requests", func() {
+		for _, r := range []*http.Request{
+			request(""),
+			request(ContentType, ContentType),
+			request("application/json", "application/json"),
+			request(ContentType, "*/*"),
+			request(ContentType+`; profile="https://example.com/profiles/flexible"`, ContentType+`; profile="https://example.com/profiles/flexible"`),
+			request(ContentType+`; ext="`+atomic+`"`, ContentType+`; ext="`+atomic+`"; q=0.9`),
+			request(ContentType, ContentType+"; charset=utf-8, "+ContentType),
+			request(ContentType, ContentType+"; charset=utf-8", ContentType+"; q=0.5"),
+		} {
+			Ω(Negotiate(r, atomic)).Should(BeNil(), r.Header.Get("Content-Type")+" "+r.Header.Get("Accept"))
+		}
+	})
+
+	It("rejects JSON API content type with unsupported parameters", func() {
+		err := Negotiate(request(ContentType+"; charset=utf-8", ContentType))
+
+		Ω(err).Should(Equal(&ErrorObject{
+			Status: "415",
+			Title:  "Unsupported Media Type",
+			Detail: `Media type parameter "charset" is not supported.`,
+			Code:   "unsupported_media_type",
+			Source: ErrorObjectSource{Header: "Content-Type"},
+		}))
+	})
+
+	It("rejects JSON API content type with unsupported extensions", func() {
+		err := Negotiate(request(ContentType+`; ext="`+atomic+` https://example.com/ext/version"`), atomic)
+
+		Ω(err.Status).Should(Equal("415"))
+		Ω(err.Detail).Should(Equal(`Extension "https://example.com/ext/version" is not supported.`))
+
+		Ω(Negotiate(request(ContentType + `; ext="` + atomic + `"`))).ShouldNot(BeNil())
+	})
+
+	It("rejects Accept header having only JSON API media types with unsupported parameters", func() {
+		err := Negotiate(request(ContentType, "application/json, "+ContentType+"; charset=utf-8", ContentType+`; ext="`+atomic+`"`))
+
+		Ω(err).Should(Equal(&ErrorObject{
+			Status: "406",
+			Title:  "Not Acceptable",
+			Detail: `Media type parameter "charset" is not supported, extension "` + atomic + `" is not supported.`,
+			Code:   "not_acceptable",
+			Source: ErrorObjectSource{Header: "Accept"},
+		}))
+	})
+
+	It("checks content type before Accept header", func() {
+		err := Negotiate(request(ContentType+"; charset=utf-8", ContentType+"; charset=utf-8"))
+
+		Ω(err.Status).Should(Equal("415"))
+		Ω(NegotiateAccept(request(ContentType+"; charset=utf-8", ContentType+"; charset=utf-8")).Status).Should(Equal("406"))
+	})
+
+	It("returns error object ready to be written", func() {
+		w := httptest.NewRecorder()
+		r := request(ContentType, ContentType+"; charset=utf-8")
+
+		Ω(WriteErrors(w, r, []*ErrorObject{Negotiate(r)})).Should(Succeed())
+		Ω(w.Code).Should(Equal(http.StatusNotAcceptable))
+	})
+})
+
+var _ = Describe("Middleware", func() {
+	handler := Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"meta": {"ok": true}}`))
+	}))
+
+	serve := func(contentType, accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/books", nil)
+
+		if len(contentType) > 0 {
+			r.Header.Set("Content-Type", contentType)
+		}
+
+		if len(accept) > 0 {
+			r.Header.Set("Accept", accept)
+		}
+
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	It("passes negotiated requests to the handler with response headers set", func() {
+		w := serve("", ContentType)
+
+		Ω(w.Code).Should(Equal(http.StatusOK))
+		Ω(w.Header().Get("Content-Type")).Should(Equal(ContentType))
+		Ω(w.Header().Get("Vary")).Should(Equal("Accept"))
+		Ω(w.Body.String()).Should(MatchJSON(`{"meta": {"ok": true}}`))
+	})
+
+	It("responds with 415 error document", func() {
+		w := serve(ContentType+"; charset=utf-8", "")
+
+		Ω(w.Code).Should(Equal(http.StatusUnsupportedMediaType))
+		Ω(w.Header().Get("Content-Type")).Should(Equal(ContentType))
+		Ω(w.Body.String()).Should(MatchJSON(`{"errors": [{
+			"status": "415",
+			"title": "Unsupported Media Type",
+			"detail": "Media type parameter \"charset\" is not supported.",
+			"code": "unsupported_media_type",
+			"source": {"header": "Content-Type"}
+		}]}`))
+	})
+
+	It("responds with 406 error document", func() {
+		w := serve("", ContentType+"; charset=utf-8")
+
+		Ω(w.Code).Should(Equal(http.StatusNotAcceptable))
+		Ω(w.Header().Get("Vary")).Should(Equal("Accept"))
+		Ω(w.Body.String()).Should(ContainSubstring(`"not_acceptable"`))
+	})
+
+	It("accepts extensions the server supports", func() {
+		const atomic = "https://jsonapi.org/ext/atomic"
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/operations", nil)
+		r.Header.Set("Content-Type", ContentType+`; ext="`+atomic+`"`)
+
+		Middleware(atomic)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})).ServeHTTP(w, r)
+
+		Ω(w.Code).Should(Equal(http.StatusNoContent))
+	})
+})
@@ -0,0 +1,160 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Nullable is attribute value which tells absent, null and set values apart, e.g. for PATCH requests
+// where null clears the attribute and absent one leaves it unchanged.
+// Absent attributes are omitted on Marshal, null ones are marshaled as null.
+//
+// Nullable example:
+//
+//	type BookPatch struct {
+//	  Title    jsonapi.Nullable[string] `json:"title"`
+//	  Subtitle jsonapi.Nullable[string] `json:"subtitle"`
+//	}
+//
+//	if subtitle, ok := patch.Subtitle.Get(); ok {
+//	  book.Subtitle = subtitle
+//	} else if patch.Subtitle.IsNull() {
+//	  book.Subtitle = ""
+//	}
+type Nullable[T any] struct {
+	value T
+	state nullableState
+}
+
+type nullableState int
+
+const (
+	nullableAbsent nullableState = iota
+	nullableNull
+	nullableSet
+)
+
+// NullableOf returns set Nullable of the value.
+func NullableOf[T any](value T) Nullable[T] {
+	return Nullable[T]{value: value, state: nullableSet}
+}
+
+// Null returns null Nullable.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{state: nullableNull}
+}
+
+// Get returns the value, false when it is absent or null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.state == nullableSet
+}
+
+// IsSet returns true when the value is set.
+func (n Nullable[T]) IsSet() bool {
+	return n.state == nullableSet
+}
+
+// IsNull returns true when the value is explicitly null.
+func (n Nullable[T]) IsNull() bool {
+	return n.state == nullableNull
+}
+
+// IsPresent returns true when the value is set or null, false when it is absent.
+func (n Nullable[T]) IsPresent() bool {
+	return n.state != nullableAbsent
+}
+
+// Set sets the value.
+func (n *Nullable[T]) Set(value T) {
+	*n = NullableOf(value)
+}
+
+// SetNull makes the value null.
+func (n *Nullable[T]) SetNull() {
+	*n = Null[T]()
+}
+
+// Unset makes the value absent.
+func (n *Nullable[T]) Unset() {
+	*n = Nullable[T]{}
+}
+
+// MarshalJSON marshals the value, null when it is null or absent.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.state != nullableSet {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.value)
+}
+
+// UnmarshalJSON unmarshals the value, null makes it null.
+func (n *Nullable[T]) UnmarshalJSON(payload []byte) error {
+	if string(payload) == "null" {
+		n.SetNull()
+		return nil
+	}
+
+	var value T
+
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return err
+	}
+
+	n.Set(value)
+
+	return nil
+}
+
+func (n Nullable[T]) absent() bool {
+	return n.state == nullableAbsent
+}
+
+// absenter is implemented by Nullable, absent values are omitted on Marshal.
+type absenter interface {
+	absent() bool
+}
+
+var absenterType = reflect.TypeOf((*absenter)(nil)).Elem()
+
+// omitAbsent removes absent Nullable members from the attributes encoded from v.
+func omitAbsent(attributes json.RawMessage, v interface{}) (json.RawMessage, error) {
+	value := reflect.ValueOf(v)
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return attributes, nil
+		}
+
+		value = value.Elem()
+	}
+
+	var absent []string
+
+	for name, field := range jsonFields(value.Type()) {
+		if !field.field.Type.Implements(absenterType) {
+			continue
+		}
+
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if ok && fieldValue.Interface().(absenter).absent() {
+			absent = append(absent, name)
+		}
+	}
+
+	if len(absent) == 0 {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for _, name := range absent {
+			delete(members, name)
+		}
+
+		return nil
+	})
+}
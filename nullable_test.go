@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Episode struct {
+	ID       string            `json:"-"`
+	Title    Nullable[string]  `json:"title"`
+	Subtitle Nullable[string]  `json:"subtitle"`
+	Rating   Nullable[float64] `json:"rating"`
+}
+
+func (e Episode) GetID() string {
+	return e.ID
+}
+
+func (e Episode) GetType() string {
+	return "episodes"
+}
+
+func (e *Episode) SetID(id string) error {
+	e.ID = id
+	return nil
+}
+
+func (e *Episode) SetType(string) error {
+	return nil
+}
+
+func (e *Episode) SetData(to func(target interface{}) error) error {
+	return to(e)
+}
+
+type EpisodeView struct {
+	Data interface{}
+}
+
+func (v EpisodeView) GetData() interface{} {
+	return v.Data
+}
+
+type Season struct {
+	ID       string           `json:"-" jsonapi:"id,type=seasons"`
+	Title    Nullable[string] `json:"title"`
+	Subtitle Nullable[string] `json:"subtitle"`
+}
+
+var _ = Describe("Nullable", func() {
+	It("tells absent, null and set values apart", func() {
+		var n Nullable[int]
+
+		Ω(n.IsPresent()).Should(BeFalse())
+		Ω(n.IsNull()).Should(BeFalse())
+		Ω(n.IsSet()).Should(BeFalse())
+
+		n.SetNull()
+		Ω(n.IsPresent()).Should(BeTrue())
+		Ω(n.IsNull()).Should(BeTrue())
+
+		n.Set(42)
+		value, ok := n.Get()
+		Ω(ok).Should(BeTrue())
+		Ω(value).Should(Equal(42))
+
+		n.Unset()
+		Ω(n.IsPresent()).Should(BeFalse())
+
+		Ω(NullableOf("x").IsSet()).Should(BeTrue())
+		Ω(Null[string]().IsNull()).Should(BeTrue())
+	})
+
+	It("omits absent attributes and marshals null ones as null", func() {
+		payload, err := Marshal(EpisodeView{Data: Episode{ID: "1", Title: NullableOf("Pilot"), Subtitle: Null[string]()}})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`{"data": {"type": "episodes", "id": "1", "attributes": {"title": "Pilot", "subtitle": null}}}`))
+	})
+
+	It("omits absent attributes of tagged structs", func() {
+		payload, err := Marshal(EpisodeView{Data: Season{ID: "1", Title: NullableOf("First")}})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`{"data": {"type": "seasons", "id": "1", "attributes": {"title": "First"}}}`))
+	})
+
+	It("omits absent values of attributes maps", func() {
+		payload, err := Marshal(EpisodeView{Data: Record{
+			ID: "1",
+			Fields: map[string]interface{}{
+				"title":    NullableOf("Pilot"),
+				"subtitle": Nullable[string]{},
+			},
+		}})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`{"data": {"type": "records", "id": "1", "attributes": {"title": "Pilot"}}}`))
+	})
+
+	It("unmarshals absent, null and set attributes", func() {
+		var episode Episode
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "episodes", "id": "1", "attributes": {"title": "Pilot", "subtitle": null}}}`), &episode)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		title, ok := episode.Title.Get()
+		Ω(ok).Should(BeTrue())
+		Ω(title).Should(Equal("Pilot"))
+
+		Ω(episode.Subtitle.IsNull()).Should(BeTrue())
+		Ω(episode.Rating.IsPresent()).Should(BeFalse())
+	})
+
+	It("fails on values of another type", func() {
+		_, err := Unmarshal([]byte(`{"data": {"type": "episodes", "id": "1", "attributes": {"rating": "high"}}}`), &Episode{})
+		Ω(err).Should(HaveOccurred())
+	})
+})
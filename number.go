@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// NumberFormat describes how float attributes are marshaled, e.g. for financial APIs which need predictable number rendering.
+// Integer attributes are never affected, they keep being marshaled as integers.
+type NumberFormat struct {
+	// Decimals maximum number of decimal places, the floats are rounded to it and trailing zeros are dropped,
+	// e.g. 2 gives 10.13 for 10.125 and 10.5 for 10.50. Negative value keeps the shortest exact representation.
+	Decimals int
+	// NoExponent formats floats without exponent notation, e.g. 0.0000001 instead of 1e-7.
+	// Floats are always formatted without it when Decimals isn't negative.
+	NoExponent bool
+}
+
+// floatAttributes returns json names of the type fields holding floats (or pointers to them) by their bit size.
+func floatAttributes(typ reflect.Type) map[string]int {
+	names := map[string]int{}
+
+	for name, field := range jsonFields(typ) {
+		fieldType := field.field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		switch fieldType.Kind() {
+		case reflect.Float32:
+			names[name] = 32
+		case reflect.Float64:
+			names[name] = 64
+		}
+	}
+
+	return names
+}
+
+// formatNumbers formats float attributes of the type according to the format.
+func formatNumbers(attributes json.RawMessage, typ reflect.Type, format NumberFormat) (json.RawMessage, error) {
+	names := floatAttributes(typ)
+	if (format.Decimals < 0 && !format.NoExponent) || len(names) == 0 || len(attributes) == 0 || bytes.Equal(attributes, []byte("{}\n")) {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for name, bitSize := range names {
+			value, ok := members[name]
+			if !ok || bytes.Equal(value, []byte("null")) {
+				continue
+			}
+
+			f, err := strconv.ParseFloat(string(value), bitSize)
+			if err != nil {
+				return err
+			}
+
+			members[name] = json.RawMessage(format.formatFloat(f, bitSize))
+		}
+
+		return nil
+	})
+}
+
+func (nf NumberFormat) formatFloat(f float64, bitSize int) string {
+	if nf.Decimals < 0 {
+		return strconv.FormatFloat(f, 'f', -1, bitSize)
+	}
+
+	formatted := strconv.FormatFloat(f, 'f', nf.Decimals, bitSize)
+
+	if strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	}
+
+	if formatted == "-0" {
+		formatted = "0"
+	}
+
+	return formatted
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Invoice struct {
+	ID       string   `json:"-"`
+	Amount   float64  `json:"amount"`
+	Rate     float32  `json:"rate"`
+	Discount *float64 `json:"discount"`
+	Quantity int      `json:"quantity"`
+}
+
+func (i Invoice) GetID() string {
+	return i.ID
+}
+
+func (i Invoice) GetType() string {
+	return "invoices"
+}
+
+type InvoiceView struct {
+	Invoice Invoice
+}
+
+func (v InvoiceView) GetData() interface{} {
+	return v.Invoice
+}
+
+var _ = Describe("NumberFormat", func() {
+
+	attributes := func(result []byte) json.RawMessage {
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+
+		return doc.Data.One.Attributes
+	}
+
+	It("rounds floats to maximum decimals", func() {
+		discount := 0.5
+		view := InvoiceView{Invoice: Invoice{ID: "1", Amount: 10.126, Rate: 0.1, Discount: &discount, Quantity: 1000000}}
+
+		result, err := Marshal(view, WithNumberFormat(NumberFormat{Decimals: 2}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(attributes(result))).Should(MatchJSON(`{"amount": 10.13, "rate": 0.1, "discount": 0.5, "quantity": 1000000}`))
+		Ω(string(attributes(result))).Should(ContainSubstring(`"amount":10.13`))
+	})
+
+	It("formats floats without exponent notation", func() {
+		view := InvoiceView{Invoice: Invoice{ID: "1", Amount: 0.0000001, Rate: 1e21}}
+
+		result, err := Marshal(view, WithNumberFormat(NumberFormat{Decimals: -1, NoExponent: true}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(attributes(result))).Should(ContainSubstring(`"amount":0.0000001`))
+		Ω(string(attributes(result))).Should(ContainSubstring(`"rate":1000000000000000000000`))
+		Ω(string(attributes(result))).Should(ContainSubstring(`"discount":null`))
+	})
+
+	It("keeps encoding/json formatting by default", func() {
+		view := InvoiceView{Invoice: Invoice{ID: "1", Amount: 0.0000001}}
+
+		result, err := Marshal(view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(attributes(result))).Should(ContainSubstring(`"amount":1e-7`))
+	})
+})
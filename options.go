@@ -0,0 +1,461 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Options describes Marshal and Unmarshal behavior settings.
+type Options struct {
+	// Strict enables JSON API specification conformance checks, a document which violates them is rejected with an error.
+	Strict bool
+	// RequestID when set, is stamped into meta of every marshaled error object, see RequestIDMeta.
+	RequestID string
+	// EmptyID describes how to-one relationship linkage with neither ID nor LID is marshaled.
+	EmptyID EmptyIDPolicy
+	// Fields sparse fieldsets by resource type, see MarshalWithFields.
+	Fields map[string][]string
+	// Include relationship paths requested by the client, e.g. "author" or "comments.author".
+	// When it is not nil, only included resources reachable from primary data through these paths are marshaled.
+	Include []string
+	// Cipher encrypts attributes on Marshal and decrypts them on Unmarshal, see Cipher.
+	Cipher Cipher
+	// Encrypted attributes by resource type, in addition to the ones tagged with `jsonapi:"encrypted"`.
+	Encrypted map[string][]string
+	// Version JSON API specification version the document must comply with, Version11 when it is empty.
+	// In Version10 mode the features introduced by JSON API 1.1 are rejected with ErrVersionFeature.
+	Version string
+	// TimeZone when set, time.Time attributes are converted into this location on Marshal and Unmarshal,
+	// e.g. time.UTC, and Unmarshal rejects time attributes not formatted as RFC 3339 with AttributeError.
+	TimeZone *time.Location
+	// NumberFormat when set, float attributes are marshaled according to it, see NumberFormat.
+	NumberFormat *NumberFormat
+	// Locale the request locale, e.g. "de-AT", see LocaleFromContext.
+	Locale string
+	// Formatter when set together with Locale, numeric and time attributes are additionally marshaled
+	// as locale formatted strings into resource meta, see FormattedMeta.
+	Formatter Formatter
+	// Translator when set together with Locale, translatable string attributes are localized, see Translator.
+	Translator Translator
+	// ErrorTranslator when set together with Locale, error objects title and detail are localized by their code, see ErrorTranslator.
+	ErrorTranslator ErrorTranslator
+	// Translatable translatable attribute names by resource type, in addition to the ones tagged `jsonapi:"translatable"`.
+	Translatable map[string][]string
+	// Sidepost enables sideposting on Unmarshal: included resources, usually new ones identified by lid,
+	// are unmarshaled into the Go types registered by RegisterResourceType in dependency order,
+	// and SetRelationships receives pointers to them instead of resource identifiers.
+	// To-many relationships referencing sideposted resources are passed as []interface{}.
+	Sidepost bool
+	// Existence when set, resources referenced by primary data relationships are checked to exist on Unmarshal,
+	// which returns ValidationError for the missing ones.
+	Existence *ExistencePolicy
+	// Create marks the document as resource creation request, primary data attributes absent from it
+	// get default values given by `jsonapi:"default=value"` field tags and Defaults, see UnmarshalDefaulted.
+	Create bool
+	// Defaults default attribute values by resource type, they take precedence over the field tags.
+	Defaults map[string]map[string]interface{}
+	// Enums allowed attribute values by resource type, in addition to the ones given by `jsonapi:"enum=a|b|c"` field tags.
+	// Unmarshal returns ValidationError with 422 error objects for the attributes having other values.
+	Enums map[string]map[string][]string
+	// MaxBlobSize maximum decoded size of Blob attributes in bytes, unlimited when it is zero,
+	// `jsonapi:"maxsize=N"` field tags take precedence over it.
+	MaxBlobSize int64
+	// LinkageLimit when set, truncates to-many relationships linkage exceeding the limits, see LinkageLimit.
+	LinkageLimit *LinkageLimit
+	// FileLinks signs upload and download links of the resources implementing MarshalFiles.
+	FileLinks FileLinkSigner
+	// Interner when set, interns resource types of the unmarshaled documents, see Interner.
+	Interner *Interner
+	// Naming transforms attribute and relationship names on Marshal, e.g. into CamelCase, independently of the json tags.
+	// Unmarshal names them back as the target knows them: by its json tags, tagged relationships and
+	// UnmarshalRelationshipNames, the members it doesn't know are left as is.
+	Naming NamingStrategy
+	// TypeNaming transforms resource types of the resource objects and relationships linkage on Marshal.
+	// Unmarshal names back the tagged types of the target, the other types need Unnaming.
+	TypeNaming NamingStrategy
+	// Unnaming inverse of Naming and TypeNaming, when set Unmarshal names every attribute, relationship
+	// and resource type back with it instead of by the names the target knows.
+	Unnaming NamingStrategy
+	// Scalars custom attribute types encoding and decoding, see Scalars.
+	Scalars *Scalars
+	// Context passed to ResourceValidator, context.Background() when it is nil.
+	Context context.Context
+	// DisallowUnknownFields makes Unmarshal return ValidationError with 400 error objects pointing to the attributes
+	// the target struct has no fields for, e.g. misspelled ones. Attribute names are matched exactly,
+	// targets unmarshaling attributes themselves accept any.
+	DisallowUnknownFields bool
+	// UseNumber decodes numbers of interface{} attributes and attributes maps as json.Number instead of float64,
+	// so large integers and high-precision decimals aren't rounded.
+	UseNumber bool
+	// MaxSize maximum size of the document read by UnmarshalReader in bytes, unlimited when it is zero.
+	MaxSize int64
+	// Resolvers included resources resolvers by resource type, see IncludeResolver.
+	Resolvers map[string]IncludeResolver
+	// IncludeFailure describes how failure of IncludeResolver is handled.
+	IncludeFailure IncludeFailurePolicy
+	// ResolveTimeout maximum duration of a single IncludeResolver call, unlimited when it is zero.
+	// The resources which resolution exceeds it are skipped, see IncludeResolver.
+	ResolveTimeout time.Duration
+	// IncludedCache when set, memoizes included resources of identical requests, see IncludedCache.
+	IncludedCache *IncludedCache
+	// Logger logs failures which don't fail Marshal, e.g. of IncludeResolver with IncludePartial policy.
+	Logger *log.Logger
+	// SkipNil skips nil resources of primary data, included and to-many relationships collections,
+	// Marshal fails with ErrNilResource on them by default.
+	SkipNil bool
+	// Visibility when set, drops the attributes it hides from the caller, e.g. by the caller's role, see AttributeVisibility.
+	Visibility AttributeVisibility
+	// OmitZero drops attributes of zero valued struct fields regardless of their omitempty json tag options,
+	// e.g. of vendor structs which tags can't be changed. Nullable attributes keep being marshaled when they are null.
+	OmitZero bool
+	// EmptyAttributes emits empty "attributes" object of the resources having no attributes,
+	// e.g. all of them are omitted as zero values, for clients requiring the member presence. They are dropped by default.
+	EmptyAttributes bool
+	// EmptyRelationships emits empty "relationships" object of the resources having no relationships,
+	// for clients requiring the member presence, e.g. older Ember Data. They are dropped by default.
+	EmptyRelationships bool
+	// Compact guarantees canonical compact output: no whitespace between the tokens and no trailing newline,
+	// e.g. for signatures and hashing. Indentation of MarshalIndent is ignored.
+	Compact bool
+	// EscapeHTML escapes <, > and & in the document strings, e.g. for documents embedded in HTML,
+	// they are not escaped by default.
+	EscapeHTML bool
+
+	sideposted map[ResourceObjectIdentifier]interface{}
+	report     *UnmarshalReport
+	trace      *replayTrace
+}
+
+// Option changes Marshal and Unmarshal behavior settings.
+type Option func(*Options)
+
+func newOptions(opts []Option) Options {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// WithOptions sets all the settings at once, the following options change them further.
+func WithOptions(options Options) Option {
+	return func(o *Options) {
+		*o = options
+	}
+}
+
+// WithContext sets context passed to ResourceValidator, see Options.Context.
+func WithContext(ctx context.Context) Option {
+	return func(o *Options) {
+		o.Context = ctx
+	}
+}
+
+// WithMaxSize sets maximum size of the document read by UnmarshalReader, see Options.MaxSize.
+func WithMaxSize(size int64) Option {
+	return func(o *Options) {
+		o.MaxSize = size
+	}
+}
+
+// WithMaxBlobSize sets maximum decoded size of Blob attributes, see Options.MaxBlobSize.
+func WithMaxBlobSize(size int64) Option {
+	return func(o *Options) {
+		o.MaxBlobSize = size
+	}
+}
+
+// WithLinkageLimit sets limits of to-many relationships linkage, see Options.LinkageLimit.
+func WithLinkageLimit(limit LinkageLimit) Option {
+	return func(o *Options) {
+		o.LinkageLimit = &limit
+	}
+}
+
+// WithFileLinks sets signer of the resource file links, see Options.FileLinks.
+func WithFileLinks(signer FileLinkSigner) Option {
+	return func(o *Options) {
+		o.FileLinks = signer
+	}
+}
+
+// WithSkipNil skips nil resources of collections, see Options.SkipNil.
+func WithSkipNil() Option {
+	return func(o *Options) {
+		o.SkipNil = true
+	}
+}
+
+// WithVisibility drops the attributes hidden from the caller, see Options.Visibility.
+func WithVisibility(visibility AttributeVisibility) Option {
+	return func(o *Options) {
+		o.Visibility = visibility
+	}
+}
+
+// WithOmitZero drops zero valued attributes, see Options.OmitZero.
+func WithOmitZero() Option {
+	return func(o *Options) {
+		o.OmitZero = true
+	}
+}
+
+// WithEmptyAttributes emits empty attributes objects, see Options.EmptyAttributes.
+func WithEmptyAttributes() Option {
+	return func(o *Options) {
+		o.EmptyAttributes = true
+	}
+}
+
+// WithNaming sets attribute and relationship names transformation, see Options.Naming.
+func WithNaming(naming NamingStrategy) Option {
+	return func(o *Options) {
+		o.Naming = naming
+	}
+}
+
+// WithNamingFunc sets bespoke transformation of attribute and relationship names and resource types on Marshal,
+// and its inverse naming them back on Unmarshal, see Options.Naming, Options.TypeNaming and Options.Unnaming.
+//
+// WithNamingFunc example:
+//
+//	naming := jsonapi.WithNamingFunc(func(name string) string {
+//	  return "x-" + name
+//	}, func(name string) string {
+//	  return strings.TrimPrefix(name, "x-")
+//	})
+//
+//	payload, err := jsonapi.Marshal(view, naming)
+func WithNamingFunc(naming, unnaming func(name string) string) Option {
+	return func(o *Options) {
+		o.Naming = naming
+		o.TypeNaming = naming
+		o.Unnaming = unnaming
+	}
+}
+
+// WithScalars sets custom attribute types encoding and decoding, see Options.Scalars.
+func WithScalars(scalars *Scalars) Option {
+	return func(o *Options) {
+		o.Scalars = scalars
+	}
+}
+
+// WithDisallowUnknownFields rejects unknown attributes, see Options.DisallowUnknownFields.
+func WithDisallowUnknownFields() Option {
+	return func(o *Options) {
+		o.DisallowUnknownFields = true
+	}
+}
+
+// WithUseNumber decodes attribute numbers as json.Number, see Options.UseNumber.
+func WithUseNumber() Option {
+	return func(o *Options) {
+		o.UseNumber = true
+	}
+}
+
+// WithEmptyRelationships emits empty relationships objects, see Options.EmptyRelationships.
+func WithEmptyRelationships() Option {
+	return func(o *Options) {
+		o.EmptyRelationships = true
+	}
+}
+
+// WithInterner sets interner of the unmarshaled documents strings, see Options.Interner.
+func WithInterner(in *Interner) Option {
+	return func(o *Options) {
+		o.Interner = in
+	}
+}
+
+// WithResolver sets included resources resolver of the resource type, see Options.Resolvers.
+func WithResolver(resourceType string, resolver IncludeResolver) Option {
+	return func(o *Options) {
+		resolvers := make(map[string]IncludeResolver, len(o.Resolvers)+1)
+
+		for typ, r := range o.Resolvers {
+			resolvers[typ] = r
+		}
+
+		resolvers[resourceType] = resolver
+		o.Resolvers = resolvers
+	}
+}
+
+// WithResolveTimeout sets maximum duration of a single IncludeResolver call, see Options.ResolveTimeout.
+func WithResolveTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.ResolveTimeout = timeout
+	}
+}
+
+// WithIncludedCache sets cache memoizing included resources, see Options.IncludedCache.
+func WithIncludedCache(cache *IncludedCache) Option {
+	return func(o *Options) {
+		o.IncludedCache = cache
+	}
+}
+
+// WithPartialIncludes makes Marshal succeed when IncludeResolver fails, see IncludePartial.
+func WithPartialIncludes(logger *log.Logger) Option {
+	return func(o *Options) {
+		o.IncludeFailure = IncludePartial
+		o.Logger = logger
+	}
+}
+
+// WithCompact guarantees canonical compact output, see Options.Compact.
+func WithCompact() Option {
+	return func(o *Options) {
+		o.Compact = true
+	}
+}
+
+// WithEscapeHTML escapes <, > and & in the document strings, see Options.EscapeHTML.
+func WithEscapeHTML() Option {
+	return func(o *Options) {
+		o.EscapeHTML = true
+	}
+}
+
+// WithStrict enables JSON API specification conformance checks, see Options.Strict.
+func WithStrict() Option {
+	return func(o *Options) {
+		o.Strict = true
+	}
+}
+
+// WithErrorsRequestID stamps request ID into meta of marshaled error objects, see Options.RequestID.
+func WithErrorsRequestID(id string) Option {
+	return func(o *Options) {
+		o.RequestID = id
+	}
+}
+
+// WithEmptyID sets how to-one relationship linkage with neither ID nor LID is marshaled, see Options.EmptyID.
+func WithEmptyID(policy EmptyIDPolicy) Option {
+	return func(o *Options) {
+		o.EmptyID = policy
+	}
+}
+
+// WithFields sets sparse fieldsets by resource type, see Options.Fields.
+func WithFields(fields map[string][]string) Option {
+	return func(o *Options) {
+		o.Fields = fields
+	}
+}
+
+// WithInclude sets relationship paths requested by the client, see Options.Include.
+func WithInclude(paths ...string) Option {
+	return func(o *Options) {
+		o.Include = append([]string{}, paths...)
+	}
+}
+
+// WithCipher sets attributes cipher and additionally encrypted attributes by resource type, see Options.Cipher.
+func WithCipher(cipher Cipher, encrypted map[string][]string) Option {
+	return func(o *Options) {
+		o.Cipher = cipher
+		o.Encrypted = encrypted
+	}
+}
+
+// WithVersion sets JSON API specification version the document must comply with, see Options.Version.
+func WithVersion(version string) Option {
+	return func(o *Options) {
+		o.Version = version
+	}
+}
+
+// WithTimeZone sets location time attributes are converted into, see Options.TimeZone.
+func WithTimeZone(loc *time.Location) Option {
+	return func(o *Options) {
+		o.TimeZone = loc
+	}
+}
+
+// WithNumberFormat sets how float attributes are marshaled, see Options.NumberFormat.
+func WithNumberFormat(format NumberFormat) Option {
+	return func(o *Options) {
+		o.NumberFormat = &format
+	}
+}
+
+// WithTranslator sets the request locale, translator and translatable attribute names by resource type, see Options.Translator.
+func WithTranslator(locale string, translator Translator, translatable map[string][]string) Option {
+	return func(o *Options) {
+		o.Locale = locale
+		o.Translator = translator
+		o.Translatable = translatable
+	}
+}
+
+// WithErrorTranslator sets the request locale and translator of error objects, see Options.ErrorTranslator.
+// MarshalContext takes the locale from context when it is empty.
+func WithErrorTranslator(locale string, translator ErrorTranslator) Option {
+	return func(o *Options) {
+		if len(locale) > 0 {
+			o.Locale = locale
+		}
+
+		o.ErrorTranslator = translator
+	}
+}
+
+// WithFormatter sets the request locale and formatter of the locale formatted attributes, see Options.Formatter.
+func WithFormatter(locale string, formatter Formatter) Option {
+	return func(o *Options) {
+		o.Locale = locale
+		o.Formatter = formatter
+	}
+}
+
+// WithSidepost enables sideposting on Unmarshal, see Options.Sidepost.
+func WithSidepost() Option {
+	return func(o *Options) {
+		o.Sidepost = true
+	}
+}
+
+// WithExistence sets policy resources referenced by relationships are checked with on Unmarshal, see Options.Existence.
+func WithExistence(policy ExistencePolicy) Option {
+	return func(o *Options) {
+		o.Existence = &policy
+	}
+}
+
+// WithCreate marks the document as resource creation request and sets default attribute values, see Options.Create.
+func WithCreate(defaults map[string]map[string]interface{}) Option {
+	return func(o *Options) {
+		o.Create = true
+		o.Defaults = defaults
+	}
+}
+
+// WithEnums sets allowed attribute values by resource type, see Options.Enums.
+func WithEnums(enums map[string]map[string][]string) Option {
+	return func(o *Options) {
+		o.Enums = enums
+	}
+}
+
+// EmptyIDPolicy describes how to-one relationship linkage with neither ID nor LID is marshaled.
+type EmptyIDPolicy int
+
+const (
+	// EmptyIDNull marshals such linkage as null, which is the default.
+	EmptyIDNull EmptyIDPolicy = iota
+	// EmptyIDKeep marshals such linkage as resource identifier without id member.
+	EmptyIDKeep
+	// EmptyIDError makes Marshal return ErrEmptyID.
+	EmptyIDError
+)
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Options", func() {
+
+	view := BookWithAuthorIncludedView{
+		BookWithAuthorView: BookWithAuthorView{
+			Book: BookWithAuthor{
+				Book:   Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+				Author: Author{ID: "1", Name: "William Kennedy"},
+			},
+		},
+	}
+
+	It("marshals with functional options", func() {
+		result, err := Marshal(view, WithInclude(), WithFields(map[string][]string{"books": {"title"}}))
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "title": "Go in Action"
+          }
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("applies options in order", func() {
+		result, err := Marshal(view, WithInclude(), WithOptions(Options{}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(result)).Should(ContainSubstring(`"included"`))
+	})
+
+	It("unmarshals with functional options", func() {
+		data := []byte(`{"data": {"type": "books", "id": "1"}, "errors": []}`)
+
+		_, err := Unmarshal(data, &BookView{}, WithStrict())
+
+		Ω(err).Should(Equal(ErrDataAndErrors))
+	})
+})
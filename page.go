@@ -0,0 +1,160 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// PageStrategy describes pagination strategy.
+type PageStrategy int
+
+const (
+	// PageNumberStrategy uses page[number] and page[size] parameters.
+	PageNumberStrategy PageStrategy = iota
+	// PageOffsetStrategy uses page[offset] and page[limit] parameters.
+	PageOffsetStrategy
+	// PageCursorStrategy uses page[cursor] and page[size] parameters.
+	PageCursorStrategy
+)
+
+// PageConfig describes pagination settings of an endpoint.
+type PageConfig struct {
+	// Strategy pagination strategy, parameters of other strategies are rejected.
+	Strategy PageStrategy
+	// DefaultSize page size used when it is not given.
+	DefaultSize int
+	// MaxSize clamps requested page size, zero means no limit.
+	MaxSize int
+}
+
+// Page describes parsed pagination parameters. Size and Limit are filled for every strategy, Offset for every strategy
+// but cursor one, Number for page number strategy only.
+type Page struct {
+	Strategy PageStrategy
+	// Number 1-based page number.
+	Number int
+	// Size page size, equals to Limit.
+	Size int
+	// Offset number of skipped resources.
+	Offset int
+	// Limit maximum number of resources.
+	Limit int
+	// Cursor opaque page cursor.
+	Cursor string
+}
+
+var pageParams = map[PageStrategy][]string{
+	PageNumberStrategy: {"number", "size"},
+	PageOffsetStrategy: {"offset", "limit"},
+	PageCursorStrategy: {"cursor", "size"},
+}
+
+// ParsePage parses page parameters, e.g. QueryParams.Page, according to the config.
+// The errors are returned as error objects with source parameter, page number or offset is rejected
+// when the end of the page, Offset plus Limit, overflows int.
+//
+// ParsePage example:
+//
+//	params, errs := jsonapi.ParseQuery(req.URL.Query())
+//	...
+//	page, errs := jsonapi.ParsePage(params.Page, jsonapi.PageConfig{DefaultSize: 20, MaxSize: 100})
+//
+//	start, end := page.Offset, page.Offset+page.Limit
+//	if start > len(allBooks) {
+//	  start = len(allBooks)
+//	}
+//	if end > len(allBooks) {
+//	  end = len(allBooks)
+//	}
+//
+//	books := allBooks[start:end]
+func ParsePage(params map[string]string, config PageConfig) (Page, []*ErrorObject) {
+	var errs []*ErrorObject
+
+	page := Page{Strategy: config.Strategy, Number: 1, Size: config.DefaultSize}
+
+	allowed := map[string]bool{}
+
+	for _, name := range pageParams[config.Strategy] {
+		allowed[name] = true
+	}
+
+	names := make([]string, 0, len(params))
+
+	for name := range params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		parameter := QueryKey("page", name)
+
+		if !allowed[name] {
+			errs = append(errs, queryError(parameter, "Unsupported query parameter", fmt.Sprintf("Query parameter %q is not supported.", parameter)))
+			continue
+		}
+
+		if name == "cursor" {
+			page.Cursor = params[name]
+			continue
+		}
+
+		min := 1
+		if name == "offset" {
+			min = 0
+		}
+
+		value, err := strconv.Atoi(params[name])
+		if err != nil || value < min {
+			errs = append(errs, queryError(parameter, "Invalid query parameter", fmt.Sprintf("Query parameter %q must be an integer not less than %d.", parameter, min)))
+			continue
+		}
+
+		switch name {
+		case "number":
+			page.Number = value
+		case "size", "limit":
+			page.Size = value
+		case "offset":
+			page.Offset = value
+		}
+	}
+
+	if config.MaxSize > 0 && page.Size > config.MaxSize {
+		page.Size = config.MaxSize
+	}
+
+	page.Limit = page.Size
+
+	switch {
+	case config.Strategy == PageNumberStrategy && page.Size > 0 && page.Number > math.MaxInt/page.Size:
+		errs = append(errs, pageTooLargeError("number"))
+		page.Number = 1
+	case config.Strategy == PageOffsetStrategy && page.Offset > math.MaxInt-page.Size:
+		errs = append(errs, pageTooLargeError("offset"))
+		page.Offset = 0
+	}
+
+	if config.Strategy == PageNumberStrategy {
+		page.Offset = (page.Number - 1) * page.Size
+	}
+
+	if config.Strategy != PageNumberStrategy {
+		page.Number = 0
+	}
+
+	return page, errs
+}
+
+func pageTooLargeError(name string) *ErrorObject {
+	parameter := QueryKey("page", name)
+
+	return queryError(parameter, "Invalid query parameter", fmt.Sprintf("Query parameter %q is too large.", parameter))
+}
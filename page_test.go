@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"math"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("ParsePage", func() {
+
+	It("parses page number and size", func() {
+		page, errs := ParsePage(map[string]string{"number": "3", "size": "10"}, PageConfig{DefaultSize: 20})
+
+		Ω(errs).Should(BeEmpty())
+		Ω(page).Should(Equal(Page{Strategy: PageNumberStrategy, Number: 3, Size: 10, Offset: 20, Limit: 10}))
+	})
+
+	It("uses default size and clamps size to max", func() {
+		page, errs := ParsePage(map[string]string{}, PageConfig{DefaultSize: 20})
+
+		Ω(errs).Should(BeEmpty())
+		Ω(page).Should(Equal(Page{Strategy: PageNumberStrategy, Number: 1, Size: 20, Offset: 0, Limit: 20}))
+
+		page, errs = ParsePage(map[string]string{"size": "500"}, PageConfig{DefaultSize: 20, MaxSize: 100})
+
+		Ω(errs).Should(BeEmpty())
+		Ω(page.Size).Should(Equal(100))
+	})
+
+	It("parses page offset and limit", func() {
+		page, errs := ParsePage(map[string]string{"offset": "0", "limit": "5"}, PageConfig{Strategy: PageOffsetStrategy})
+
+		Ω(errs).Should(BeEmpty())
+		Ω(page).Should(Equal(Page{Strategy: PageOffsetStrategy, Size: 5, Offset: 0, Limit: 5}))
+	})
+
+	It("parses page cursor", func() {
+		page, errs := ParsePage(map[string]string{"cursor": "abc", "size": "5"}, PageConfig{Strategy: PageCursorStrategy})
+
+		Ω(errs).Should(BeEmpty())
+		Ω(page).Should(Equal(Page{Strategy: PageCursorStrategy, Size: 5, Limit: 5, Cursor: "abc"}))
+	})
+
+	It("reports invalid and unsupported page parameters", func() {
+		_, errs := ParsePage(map[string]string{"number": "0", "size": "ten", "offset": "1"}, PageConfig{})
+
+		var parameters []string
+
+		for _, err := range errs {
+			Ω(err.Status).Should(Equal("400"))
+			parameters = append(parameters, err.Source.Parameter)
+		}
+
+		Ω(parameters).Should(Equal([]string{"page[number]", "page[offset]", "page[size]"}))
+	})
+
+	It("rejects page number and offset overflowing the page end", func() {
+		page, errs := ParsePage(map[string]string{"number": strconv.Itoa(math.MaxInt), "size": "10"}, PageConfig{})
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Source.Parameter).Should(Equal("page[number]"))
+		Ω(page.Offset).Should(Equal(0))
+
+		page, errs = ParsePage(map[string]string{"offset": strconv.Itoa(math.MaxInt), "limit": "10"}, PageConfig{Strategy: PageOffsetStrategy})
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Source.Parameter).Should(Equal("page[offset]"))
+		Ω(page.Offset).Should(Equal(0))
+	})
+})
@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// PageMeta describes standard pagination document meta.
+type PageMeta struct {
+	// Total number of resources in the collection.
+	Total int `json:"total"`
+	// PerPage page size.
+	PerPage int `json:"per_page"`
+	// CurrentPage 1-based current page number.
+	CurrentPage int `json:"current_page"`
+	// TotalPages number of pages.
+	TotalPages int `json:"total_pages"`
+}
+
+// NewPageMeta returns pagination meta of the page within collection of total resources.
+func NewPageMeta(page Page, total int) PageMeta {
+	meta := PageMeta{Total: total, PerPage: page.Limit, CurrentPage: 1}
+
+	if page.Limit > 0 {
+		meta.CurrentPage = page.Offset/page.Limit + 1
+		meta.TotalPages = (total + page.Limit - 1) / page.Limit
+	}
+
+	return meta
+}
+
+// PageLinks returns self, first, prev, next and last pagination links of the page within collection of total resources.
+// Links are built from the request URL u, keeping its other query parameters. Prev and next links are omitted
+// on the first and the last pages. Cursor pages have self link only.
+func PageLinks(u *url.URL, page Page, total int) Links {
+	links := Links{
+		"self": {Href: u.String()},
+	}
+
+	if page.Strategy == PageCursorStrategy || page.Limit <= 0 {
+		return links
+	}
+
+	meta := NewPageMeta(page, total)
+
+	last := meta.TotalPages
+	if last < 1 {
+		last = 1
+	}
+
+	link := func(number int) *Link {
+		return &Link{Href: pageURL(u, page, number)}
+	}
+
+	links["self"] = link(meta.CurrentPage)
+	links["first"] = link(1)
+	links["last"] = link(last)
+
+	if meta.CurrentPage > 1 {
+		links["prev"] = link(meta.CurrentPage - 1)
+	}
+
+	if meta.CurrentPage < last {
+		links["next"] = link(meta.CurrentPage + 1)
+	}
+
+	return links
+}
+
+func pageURL(u *url.URL, page Page, number int) string {
+	copied := *u
+	query := copied.Query()
+
+	switch page.Strategy {
+	case PageOffsetStrategy:
+		query.Set(QueryKey("page", "offset"), strconv.Itoa((number-1)*page.Limit))
+		query.Set(QueryKey("page", "limit"), strconv.Itoa(page.Limit))
+	default:
+		query.Set(QueryKey("page", "number"), strconv.Itoa(number))
+		query.Set(QueryKey("page", "size"), strconv.Itoa(page.Limit))
+	}
+
+	copied.RawQuery = query.Encode()
+
+	return copied.String()
+}
+
+// PaginatedView is a view of a collection page, it marshals the page data and included
+// along with pagination meta and links.
+//
+// PaginatedView example:
+//
+//	page, errs := jsonapi.ParsePage(params.Page, config)
+//	...
+//	view := jsonapi.Paginate(books[page.Offset:end], page, len(books), req.URL)
+//	payload, err := jsonapi.Marshal(view)
+type PaginatedView struct {
+	// Data page resources.
+	Data interface{}
+	// Included resources.
+	Included []interface{}
+	// Meta pagination meta.
+	Meta PageMeta
+	// Links pagination links.
+	Links Links
+}
+
+// Paginate returns view of data being the page within collection of total resources, u is the request URL.
+func Paginate(data interface{}, page Page, total int, u *url.URL) PaginatedView {
+	return PaginatedView{
+		Data:  data,
+		Meta:  NewPageMeta(page, total),
+		Links: PageLinks(u, page, total),
+	}
+}
+
+// GetData returns page resources.
+func (v PaginatedView) GetData() interface{} {
+	return v.Data
+}
+
+// GetIncluded returns included resources.
+func (v PaginatedView) GetIncluded() []interface{} {
+	return v.Included
+}
+
+// GetMeta returns pagination meta.
+func (v PaginatedView) GetMeta() interface{} {
+	return v.Meta
+}
+
+// GetLinks returns pagination links.
+func (v PaginatedView) GetLinks() Links {
+	return v.Links
+}
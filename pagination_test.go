@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Pagination", func() {
+
+	It("returns pagination meta", func() {
+		page := Page{Number: 2, Size: 10, Offset: 10, Limit: 10}
+
+		Ω(NewPageMeta(page, 25)).Should(Equal(PageMeta{Total: 25, PerPage: 10, CurrentPage: 2, TotalPages: 3}))
+	})
+
+	It("marshals paginated view", func() {
+		u, _ := url.Parse("/books?sort=title")
+		page := Page{Number: 2, Size: 1, Offset: 1, Limit: 1}
+
+		view := Paginate(Books{{ID: "2", Title: "Go in Action", Year: "2015", Type: "books"}}, page, 3, u)
+
+		result, err := Marshal(view)
+
+		expected := `
+      {
+        "data": [
+          {
+            "type": "books",
+            "id": "2",
+            "attributes": {
+              "title": "Go in Action",
+              "year": "2015"
+            }
+          }
+        ],
+        "meta": {
+          "total": 3,
+          "per_page": 1,
+          "current_page": 2,
+          "total_pages": 3
+        },
+        "links": {
+          "self": "/books?page%5Bnumber%5D=2&page%5Bsize%5D=1&sort=title",
+          "first": "/books?page%5Bnumber%5D=1&page%5Bsize%5D=1&sort=title",
+          "prev": "/books?page%5Bnumber%5D=1&page%5Bsize%5D=1&sort=title",
+          "next": "/books?page%5Bnumber%5D=3&page%5Bsize%5D=1&sort=title",
+          "last": "/books?page%5Bnumber%5D=3&page%5Bsize%5D=1&sort=title"
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("omits prev and next links of the only page", func() {
+		u, _ := url.Parse("/books")
+		page := Page{Strategy: PageOffsetStrategy, Size: 10, Limit: 10}
+
+		links := PageLinks(u, page, 3)
+
+		Ω(links).Should(HaveKey("first"))
+		Ω(links).Should(HaveKey("last"))
+		Ω(links).ShouldNot(HaveKey("prev"))
+		Ω(links).ShouldNot(HaveKey("next"))
+		Ω(links["first"].Href).Should(Equal("/books?page%5Blimit%5D=10&page%5Boffset%5D=0"))
+	})
+})
@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidPatch returned when PATCH document can't be applied to the existing resource, the error is wrapped with the details.
+var ErrInvalidPatch = errors.New("jsonapi: invalid patch")
+
+// ApplyPatch unmarshals PATCH document into the existing resource, only attributes and relationships present
+// in the document are overwritten, the other fields of existing are left untouched.
+// Null attributes reset non-nullable fields to their zero values.
+// The document must have single primary resource, its type and ID must match existing one when they are known.
+//
+// ApplyPatch example:
+//
+//	book := store.Find(id)
+//
+//	if err := jsonapi.ApplyPatch(&book, payload); err != nil {
+//	  ...
+//	}
+func ApplyPatch(existing interface{}, payload []byte, opts ...Option) error {
+	options := newOptions(opts)
+
+	doc, err := decodeDocument(payload, &options)
+	if err != nil {
+		return err
+	}
+
+	if doc.Data == nil || doc.Data.One == nil {
+		return fmt.Errorf("%w: document must have single primary resource", ErrInvalidPatch)
+	}
+
+	ro := doc.Data.One
+
+	roi, err := checkPatchTarget(existing, ro)
+	if err != nil {
+		return err
+	}
+
+	// PATCH document without id keeps the existing one
+	if len(ro.ID) == 0 {
+		ro.ID = roi.ID
+	}
+
+	if err := resetNullAttributes(existing, ro.Attributes); err != nil {
+		return err
+	}
+
+	if ud, ok := existing.(UnmarshalData); ok {
+		return ud.SetData(func(target interface{}) error {
+			return unmarshalOne(ro, target, &options)
+		})
+	}
+
+	return unmarshalOne(ro, existing, &options)
+}
+
+func checkPatchTarget(existing interface{}, ro *ResourceObject) (ResourceObjectIdentifier, error) {
+	var roi ResourceObjectIdentifier
+
+	if mri, ok := existing.(MarshalResourceIdentifier); ok {
+		roi = marshalResourceObjectIdentifier(mri)
+	} else if tr, ok := newTaggedResource(existing); ok {
+		roi = marshalResourceObjectIdentifier(tr)
+	} else {
+		return roi, nil
+	}
+
+	if len(roi.Type) > 0 && roi.Type != ro.Type {
+		return roi, fmt.Errorf("%w: resource type %q doesn't match %q", ErrInvalidPatch, ro.Type, roi.Type)
+	}
+
+	if len(roi.ID) > 0 && len(ro.ID) > 0 && roi.ID != ro.ID {
+		return roi, fmt.Errorf("%w: resource id %q doesn't match %q", ErrInvalidPatch, ro.ID, roi.ID)
+	}
+
+	return roi, nil
+}
+
+// resetNullAttributes sets fields of null attributes to zero values, json.Unmarshal leaves non-nullable ones unchanged.
+func resetNullAttributes(existing interface{}, attributes json.RawMessage) error {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	value := reflect.ValueOf(existing)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil
+	}
+
+	value = value.Elem()
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var members map[string]json.RawMessage
+
+	if err := json.Unmarshal(attributes, &members); err != nil {
+		return err
+	}
+
+	for name, field := range jsonFields(value.Type()) {
+		if raw, ok := members[name]; !ok || string(raw) != "null" {
+			continue
+		}
+
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if ok && fieldValue.CanSet() {
+			fieldValue.Set(reflect.Zero(fieldValue.Type()))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Track struct {
+	ID       string   `json:"-"`
+	Title    string   `json:"title"`
+	Artist   string   `json:"artist"`
+	Duration int      `json:"duration"`
+	Genres   []string `json:"genres"`
+	AlbumID  string   `json:"-"`
+}
+
+func (t Track) GetID() string {
+	return t.ID
+}
+
+func (t Track) GetType() string {
+	return "tracks"
+}
+
+func (t *Track) SetID(id string) error {
+	t.ID = id
+	return nil
+}
+
+func (t *Track) SetType(string) error {
+	return nil
+}
+
+func (t *Track) SetRelationships(relationships map[string]interface{}) error {
+	if album, ok := ToOne(relationships, "album"); ok {
+		t.AlbumID = album.ID
+	}
+
+	return nil
+}
+
+var _ = Describe("ApplyPatch", func() {
+	var track Track
+
+	BeforeEach(func() {
+		track = Track{ID: "1", Title: "Song", Artist: "Band", Duration: 180, Genres: []string{"rock"}, AlbumID: "7"}
+	})
+
+	It("overwrites only attributes and relationships present in the document", func() {
+		err := ApplyPatch(&track, []byte(`{"data": {"type": "tracks", "id": "1", "attributes": {"title": "Ballad"}}}`))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(track).Should(Equal(Track{ID: "1", Title: "Ballad", Artist: "Band", Duration: 180, Genres: []string{"rock"}, AlbumID: "7"}))
+
+		err = ApplyPatch(&track, []byte(`{"data": {"type": "tracks", "id": "1", "relationships": {"album": {"data": {"type": "albums", "id": "8"}}}}}`))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(track).Should(Equal(Track{ID: "1", Title: "Ballad", Artist: "Band", Duration: 180, Genres: []string{"rock"}, AlbumID: "8"}))
+	})
+
+	It("keeps the existing id when the document has no id", func() {
+		err := ApplyPatch(&track, []byte(`{"data": {"type": "tracks", "attributes": {"title": "Ballad"}}}`))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(track).Should(Equal(Track{ID: "1", Title: "Ballad", Artist: "Band", Duration: 180, Genres: []string{"rock"}, AlbumID: "7"}))
+	})
+
+	It("resets null attributes to zero values", func() {
+		err := ApplyPatch(&track, []byte(`{"data": {"type": "tracks", "id": "1", "attributes": {"artist": null, "duration": null, "genres": null}}}`))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(track).Should(Equal(Track{ID: "1", Title: "Song", AlbumID: "7"}))
+	})
+
+	It("fails when the resource doesn't match the existing one", func() {
+		err := ApplyPatch(&track, []byte(`{"data": {"type": "tracks", "id": "2", "attributes": {"title": "Ballad"}}}`))
+		Ω(err).Should(MatchError(ErrInvalidPatch))
+
+		err = ApplyPatch(&track, []byte(`{"data": {"type": "albums", "id": "1", "attributes": {"title": "Ballad"}}}`))
+		Ω(err).Should(MatchError(ErrInvalidPatch))
+
+		Ω(track.Title).Should(Equal("Song"))
+	})
+
+	It("fails when the document has no single primary resource", func() {
+		err := ApplyPatch(&track, []byte(`{"data": [{"type": "tracks", "id": "1"}]}`))
+		Ω(err).Should(MatchError(ErrInvalidPatch))
+	})
+})
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPointer returned when JSON pointer doesn't refer to a value which can be changed, the error is wrapped with the details.
+var ErrInvalidPointer = errors.New("jsonapi: invalid JSON pointer")
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// escapePointer escapes JSON Pointer reference token according to RFC 6901.
+func escapePointer(token string) string {
+	return pointerEscaper.Replace(token)
+}
+
+// Pointer returns JSON Pointer [RFC6901] built of the escaped reference tokens,
+// e.g. Pointer("data", "attributes", "first/name") gives "/data/attributes/first~1name".
+func Pointer(tokens ...string) string {
+	var b strings.Builder
+
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(escapePointer(token))
+	}
+
+	return b.String()
+}
+
+// ParsePointer returns unescaped reference tokens of JSON Pointer,
+// e.g. ParsePointer("/data/attributes/first~1name") gives ["data", "attributes", "first/name"].
+func ParsePointer(pointer string) ([]string, error) {
+	if len(pointer) == 0 {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("jsonapi: JSON pointer %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+
+	for i, token := range tokens {
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// CollectionPointer returns pointer to a member of the collection payload element,
+// e.g. CollectionPointer(3, "relationships", "author", "data", "id") gives "/data/3/relationships/author/data/id".
+func CollectionPointer(index int, members ...string) string {
+	return "/data/" + strconv.Itoa(index) + Pointer(members...)
+}
+
+// ParseCollectionPointer returns the collection payload element index and the member path of the pointer built by CollectionPointer,
+// e.g. "/data/3/relationships/author/data/id" gives 3 and ["relationships", "author", "data", "id"].
+// It returns false when the pointer doesn't refer to a collection element.
+func ParseCollectionPointer(pointer string) (int, []string, bool) {
+	tokens, err := ParsePointer(pointer)
+	if err != nil || len(tokens) < 2 || tokens[0] != "data" {
+		return 0, nil, false
+	}
+
+	index, err := strconv.Atoi(tokens[1])
+	if err != nil || index < 0 || strconv.Itoa(index) != tokens[1] {
+		return 0, nil, false
+	}
+
+	return index, tokens[2:], true
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Pointer", func() {
+
+	It("builds escaped pointer", func() {
+		Ω(Pointer("data", "attributes", "first/name", "a~b")).Should(Equal("/data/attributes/first~1name/a~0b"))
+	})
+
+	It("parses pointer", func() {
+		tokens, err := ParsePointer("/data/attributes/first~1name/a~0b")
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(tokens).Should(Equal([]string{"data", "attributes", "first/name", "a~b"}))
+	})
+
+	It("returns error for relative pointer", func() {
+		_, err := ParsePointer("data/attributes")
+
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("builds collection pointer", func() {
+		Ω(CollectionPointer(3, "relationships", "author", "data", "id")).Should(Equal("/data/3/relationships/author/data/id"))
+		Ω(CollectionPointer(0)).Should(Equal("/data/0"))
+	})
+
+	It("parses collection pointer", func() {
+		index, members, ok := ParseCollectionPointer("/data/3/relationships/author/data/id")
+
+		Ω(ok).Should(BeTrue())
+		Ω(index).Should(Equal(3))
+		Ω(members).Should(Equal([]string{"relationships", "author", "data", "id"}))
+	})
+
+	It("doesn't parse pointer to a single resource", func() {
+		_, _, ok := ParseCollectionPointer("/data/attributes/title")
+
+		Ω(ok).Should(BeFalse())
+
+		_, _, ok = ParseCollectionPointer("/data/03")
+
+		Ω(ok).Should(BeFalse())
+	})
+})
@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// FieldSet is a set of resource fields, i.e. attribute and relationship names.
+type FieldSet map[string]bool
+
+// Has returns true when the set contains the field.
+func (fs FieldSet) Has(name string) bool {
+	return fs[name]
+}
+
+// Names returns the set fields in sorted order.
+func (fs FieldSet) Names() []string {
+	names := make([]string, 0, len(fs))
+
+	for name := range fs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// UnmarshalPresentFields interface may be implemented to learn which attributes and relationships
+// were present in the document, e.g. to update only them on PATCH requests.
+//
+// SetPresentFields example:
+//
+//	func (s *SomeStruct) SetPresentFields(fields jsonapi.FieldSet) error {
+//	  s.Present = fields
+//	  return nil
+//	}
+type UnmarshalPresentFields interface {
+	SetPresentFields(fields FieldSet) error
+}
+
+// PresentFields returns attributes and relationships present in the resource object,
+// attributes are present when they are null too.
+func (ro *ResourceObject) PresentFields() FieldSet {
+	fields := FieldSet{}
+
+	var attributes map[string]json.RawMessage
+
+	if len(ro.Attributes) > 0 && json.Unmarshal(ro.Attributes, &attributes) == nil {
+		for name := range attributes {
+			fields[name] = true
+		}
+	}
+
+	for name := range ro.Relationships {
+		fields[name] = true
+	}
+
+	return fields
+}
+
+// PresentFields returns attributes and relationships present in the single primary resource,
+// nil when primary data isn't a single resource.
+func (d *Document) PresentFields() FieldSet {
+	if d.Data == nil || d.Data.One == nil {
+		return nil
+	}
+
+	return d.Data.One.PresentFields()
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Chapter struct {
+	ID      string   `json:"-"`
+	Title   string   `json:"title"`
+	Summary string   `json:"summary"`
+	Present FieldSet `json:"-"`
+}
+
+func (c *Chapter) SetID(id string) error {
+	c.ID = id
+	return nil
+}
+
+func (c *Chapter) SetType(string) error {
+	return nil
+}
+
+func (c *Chapter) SetData(to func(target interface{}) error) error {
+	return to(c)
+}
+
+func (c *Chapter) SetPresentFields(fields FieldSet) error {
+	c.Present = fields
+	return nil
+}
+
+type Chapters []Chapter
+
+func (c *Chapters) SetData(to func(target interface{}) error) error {
+	return to(c)
+}
+
+var _ = Describe("Present fields", func() {
+	payload := []byte(`
+		{
+			"data": {
+				"type": "chapters",
+				"id": "1",
+				"attributes": {"summary": null},
+				"relationships": {"book": {"data": {"type": "books", "id": "1"}}}
+			}
+		}
+	`)
+
+	It("records attributes and relationships present in the document, null ones included", func() {
+		var chapter Chapter
+
+		doc, err := Unmarshal(payload, &chapter)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(chapter.Present.Names()).Should(Equal([]string{"book", "summary"}))
+		Ω(chapter.Present.Has("title")).Should(BeFalse())
+		Ω(doc.PresentFields()).Should(Equal(chapter.Present))
+	})
+
+	It("records present fields of each collection resource", func() {
+		var chapters Chapters
+
+		doc, err := Unmarshal([]byte(`
+			{
+				"data": [
+					{"type": "chapters", "id": "1", "attributes": {"title": "One"}},
+					{"type": "chapters", "id": "2"}
+				]
+			}
+		`), &chapters)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(chapters[0].Present.Names()).Should(Equal([]string{"title"}))
+		Ω(chapters[1].Present.Names()).Should(BeEmpty())
+		Ω(doc.PresentFields()).Should(BeNil())
+	})
+})
@@ -0,0 +1,236 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProblemContentType describes RFC 7807 problem details content type.
+const ProblemContentType = "application/problem+json"
+
+// Problem describes RFC 7807 problem details document https://tools.ietf.org/html/rfc7807
+type Problem struct {
+	// Type a URI reference that identifies the problem type.
+	Type string `json:"type,omitempty"`
+	// Title a short, human-readable summary of the problem type.
+	Title string `json:"title,omitempty"`
+	// Status the HTTP status code generated by the origin server for this occurrence of the problem.
+	Status int `json:"status,omitempty"`
+	// Detail a human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance a URI reference that identifies the specific occurrence of the problem.
+	Instance string `json:"instance,omitempty"`
+	// Code extension member, application specified value to identify the error.
+	Code string `json:"code,omitempty"`
+	// Pointer extension member, a JSON Pointer [RFC6901] to the associated entity in the request document.
+	Pointer string `json:"pointer,omitempty"`
+	// Parameter extension member, URI query parameter which caused the problem.
+	Parameter string `json:"parameter,omitempty"`
+	// Header extension member, request header which caused the problem.
+	Header string `json:"header,omitempty"`
+	// Meta extension member, non-standard meta-information about the problem.
+	Meta map[string]interface{} `json:"meta,omitempty"`
+	// Errors extension member, contains all error objects when the problem describes several of them.
+	Errors []*ErrorObject `json:"errors,omitempty"`
+}
+
+// ProblemFromErrors converts error objects into RFC 7807 problem document, nil error objects are skipped.
+// A single error object is mapped member by member, its "type" link becomes the problem type.
+// Several error objects are kept in Errors extension member and the problem status is derived from their statuses.
+func ProblemFromErrors(errs []*ErrorObject) Problem {
+	var present []*ErrorObject
+
+	for _, err := range errs {
+		if err != nil {
+			present = append(present, err)
+		}
+	}
+
+	errs = present
+
+	if len(errs) == 1 {
+		err := errs[0]
+
+		problem := Problem{
+			Title:     err.Title,
+			Status:    errorStatus(err),
+			Detail:    err.Detail,
+			Code:      err.Code,
+			Pointer:   err.Source.Pointer,
+			Parameter: err.Source.Parameter,
+			Header:    err.Source.Header,
+			Meta:      err.Meta,
+		}
+
+		if link := err.Links["type"]; link != nil {
+			problem.Type = link.Href
+		}
+
+		return problem
+	}
+
+	return Problem{
+		Title:  http.StatusText(StatusForErrors(errs)),
+		Status: StatusForErrors(errs),
+		Errors: errs,
+	}
+}
+
+// ErrorObjects converts RFC 7807 problem document into error objects.
+func (p Problem) ErrorObjects() []*ErrorObject {
+	if len(p.Errors) > 0 {
+		return p.Errors
+	}
+
+	err := &ErrorObject{
+		Title:  p.Title,
+		Detail: p.Detail,
+		Code:   p.Code,
+		Source: ErrorObjectSource{Pointer: p.Pointer, Parameter: p.Parameter, Header: p.Header},
+		Meta:   p.Meta,
+	}
+
+	if p.Status > 0 {
+		err.Status = strconv.Itoa(p.Status)
+	}
+
+	if len(p.Type) > 0 {
+		err.Links = Links{"type": {Href: p.Type}}
+	}
+
+	return []*ErrorObject{err}
+}
+
+// WriteErrors writes error objects into HTTP response, as RFC 7807 problem document when request Accept header
+// prefers problem details media type, by quality values and then by order, as JSON API document otherwise.
+// Request ID attached to the request context by WithRequestID is stamped into error objects meta,
+// title and detail templates are formatted with the parameters carried in meta, see FormatErrors.
+func WriteErrors(w http.ResponseWriter, r *http.Request, errs []*ErrorObject) error {
+	var (
+		payload     interface{}
+		contentType string
+	)
+
+	errs = FormatErrors(errs)
+
+	if r != nil {
+		if id, ok := RequestIDFromContext(r.Context()); ok {
+			errs = stampRequestID(errs, id)
+		}
+	}
+
+	if acceptsProblem(r) {
+		payload = ProblemFromErrors(errs)
+		contentType = ProblemContentType
+	} else {
+		payload = &Document{Errors: errs}
+		contentType = ContentType
+	}
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(payload); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(StatusForErrors(errs))
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// ErrorResponse writes error objects into HTTP response as JSON API error document, with status code by StatusForErrors.
+// Use WriteErrors instead to take the request into account, e.g. its request ID.
+//
+// ErrorResponse example:
+//
+//	if book == nil {
+//	  _ = jsonapi.ErrorResponse(w, jsonapi.NewError(http.StatusNotFound, "not_found", "Book not found", ""))
+//	  return
+//	}
+func ErrorResponse(w http.ResponseWriter, errs ...*ErrorObject) error {
+	return WriteErrors(w, nil, errs)
+}
+
+// acceptsProblem reports whether the request Accept header prefers problem details media type to JSON API one,
+// the media type of higher quality value wins, the first listed one when they are equal.
+func acceptsProblem(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	preferred, quality := "", 0.0
+
+	for _, header := range r.Header.Values("Accept") {
+		for _, accept := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+			if err != nil || (mediaType != ProblemContentType && mediaType != ContentType) {
+				continue
+			}
+
+			q := 1.0
+
+			if value, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(value, 64); err != nil {
+					continue
+				}
+			}
+
+			if q > quality {
+				preferred, quality = mediaType, q
+			}
+		}
+	}
+
+	return preferred == ProblemContentType
+}
+
+func errorStatus(err *ErrorObject) int {
+	if err == nil {
+		return 0
+	}
+
+	status, _ := strconv.Atoi(err.Status)
+
+	return status
+}
+
+// StatusForErrors returns HTTP status code of the response having error objects, by their statuses.
+// It is the status the error objects share, 500 Internal Server Error when any of them is server error,
+// 400 Bad Request when client errors are mixed, or no error object has status.
+func StatusForErrors(errs []*ErrorObject) int {
+	status := 0
+
+	for _, err := range errs {
+		current := errorStatus(err)
+
+		switch {
+		case current == 0 || current == status:
+			continue
+		case status == 0:
+			status = current
+		case status >= 500 || current >= 500:
+			status = http.StatusInternalServerError
+		default:
+			status = http.StatusBadRequest
+		}
+	}
+
+	if status == 0 {
+		status = http.StatusBadRequest
+	}
+
+	return status
+}
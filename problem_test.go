@@ -0,0 +1,191 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Problem", func() {
+
+	errs := []*ErrorObject{
+		{
+			Status: "422",
+			Title:  "is required",
+			Code:   "is_required",
+			Source: ErrorObjectSource{
+				Pointer: "/data/attributes/title",
+			},
+		},
+	}
+
+	Describe("ProblemFromErrors", func() {
+
+		It("converts single error object", func() {
+			Ω(ProblemFromErrors(errs)).Should(Equal(Problem{
+				Title:   "is required",
+				Status:  422,
+				Code:    "is_required",
+				Pointer: "/data/attributes/title",
+			}))
+		})
+
+		It("converts several error objects with mixed statuses", func() {
+			problem := ProblemFromErrors([]*ErrorObject{
+				{Status: "422", Title: "is required"},
+				{Status: "409", Title: "already exists"},
+			})
+
+			Ω(problem.Status).Should(Equal(400))
+			Ω(problem.Errors).Should(HaveLen(2))
+		})
+
+		It("converts problem back into error objects", func() {
+			Ω(ProblemFromErrors(errs).ErrorObjects()).Should(Equal(errs))
+		})
+
+		It("converts source parameter, header and type link", func() {
+			sourced := []*ErrorObject{
+				{
+					Status: "400",
+					Title:  "is invalid",
+					Source: ErrorObjectSource{Parameter: "page[size]", Header: "X-Page"},
+					Links:  Links{"type": {Href: "https://example.com/problems/invalid-page"}},
+				},
+			}
+
+			problem := ProblemFromErrors(sourced)
+
+			Ω(problem).Should(Equal(Problem{
+				Type:      "https://example.com/problems/invalid-page",
+				Title:     "is invalid",
+				Status:    400,
+				Parameter: "page[size]",
+				Header:    "X-Page",
+			}))
+
+			Ω(problem.ErrorObjects()).Should(Equal(sourced))
+		})
+
+		It("skips nil error objects", func() {
+			Ω(ProblemFromErrors([]*ErrorObject{nil, errs[0]})).Should(Equal(ProblemFromErrors(errs)))
+
+			Ω(ProblemFromErrors([]*ErrorObject{nil})).Should(Equal(Problem{Title: "Bad Request", Status: 400}))
+		})
+	})
+
+	Describe("WriteErrors", func() {
+
+		It("writes JSON API document by default", func() {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/books", nil)
+
+			err := WriteErrors(w, r, errs)
+
+			expected := `
+        {
+          "errors": [
+            {
+              "status": "422",
+              "title": "is required",
+              "code": "is_required",
+              "source": {
+                "pointer": "/data/attributes/title"
+              }
+            }
+          ]
+        }
+      `
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(w.Code).Should(Equal(422))
+			Ω(w.Header().Get("Content-Type")).Should(Equal(ContentType))
+			Ω(w.Body.String()).Should(MatchJSON(expected))
+		})
+
+		It("writes problem document when it is accepted", func() {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/books", nil)
+			r.Header.Set("Accept", "application/problem+json, application/vnd.api+json")
+
+			err := WriteErrors(w, r, errs)
+
+			expected := `
+        {
+          "title": "is required",
+          "status": 422,
+          "code": "is_required",
+          "pointer": "/data/attributes/title"
+        }
+      `
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(w.Code).Should(Equal(422))
+			Ω(w.Header().Get("Content-Type")).Should(Equal(ProblemContentType))
+			Ω(w.Body.String()).Should(MatchJSON(expected))
+		})
+
+		It("picks the media type by quality values", func() {
+			accepts := map[string]string{
+				"application/problem+json;q=0.5, application/vnd.api+json":   ContentType,
+				"application/vnd.api+json;q=0.5, application/problem+json":   ProblemContentType,
+				"application/vnd.api+json, application/problem+json":         ContentType,
+				"application/problem+json;q=0, application/vnd.api+json;q=0": ContentType,
+			}
+
+			for accept, contentType := range accepts {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/books", nil)
+				r.Header.Set("Accept", accept)
+
+				Ω(WriteErrors(w, r, errs)).ShouldNot(HaveOccurred())
+				Ω(w.Header().Get("Content-Type")).Should(Equal(contentType), accept)
+			}
+		})
+	})
+
+	Describe("StatusForErrors", func() {
+
+		It("picks the most general status", func() {
+			cases := []struct {
+				errs   []*ErrorObject
+				status int
+			}{
+				{nil, http.StatusBadRequest},
+				{[]*ErrorObject{{Status: "404"}, {Status: "404"}, nil}, http.StatusNotFound},
+				{[]*ErrorObject{{Status: "409"}, {Title: "without status"}}, http.StatusConflict},
+				{[]*ErrorObject{{Status: "422"}, {Status: "404"}}, http.StatusBadRequest},
+				{[]*ErrorObject{{Status: "422"}, {Status: "503"}}, http.StatusInternalServerError},
+			}
+
+			for i, c := range cases {
+				Ω(StatusForErrors(c.errs)).Should(Equal(c.status), strconv.Itoa(i))
+			}
+		})
+	})
+
+	Describe("ErrorResponse", func() {
+
+		It("writes JSON API error document with status", func() {
+			w := httptest.NewRecorder()
+
+			err := ErrorResponse(w, NewError(http.StatusNotFound, "not_found", "Book not found", ""), NewError(http.StatusGone, "gone", "Author is gone", ""))
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(w.Code).Should(Equal(http.StatusBadRequest))
+			Ω(w.Header().Get("Content-Type")).Should(Equal(ContentType))
+			Ω(w.Body.String()).Should(MatchJSON(`{"errors": [
+				{"status": "404", "code": "not_found", "title": "Book not found", "source": {}},
+				{"status": "410", "code": "gone", "title": "Author is gone", "source": {}}
+			]}`))
+		})
+	})
+})
@@ -0,0 +1,227 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryParams describes JSON API query parameters https://jsonapi.org/format/#query-parameters
+type QueryParams struct {
+	// Include relationship paths requested by include parameter, e.g. "author" or "comments.author".
+	Include []string
+	// Fields requested sparse fieldsets by resource type, e.g. fields[books]=title,year.
+	Fields map[string][]string
+	// Sort requested sort fields in order of precedence.
+	Sort SortSpec
+	// Page pagination parameters by name, e.g. page[number]=2 gives "number": "2".
+	Page map[string]string
+	// Filter filtering parameters sorted by name.
+	Filter []FilterParam
+}
+
+// FilterParam describes a single filter parameter, e.g. filter[year][gte]=2000 gives Path ["year", "gte"] and Value "2000".
+type FilterParam struct {
+	Path  []string
+	Value string
+}
+
+// ParseQuery parses JSON API query parameters, the errors are returned as error objects with source parameter.
+//
+// ParseQuery example:
+//
+//	params, errs := jsonapi.ParseQuery(req.URL.Query())
+//	if len(errs) > 0 {
+//	  // respond with errs
+//	}
+//
+// Implementation specific parameters (containing characters besides a-z) are ignored,
+// any other unknown parameter is reported as an error.
+func ParseQuery(values url.Values) (*QueryParams, []*ErrorObject) {
+	var errs []*ErrorObject
+
+	params := &QueryParams{
+		Fields: map[string][]string{},
+		Page:   map[string]string{},
+	}
+
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		family, path, ok := parseQueryKey(key)
+		if !ok {
+			errs = append(errs, queryError(key, "Invalid query parameter", "Query parameter name is malformed."))
+			continue
+		}
+
+		vals := values[key]
+
+		switch family {
+		case "include":
+			if err := expectQueryPath(key, path, 0); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			include := []string{}
+
+			if joined := strings.Join(vals, ","); len(joined) > 0 {
+				list, err := parseQueryList(key, vals)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+
+				include = list
+			}
+
+			params.Include = include
+		case "fields":
+			if err := expectQueryPath(key, path, 1); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			fields := []string{}
+
+			if joined := strings.Join(vals, ","); len(joined) > 0 {
+				list, err := parseQueryList(key, vals)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+
+				fields = list
+			}
+
+			params.Fields[path[0]] = fields
+		case "sort":
+			if err := expectQueryPath(key, path, 0); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			spec, sortErrs := ParseSort(strings.Join(vals, ","))
+			if len(sortErrs) > 0 {
+				errs = append(errs, sortErrs...)
+				continue
+			}
+
+			params.Sort = spec
+		case "page":
+			if err := expectQueryPath(key, path, 1); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			if len(vals) > 1 {
+				errs = append(errs, queryError(key, "Invalid query parameter", "Query parameter must be given once."))
+				continue
+			}
+
+			params.Page[path[0]] = vals[0]
+		case "filter":
+			if len(vals) > 1 {
+				errs = append(errs, queryError(key, "Invalid query parameter", "Query parameter must be given once."))
+				continue
+			}
+
+			params.Filter = append(params.Filter, FilterParam{Path: path, Value: vals[0]})
+		default:
+			if isReservedQueryParam(family) {
+				errs = append(errs, queryError(key, "Unsupported query parameter", fmt.Sprintf("Query parameter %q is not supported.", key)))
+			}
+		}
+	}
+
+	return params, errs
+}
+
+func parseQueryKey(key string) (string, []string, bool) {
+	i := strings.Index(key, "[")
+	if i < 0 {
+		return key, nil, !strings.Contains(key, "]")
+	}
+
+	family, rest := key[:i], key[i:]
+
+	var path []string
+
+	for len(rest) > 0 {
+		if !strings.HasPrefix(rest, "[") {
+			return family, nil, false
+		}
+
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return family, nil, false
+		}
+
+		segment := rest[1:end]
+		if len(segment) == 0 || strings.Contains(segment, "[") {
+			return family, nil, false
+		}
+
+		path = append(path, segment)
+		rest = rest[end+1:]
+	}
+
+	return family, path, len(family) > 0
+}
+
+func parseQueryList(key string, vals []string) ([]string, *ErrorObject) {
+	var list []string
+
+	for _, val := range vals {
+		for _, item := range strings.Split(val, ",") {
+			if len(item) == 0 {
+				return nil, queryError(key, "Invalid query parameter", "Query parameter list must not contain empty values.")
+			}
+
+			list = append(list, item)
+		}
+	}
+
+	return list, nil
+}
+
+func expectQueryPath(key string, path []string, n int) *ErrorObject {
+	if len(path) == n {
+		return nil
+	}
+
+	return queryError(key, "Invalid query parameter", fmt.Sprintf("Query parameter %q is malformed.", key))
+}
+
+func isReservedQueryParam(family string) bool {
+	for _, c := range family {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func queryError(parameter, title, detail string) *ErrorObject {
+	return &ErrorObject{
+		Status: strconv.Itoa(http.StatusBadRequest),
+		Title:  title,
+		Detail: detail,
+		Code:   "invalid_query_parameter",
+		Source: ErrorObjectSource{Parameter: parameter},
+	}
+}
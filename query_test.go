@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("ParseQuery", func() {
+
+	It("parses query parameters", func() {
+		values, _ := url.ParseQuery("include=author,readers&fields[books]=title,year&fields[authors]=&sort=-year,title&page[number]=2&page[size]=10&filter[year][gte]=2000&filter[title]=Go&camelCase=ignored")
+
+		params, errs := ParseQuery(values)
+
+		Ω(errs).Should(BeEmpty())
+		Ω(params).Should(Equal(&QueryParams{
+			Include: []string{"author", "readers"},
+			Fields: map[string][]string{
+				"books":   {"title", "year"},
+				"authors": {},
+			},
+			Sort: []SortField{
+				{Field: "year", Descending: true},
+				{Field: "title"},
+			},
+			Page: map[string]string{
+				"number": "2",
+				"size":   "10",
+			},
+			Filter: []FilterParam{
+				{Path: []string{"title"}, Value: "Go"},
+				{Path: []string{"year", "gte"}, Value: "2000"},
+			},
+		}))
+	})
+
+	It("reports invalid query parameters as error objects", func() {
+		values, _ := url.ParseQuery("include=author,,readers&fields=title&sort=-&page[number]=1&page[number]=2&unknown=1")
+
+		_, errs := ParseQuery(values)
+
+		var parameters []string
+
+		for _, err := range errs {
+			Ω(err.Status).Should(Equal("400"))
+			parameters = append(parameters, err.Source.Parameter)
+		}
+
+		Ω(parameters).Should(Equal([]string{"fields", "include", "page[number]", "sort", "unknown"}))
+	})
+})
@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// ErrDocumentTooLarge returned when the document read exceeds Options.MaxSize.
+var ErrDocumentTooLarge = errors.New("jsonapi: document is too large")
+
+// UnmarshalReader deserializes JSON API document read from r into target like Unmarshal does,
+// e.g. jsonapi.UnmarshalReader(req.Body, &view, jsonapi.WithMaxSize(1 << 20)).
+// Only the document is read, anything following it is left in r.
+func UnmarshalReader(r io.Reader, target interface{}, opts ...Option) (*Document, error) {
+	options := newOptions(opts)
+
+	if options.MaxSize > 0 {
+		r = &limitedReader{r: r, n: options.MaxSize}
+	}
+
+	var data json.RawMessage
+
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return &Document{}, err
+	}
+
+	return UnmarshalWithOptions(data, target, options)
+}
+
+// limitedReader reads from r until n bytes are read, then it returns ErrDocumentTooLarge.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.n <= 0 {
+		return 0, ErrDocumentTooLarge
+	}
+
+	if int64(len(p)) > lr.n {
+		p = p[:lr.n]
+	}
+
+	n, err := lr.r.Read(p)
+	lr.n -= int64(n)
+
+	return n, err
+}
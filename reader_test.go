@@ -0,0 +1,47 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("UnmarshalReader", func() {
+
+	data := `{"data": {"type": "books", "id": "1", "attributes": {"title": "Go in Action", "year": "2015"}}}`
+
+	It("unmarshals document read from reader", func() {
+		view := BookView{}
+
+		doc, err := UnmarshalReader(strings.NewReader(data), &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(doc.Data).ShouldNot(BeNil())
+		Ω(view.Book).Should(Equal(Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"}))
+	})
+
+	It("unmarshals document fitting size limit", func() {
+		_, err := UnmarshalReader(strings.NewReader(data), &BookView{}, WithMaxSize(int64(len(data))))
+
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("returns error for document exceeding size limit", func() {
+		_, err := UnmarshalReader(strings.NewReader(data), &BookView{}, WithMaxSize(16))
+
+		Ω(errors.Is(err, ErrDocumentTooLarge)).Should(BeTrue())
+	})
+
+	It("returns error for malformed document", func() {
+		_, err := UnmarshalReader(strings.NewReader(`{"data":`), &BookView{})
+
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+)
+
+// ToOne returns to-one relationship linkage out of the relationships given to SetRelationships.
+// It returns false when there is no such relationship or it isn't to-one one, e.g. the payload is malformed.
+//
+// ToOne example:
+//
+//	func(s *SomeStruct) SetRelationships(relationships map[string]interface{}) error {
+//	  if author, ok := jsonapi.ToOne(relationships, "author"); ok {
+//	    s.AuthorID = author.ID
+//	  }
+//
+//	  return nil
+//	}
+func ToOne(relationships map[string]interface{}, name string) (*ResourceObjectIdentifier, bool) {
+	one, ok := relationships[name].(*ResourceObjectIdentifier)
+
+	return one, ok && one != nil
+}
+
+// ToMany returns to-many relationship linkage out of the relationships given to SetRelationships.
+// It returns false when there is no such relationship or it isn't to-many one, e.g. the payload is malformed.
+//
+// ToMany example:
+//
+//	func(s *SomeStruct) SetRelationships(relationships map[string]interface{}) error {
+//	  if readers, ok := jsonapi.ToMany(relationships, "readers"); ok {
+//	    for _, reader := range readers {
+//	      s.ReaderIDs = append(s.ReaderIDs, reader.ID)
+//	    }
+//	  }
+//
+//	  return nil
+//	}
+func ToMany(relationships map[string]interface{}, name string) ([]*ResourceObjectIdentifier, bool) {
+	many, ok := relationships[name].([]*ResourceObjectIdentifier)
+
+	return many, ok
+}
+
+// Relationship is relationship object given to UnmarshalTypedRelationships.
+type Relationship struct {
+	// One to-one relationship linkage, nil when the linkage is null or missing, or the relationship is to-many.
+	One *ResourceObjectIdentifier
+	// Many to-many relationship linkage, nil when the relationship is to-one or the linkage is missing.
+	Many []*ResourceObjectIdentifier
+	// Meta the relationship meta raw data.
+	Meta json.RawMessage
+	// Links the relationship links.
+	Links Links
+	// Present tells the relationship has linkage, i.e. data member, relationships having meta or links only don't.
+	Present bool
+	// Null tells the linkage is null, i.e. to-one relationship is being emptied.
+	Null bool
+	// Sideposted resources the linkage refers to, when they are sideposted: to-one relationship resource
+	// or to-many relationship []interface{}, having identifiers of the not sideposted ones, see Options.Sidepost.
+	Sideposted interface{}
+}
+
+// IsToMany tells the relationship linkage is to-many one.
+func (r Relationship) IsToMany() bool {
+	return r.Many != nil
+}
+
+func typedRelationships(ro *ResourceObject, opts *Options) map[string]Relationship {
+	relationships := make(map[string]Relationship, len(ro.Relationships))
+
+	for key, rel := range ro.Relationships {
+		if rel == nil {
+			continue
+		}
+
+		typed := Relationship{
+			Meta:    rel.Meta,
+			Links:   rel.Links,
+			Present: rel.Data != nil || rel.null,
+			Null:    rel.null,
+		}
+
+		if data := rel.Data; data != nil {
+			typed.One, typed.Many = data.One, data.Many
+
+			if data.One != nil {
+				if resolved, ok := opts.sideposted[sidepostKey(*data.One)]; ok {
+					typed.Sideposted = resolved
+				}
+			}
+
+			if data.Many != nil {
+				if sideposted := unmarshalSidepostedMany(data.Many, opts); sideposted != nil {
+					typed.Sideposted = sideposted
+				}
+			}
+		}
+
+		relationships[key] = typed
+	}
+
+	return relationships
+}
@@ -0,0 +1,295 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Relationship accessors", func() {
+
+	author := &ResourceObjectIdentifier{Type: "authors", ID: "1"}
+	readers := []*ResourceObjectIdentifier{{Type: "readers", ID: "2"}}
+
+	relationships := map[string]interface{}{
+		"author":  author,
+		"readers": readers,
+		"editor":  (*ResourceObjectIdentifier)(nil),
+	}
+
+	It("returns to-one linkage", func() {
+		one, ok := ToOne(relationships, "author")
+
+		Ω(ok).Should(BeTrue())
+		Ω(one).Should(Equal(author))
+	})
+
+	It("doesn't return missing or malformed to-one linkage", func() {
+		for _, name := range []string{"publisher", "readers", "editor"} {
+			one, ok := ToOne(relationships, name)
+
+			Ω(ok).Should(BeFalse(), name)
+			Ω(one).Should(BeNil(), name)
+		}
+	})
+
+	It("returns to-many linkage", func() {
+		many, ok := ToMany(relationships, "readers")
+
+		Ω(ok).Should(BeTrue())
+		Ω(many).Should(Equal(readers))
+	})
+
+	It("doesn't return missing or malformed to-many linkage", func() {
+		for _, name := range []string{"publisher", "author"} {
+			many, ok := ToMany(relationships, name)
+
+			Ω(ok).Should(BeFalse(), name)
+			Ω(many).Should(BeNil(), name)
+		}
+	})
+})
+
+type Story struct {
+	ID            string `json:"-"`
+	Title         string `json:"title"`
+	Relationships map[string]Relationship
+}
+
+func (s *Story) SetID(id string) error {
+	s.ID = id
+	return nil
+}
+
+func (s *Story) SetType(string) error {
+	return nil
+}
+
+func (s *Story) SetRelationships(relationships map[string]Relationship) error {
+	s.Relationships = relationships
+	return nil
+}
+
+type StoryView struct {
+	Story Story
+}
+
+func (v *StoryView) SetData(to func(target interface{}) error) error {
+	return to(&v.Story)
+}
+
+var _ = Describe("Typed relationships", func() {
+
+	It("are given to SetRelationships", func() {
+		payload := []byte(`{
+			"data": {
+				"type": "stories",
+				"id": "1",
+				"attributes": {"title": "Hello"},
+				"relationships": {
+					"author": {"data": {"type": "people", "id": "9"}, "links": {"related": "/stories/1/author"}},
+					"editor": {"data": null},
+					"tags": {"data": [], "meta": {"count": 0}},
+					"comments": {"links": {"related": "/stories/1/comments"}}
+				}
+			}
+		}`)
+
+		view := StoryView{}
+		_, err := Unmarshal(payload, &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		relationships := view.Story.Relationships
+		Ω(relationships).Should(HaveLen(4))
+
+		author := relationships["author"]
+		Ω(author.Present).Should(BeTrue())
+		Ω(author.Null).Should(BeFalse())
+		Ω(author.IsToMany()).Should(BeFalse())
+		Ω(author.One).Should(Equal(&ResourceObjectIdentifier{Type: "people", ID: "9"}))
+		Ω(author.Links["related"].Href).Should(Equal("/stories/1/author"))
+
+		editor := relationships["editor"]
+		Ω(editor.Present).Should(BeTrue())
+		Ω(editor.Null).Should(BeTrue())
+		Ω(editor.One).Should(BeNil())
+
+		tags := relationships["tags"]
+		Ω(tags.Present).Should(BeTrue())
+		Ω(tags.IsToMany()).Should(BeTrue())
+		Ω(tags.Many).Should(BeEmpty())
+		Ω(tags.Meta).Should(MatchJSON(`{"count": 0}`))
+
+		comments := relationships["comments"]
+		Ω(comments.Present).Should(BeFalse())
+		Ω(comments.Null).Should(BeFalse())
+		Ω(comments.Links).Should(HaveKey("related"))
+	})
+})
+
+type Manuscript struct {
+	ID      string   `json:"-"`
+	Author  *Author  `json:"-"`
+	Readers *Readers `json:"-"`
+}
+
+func (m Manuscript) GetID() string {
+	return m.ID
+}
+
+func (m Manuscript) GetType() string {
+	return "manuscripts"
+}
+
+func (m Manuscript) GetRelationships() map[string]interface{} {
+	return map[string]interface{}{
+		"author":  m.Author,
+		"readers": m.Readers,
+		"editor":  nil,
+	}
+}
+
+type ManuscriptView struct {
+	Manuscript Manuscript
+}
+
+func (v ManuscriptView) GetData() interface{} {
+	return v.Manuscript
+}
+
+var _ = Describe("Pointer relationships", func() {
+
+	It("marshals nil pointers as null", func() {
+		result, err := Marshal(ManuscriptView{Manuscript: Manuscript{ID: "1"}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "manuscripts", "id": "1", "relationships": {
+			"author": {"data": null},
+			"readers": {"data": null},
+			"editor": {"data": null}
+		}}}`))
+	})
+
+	It("marshals pointers as the values they point to", func() {
+		readers := Readers{{ID: "2"}}
+
+		result, err := Marshal(ManuscriptView{Manuscript: Manuscript{ID: "1", Author: &Author{ID: "1"}, Readers: &readers}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "manuscripts", "id": "1", "relationships": {
+			"author": {"data": {"type": "authors", "id": "1"}},
+			"readers": {"data": [{"type": "people", "id": "2"}]},
+			"editor": {"data": null}
+		}}}`))
+	})
+})
+
+type Memo struct {
+	ID            string                 `json:"-"`
+	Relationships map[string]interface{} `json:"-"`
+}
+
+func (m Memo) GetID() string {
+	return m.ID
+}
+
+func (m Memo) GetType() string {
+	return "memos"
+}
+
+func (m Memo) GetRelationships() map[string]interface{} {
+	return m.Relationships
+}
+
+type MemoView struct {
+	Memo Memo
+}
+
+func (v MemoView) GetData() interface{} {
+	return v.Memo
+}
+
+var _ = Describe("Identifier relationships", func() {
+
+	It("marshals resource identifiers given directly", func() {
+		view := MemoView{Memo: Memo{ID: "1", Relationships: map[string]interface{}{
+			"author":    ResourceObjectIdentifier{Type: "people", ID: "1"},
+			"reviewer":  &ResourceObjectIdentifier{Type: "people", ID: "2", Missing: true},
+			"approver":  (*ResourceObjectIdentifier)(nil),
+			"readers":   []ResourceObjectIdentifier{{Type: "people", ID: "3"}},
+			"watchers":  []*ResourceObjectIdentifier{{Type: "people", ID: "4"}},
+			"followers": []*ResourceObjectIdentifier{},
+		}}}
+
+		result, err := Marshal(view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "memos", "id": "1", "relationships": {
+			"author": {"data": {"type": "people", "id": "1"}},
+			"reviewer": {"data": {"type": "people", "id": "2", "meta": {"exists": false}}},
+			"approver": {"data": null},
+			"readers": {"data": [{"type": "people", "id": "3"}]},
+			"watchers": {"data": [{"type": "people", "id": "4"}]},
+			"followers": {"data": []}
+		}}}`))
+	})
+
+	It("rejects identifier without ID when empty ID is rejected", func() {
+		view := MemoView{Memo: Memo{ID: "1", Relationships: map[string]interface{}{
+			"author": ResourceObjectIdentifier{Type: "people"},
+		}}}
+
+		_, err := MarshalWithOptions(view, Options{EmptyID: EmptyIDError})
+
+		Ω(err).Should(MatchError(ErrEmptyID))
+	})
+})
+
+var _ = Describe("Relationships order", func() {
+
+	It("marshals relationships in sorted keys order", func() {
+		view := MemoView{Memo: Memo{ID: "1", Relationships: map[string]interface{}{
+			"watchers": []ResourceObjectIdentifier{{Type: "people", ID: "3"}},
+			"author":   ResourceObjectIdentifier{Type: "people", ID: "1"},
+			"reviewer": ResourceObjectIdentifier{Type: "people", ID: "2"},
+			"approver": nil,
+		}}}
+
+		expected := `{"data":{"type":"memos","id":"1","relationships":{` +
+			`"approver":{"data":null},` +
+			`"author":{"data":{"type":"people","id":"1"}},` +
+			`"reviewer":{"data":{"type":"people","id":"2"}},` +
+			`"watchers":{"data":[{"type":"people","id":"3"}]}}}}` + "\n"
+
+		for i := 0; i < 20; i++ {
+			result, err := Marshal(view)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(result)).Should(Equal(expected))
+		}
+	})
+
+	It("reports the error of the first failing relationship in sorted keys order", func() {
+		view := MemoView{Memo: Memo{ID: "1", Relationships: map[string]interface{}{
+			"watchers": []*ResourceObjectIdentifier{nil},
+			"readers":  []*ResourceObjectIdentifier{nil},
+			"author":   ResourceObjectIdentifier{Type: "people", ID: "1"},
+		}}}
+
+		for i := 0; i < 20; i++ {
+			_, err := Marshal(view)
+
+			var marshalErr *MarshalError
+
+			Ω(errors.As(err, &marshalErr)).Should(BeTrue())
+			Ω(marshalErr.Member).Should(Equal("/relationships/readers"))
+		}
+	})
+})
@@ -0,0 +1,336 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Replay field trace statuses.
+const (
+	// ReplaySet the payload member was decoded into the Go field.
+	ReplaySet = "set"
+	// ReplayIgnored the payload member has no matching Go field or method and was dropped.
+	ReplayIgnored = "ignored"
+	// ReplayMissing the Go field has no matching payload member and was left unchanged.
+	ReplayMissing = "missing"
+	// ReplayPassed the relationship was passed to SetRelationships, or the attribute to the target
+	// unmarshaling attributes itself, e.g. by SetAttributes.
+	ReplayPassed = "passed"
+)
+
+var replayTypes = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+}{
+	byName: map[string]reflect.Type{},
+}
+
+// RegisterType registers Go type of sample under the name, so documents can be replayed by type name, see Replay.
+// Sample is usually a view, e.g. RegisterType("BookView", BookView{}).
+func RegisterType(name string, sample interface{}) {
+	typ := reflect.TypeOf(sample)
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	replayTypes.Lock()
+	defer replayTypes.Unlock()
+
+	replayTypes.byName[name] = typ
+}
+
+// ReplayReport describes how Unmarshal populated the target from the document.
+type ReplayReport struct {
+	// Document the unmarshaled document.
+	Document *Document
+	// Target the populated target.
+	Target interface{}
+	// Err the error Unmarshal returned.
+	Err error
+	// Notes general remarks, e.g. interfaces the target misses.
+	Notes []string
+	// Resources field-by-field traces of primary data resources.
+	Resources []ResourceTrace
+}
+
+// ResourceTrace describes how a single resource object was unmarshaled.
+type ResourceTrace struct {
+	// Pointer JSON pointer of the resource object, e.g. "/data" or "/data/1".
+	Pointer string
+	Type    string
+	ID      string
+	// GoType the Go type the resource object was unmarshaled into.
+	GoType string
+	Fields []FieldTrace
+}
+
+// FieldTrace describes what happened to a single payload member or Go field.
+type FieldTrace struct {
+	// Member payload member, e.g. "attributes/title" or "relationships/author".
+	Member string
+	// Field Go field name.
+	Field string
+	// Status one of ReplaySet, ReplayIgnored, ReplayMissing and ReplayPassed.
+	Status string
+	// Value resulting Go field value.
+	Value interface{}
+}
+
+// Replay unmarshals captured document into a new value of the type registered by RegisterType
+// and reports field-by-field how the value was populated, see ReplayInto.
+func Replay(data []byte, typeName string, opts ...Option) (*ReplayReport, error) {
+	replayTypes.RLock()
+	typ, ok := replayTypes.byName[typeName]
+	replayTypes.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("jsonapi: type %q is not registered", typeName)
+	}
+
+	return ReplayInto(data, reflect.New(typ).Interface(), opts...)
+}
+
+// ReplayInto unmarshals captured document into target exactly like Unmarshal does with the options,
+// e.g. the ones the document was decoded with, and reports field-by-field how the target was populated,
+// to debug why a field stays empty.
+func ReplayInto(data []byte, target interface{}, opts ...Option) (*ReplayReport, error) {
+	report := &ReplayReport{Target: target}
+
+	if _, ok := target.(UnmarshalData); !ok {
+		report.Notes = append(report.Notes, fmt.Sprintf("%T does not implement UnmarshalData, primary data is not unmarshaled", target))
+	}
+
+	options := newOptions(opts)
+
+	trace := &replayTrace{}
+	options.trace = trace
+
+	doc, err := UnmarshalWithOptions(data, target, options)
+
+	report.Document = doc
+	report.Err = err
+
+	if doc == nil || doc.Data == nil {
+		report.Notes = append(report.Notes, "document has no primary data")
+		return report, nil
+	}
+
+	if len(trace.resources) == 0 && len(report.Notes) == 0 {
+		report.Notes = append(report.Notes, "SetData did not call the given func, primary data is not unmarshaled")
+	}
+
+	for _, traced := range trace.resources {
+		rt, err := traceResource(traced.pointer, traced.ro, traced.ui)
+		if err != nil {
+			return report, err
+		}
+
+		report.Resources = append(report.Resources, rt)
+	}
+
+	return report, nil
+}
+
+// replayTrace collects primary resources the way Unmarshal unmarshals them, see Options.trace.
+type replayTrace struct {
+	resources []tracedResource
+}
+
+type tracedResource struct {
+	pointer string
+	ro      *ResourceObject
+	ui      UnmarshalResourceIdentifier
+}
+
+// add traces the resource object unmarshaled into ui, its members are named back already.
+func (t *replayTrace) add(ro *ResourceObject, ui UnmarshalResourceIdentifier, pointer string) {
+	if isPrimaryPointer(pointer) {
+		t.resources = append(t.resources, tracedResource{pointer: pointer, ro: ro, ui: ui})
+	}
+}
+
+// traceResource describes how the resource object was unmarshaled into ui, after Unmarshal has finished.
+// Attributes of targets unmarshaling them themselves, e.g. by SetAttributes, are reported as passed.
+func traceResource(pointer string, ro *ResourceObject, ui UnmarshalResourceIdentifier) (ResourceTrace, error) {
+	resource := unwrapResource(ui)
+
+	trace := ResourceTrace{
+		Pointer: pointer,
+		Type:    ro.Type,
+		ID:      ro.ID,
+		GoType:  reflect.TypeOf(resource).String(),
+	}
+
+	var fields map[string]*jsonField
+
+	structAttributes := hasStructAttributes(ui)
+	if structAttributes {
+		fields = jsonFields(resourceType(ui))
+	}
+
+	members := map[string]bool{}
+
+	if len(ro.Attributes) > 0 {
+		attributes := map[string]json.RawMessage{}
+
+		if err := json.Unmarshal(ro.Attributes, &attributes); err != nil {
+			return trace, err
+		}
+
+		for name := range attributes {
+			members[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(members)+len(fields))
+
+	for name := range members {
+		names = append(names, name)
+	}
+
+	for name := range fields {
+		if !members[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	value := reflect.Indirect(reflect.ValueOf(resource))
+
+	for _, name := range names {
+		ft := FieldTrace{Member: "attributes/" + name}
+
+		field, ok := fields[name]
+
+		switch {
+		case !structAttributes:
+			ft.Status = ReplayPassed
+		case !ok:
+			ft.Status = ReplayIgnored
+		case members[name]:
+			ft.Status = ReplaySet
+		default:
+			ft.Status = ReplayMissing
+		}
+
+		if ok {
+			ft.Field = field.field.Name
+
+			if fv, ok := fieldByIndex(value, field.index); ok && fv.CanInterface() {
+				ft.Value = fv.Interface()
+			}
+		}
+
+		trace.Fields = append(trace.Fields, ft)
+	}
+
+	_, setsRelationships := ui.(UnmarshalRelationships)
+	if _, ok := ui.(UnmarshalTypedRelationships); ok {
+		setsRelationships = true
+	}
+
+	keys := make([]string, 0, len(ro.Relationships))
+
+	for key := range ro.Relationships {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		ft := FieldTrace{Member: "relationships/" + key, Status: ReplayIgnored}
+
+		if setsRelationships {
+			ft.Status = ReplayPassed
+		}
+
+		trace.Fields = append(trace.Fields, ft)
+	}
+
+	return trace, nil
+}
+
+// String returns human-readable report.
+func (r *ReplayReport) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "target: %T\n", r.Target)
+
+	if r.Err != nil {
+		fmt.Fprintf(&b, "error: %v\n", r.Err)
+	}
+
+	for _, note := range r.Notes {
+		fmt.Fprintf(&b, "note: %s\n", note)
+	}
+
+	for _, resource := range r.Resources {
+		fmt.Fprintf(&b, "%s (type %q, id %q) -> %s\n", resource.Pointer, resource.Type, resource.ID, resource.GoType)
+
+		for _, field := range resource.Fields {
+			switch {
+			case len(field.Field) > 0:
+				fmt.Fprintf(&b, "  %-30s %-8s %s = %#v\n", field.Member, field.Status, field.Field, field.Value)
+			default:
+				fmt.Fprintf(&b, "  %-30s %s\n", field.Member, field.Status)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// ReplayCommand implements "replay" subcommand which applications can wire into their own binaries,
+// since only they have their Go types registered. Arguments are the registered type name and optional document file,
+// the document is read from stdin when the file is not given.
+//
+// ReplayCommand example:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "replay" {
+//	  if err := jsonapi.ReplayCommand(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+//	    log.Fatal(err)
+//	  }
+//	  return
+//	}
+func ReplayCommand(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("usage: replay <type-name> [document-file]")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if len(args) == 2 {
+		data, err = ioutil.ReadFile(args[1])
+	} else {
+		data, err = ioutil.ReadAll(stdin)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	report, err := Replay(data, args[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(stdout, report.String())
+
+	return err
+}
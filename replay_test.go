@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Replay", func() {
+
+	payload := []byte(`
+    {
+      "data": {
+        "type": "books",
+        "id": "1",
+        "attributes": {
+          "title": "An Introduction to Programming in Go",
+          "published": "2012"
+        },
+        "relationships": {
+          "author": {
+            "data": { "type": "authors", "id": "1" }
+          }
+        }
+      }
+    }
+  `)
+
+	BeforeEach(func() {
+		RegisterType("BookWithAuthorView", BookWithAuthorView{})
+	})
+
+	It("reports how resource fields were populated", func() {
+		report, err := Replay(payload, "BookWithAuthorView")
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Err).ShouldNot(HaveOccurred())
+		Ω(report.Resources).Should(HaveLen(1))
+
+		resource := report.Resources[0]
+
+		Ω(resource.Pointer).Should(Equal("/data"))
+		Ω(resource.GoType).Should(Equal("*jsonapi_test.BookWithAuthor"))
+		Ω(resource.Fields).Should(Equal([]FieldTrace{
+			{Member: "attributes/published", Status: ReplayIgnored},
+			{Member: "attributes/title", Field: "Title", Status: ReplaySet, Value: "An Introduction to Programming in Go"},
+			{Member: "attributes/year", Field: "Year", Status: ReplayMissing, Value: ""},
+			{Member: "relationships/author", Status: ReplayPassed},
+		}))
+	})
+
+	It("notes targets which can't receive primary data", func() {
+		report, err := ReplayInto(payload, &Book{})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Notes).Should(HaveLen(1))
+		Ω(report.Resources).Should(BeEmpty())
+	})
+
+	It("replays with the options the document was decoded with", func() {
+		var stadium Stadium
+
+		report, err := ReplayInto([]byte(`{"data": {"type": "stadiums", "id": "1", "attributes": {"name": "Arena", "seatCount": 100}}}`), &stadium, WithNaming(CamelCase))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Err).ShouldNot(HaveOccurred())
+		Ω(report.Resources).Should(HaveLen(1))
+		Ω(report.Resources[0].Fields).Should(ContainElement(FieldTrace{Member: "attributes/seat_count", Field: "SeatCount", Status: ReplaySet, Value: 100}))
+		Ω(stadium.SeatCount).Should(Equal(100))
+	})
+
+	It("reports attributes passed to targets unmarshaling them themselves", func() {
+		report, err := ReplayInto([]byte(`{"data": {"type": "records", "id": "1", "attributes": {"anything": 1}}}`), &RecordView{})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Resources).Should(HaveLen(1))
+		Ω(report.Resources[0].Fields).Should(Equal([]FieldTrace{{Member: "attributes/anything", Status: ReplayPassed}}))
+	})
+
+	It("unmarshals errors into the target", func() {
+		view := &ErrorsView{}
+
+		report, err := ReplayInto([]byte(`{"errors": [{"status": "422", "title": "is invalid"}]}`), view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Document.Errors).Should(HaveLen(1))
+		Ω(view.ValidationErrors).Should(HaveLen(1))
+	})
+
+	It("returns error for not registered type", func() {
+		_, err := Replay(payload, "Unknown")
+
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("runs replay subcommand", func() {
+		out := &bytes.Buffer{}
+
+		err := ReplayCommand([]string{"BookWithAuthorView"}, bytes.NewReader(payload), out)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(strings.Contains(out.String(), "attributes/published")).Should(BeTrue())
+	})
+})
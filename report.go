@@ -0,0 +1,236 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalReport describes what the unmarshaled document actually contained.
+type UnmarshalReport struct {
+	// Resources reports of the primary resources unmarshaled into the target, in document order.
+	Resources []ResourceReport
+	// Unknown JSON pointers of the document, resource object and attribute members the target doesn't know,
+	// e.g. "/data/attributes/isbn". Extension members, i.e. ones containing colon, are not reported.
+	Unknown []string
+}
+
+// ResourceReport describes the members of an unmarshaled resource object.
+type ResourceReport struct {
+	ResourceObjectIdentifier
+	// Pointer JSON pointer of the resource object, e.g. "/data" or "/data/1".
+	Pointer string
+	// Attributes present attributes, null ones included.
+	Attributes FieldSet
+	// Relationships present relationships.
+	Relationships FieldSet
+	// Defaulted attributes absent from the creation document which got their default values, see Options.Create.
+	// They are not in Attributes, which reports the document only.
+	Defaulted FieldSet
+	// Unknown names of the attributes the target struct has no fields for,
+	// targets unmarshaling attributes themselves know them all.
+	Unknown []string
+}
+
+var (
+	documentMembers = map[string]bool{
+		"data": true, "errors": true, "included": true, "meta": true, "links": true, "jsonapi": true,
+	}
+	resourceObjectMembers = map[string]bool{
+		"type": true, "id": true, "lid": true, "attributes": true, "relationships": true, "links": true, "meta": true,
+	}
+)
+
+// UnmarshalWithReport deserializes JSON API document into target like Unmarshal does and reports
+// present and unknown members of the document.
+//
+// UnmarshalWithReport example:
+//
+//	doc, report, err := jsonapi.UnmarshalWithReport(data, &view)
+//	...
+//	if len(report.Unknown) > 0 {
+//	  // respond with 400 Bad Request
+//	}
+func UnmarshalWithReport(data []byte, target interface{}, opts ...Option) (*Document, *UnmarshalReport, error) {
+	options := newOptions(opts)
+
+	report := &UnmarshalReport{}
+	options.report = report
+
+	doc, err := UnmarshalWithOptions(data, target, options)
+	if err != nil {
+		return doc, report, err
+	}
+
+	var members map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &members); err != nil {
+		return doc, report, err
+	}
+
+	unknown := unknownMembers("", members, documentMembers)
+	primary := primaryResourceObjects(members["data"])
+
+	for i := range report.Resources {
+		rr := &report.Resources[i]
+
+		if ro, ok := primary[rr.Pointer]; ok {
+			unknown = append(unknown, unknownMembers(rr.Pointer, ro, resourceObjectMembers)...)
+		}
+
+		for _, name := range rr.Unknown {
+			unknown = append(unknown, rr.Pointer+Pointer("attributes", name))
+		}
+	}
+
+	report.Unknown = unknown
+
+	return doc, report, nil
+}
+
+// add reports the resource object unmarshaled into ui.
+func (r *UnmarshalReport) add(ro *ResourceObject, ui UnmarshalResourceIdentifier, pointer string) error {
+	rr := ResourceReport{
+		ResourceObjectIdentifier: ro.ResourceObjectIdentifier,
+		Pointer:                  pointer,
+		Attributes:               FieldSet{},
+		Relationships:            FieldSet{},
+		Defaulted:                FieldSet{},
+	}
+
+	var attributes map[string]json.RawMessage
+
+	if len(ro.Attributes) > 0 {
+		if err := json.Unmarshal(ro.Attributes, &attributes); err != nil {
+			return err
+		}
+	}
+
+	for name := range attributes {
+		rr.Attributes[name] = true
+	}
+
+	for name := range ro.Relationships {
+		rr.Relationships[name] = true
+	}
+
+	rr.Unknown = unknownAttributes(rr.Attributes.Names(), ui)
+
+	r.Resources = append(r.Resources, rr)
+
+	return nil
+}
+
+// defaulted reports the attributes of the resource object at pointer which got their default values.
+func (r *UnmarshalReport) defaulted(pointer string, names []string) {
+	for i := range r.Resources {
+		if r.Resources[i].Pointer != pointer {
+			continue
+		}
+
+		for _, name := range names {
+			r.Resources[i].Defaulted[name] = true
+		}
+	}
+}
+
+// unknownAttributes returns the attribute names ui struct has no fields for,
+// ones unmarshaling attributes themselves know them all.
+func unknownAttributes(names []string, ui UnmarshalResourceIdentifier) []string {
+	if !hasStructAttributes(ui) {
+		return nil
+	}
+
+	fields := jsonFields(resourceType(ui))
+
+	var unknown []string
+
+	for _, name := range names {
+		if _, ok := fields[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	return unknown
+}
+
+func unknownMembers(pointer string, members map[string]json.RawMessage, known map[string]bool) []string {
+	var unknown []string
+
+	for name := range members {
+		if !known[name] && !strings.Contains(name, ":") {
+			unknown = append(unknown, pointer+Pointer(name))
+		}
+	}
+
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// primaryResourceObjects returns members of primary data resource objects by their pointers, "/data" or "/data/<index>".
+func primaryResourceObjects(data json.RawMessage) map[string]map[string]json.RawMessage {
+	objects := map[string]map[string]json.RawMessage{}
+
+	if jsonDelim(data) != '[' {
+		var members map[string]json.RawMessage
+
+		if json.Unmarshal(data, &members) == nil {
+			objects["/data"] = members
+		}
+
+		return objects
+	}
+
+	var many []map[string]json.RawMessage
+
+	if json.Unmarshal(data, &many) == nil {
+		for i, members := range many {
+			objects[CollectionPointer(i)] = members
+		}
+	}
+
+	return objects
+}
+
+// validateUnknownAttributes returns 400 error objects of the attributes ui struct has no fields for, see Options.DisallowUnknownFields.
+func validateUnknownAttributes(attributes json.RawMessage, ui UnmarshalResourceIdentifier, pointer string) ([]*ErrorObject, error) {
+	if len(attributes) == 0 || !hasStructAttributes(ui) {
+		return nil, nil
+	}
+
+	var members map[string]json.RawMessage
+
+	if err := json.Unmarshal(attributes, &members); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(members))
+
+	for name := range members {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var errs []*ErrorObject
+
+	for _, name := range unknownAttributes(names, ui) {
+		errs = append(errs, &ErrorObject{
+			Status: strconv.Itoa(http.StatusBadRequest),
+			Title:  "Unknown attribute",
+			Detail: fmt.Sprintf("Attribute %q is not supported.", name),
+			Code:   "unknown_attribute",
+			Source: ErrorObjectSource{Pointer: pointer + Pointer(name)},
+		})
+	}
+
+	return errs, nil
+}
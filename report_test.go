@@ -0,0 +1,137 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("UnmarshalWithReport", func() {
+	It("reports present attributes and relationships and unknown members", func() {
+		var chapter Chapter
+
+		doc, report, err := UnmarshalWithReport([]byte(`
+			{
+				"data": {
+					"type": "chapters",
+					"id": "1",
+					"attributes": {"title": "One", "summary": null, "pages": 12},
+					"relationships": {"book": {"data": {"type": "books", "id": "1"}}},
+					"version": 2
+				},
+				"extra": true,
+				"ext:member": true
+			}
+		`), &chapter)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(doc.Data).ShouldNot(BeNil())
+		Ω(chapter.Title).Should(Equal("One"))
+
+		Ω(report.Resources).Should(HaveLen(1))
+
+		rr := report.Resources[0]
+		Ω(rr.ResourceObjectIdentifier).Should(Equal(ResourceObjectIdentifier{Type: "chapters", ID: "1"}))
+		Ω(rr.Pointer).Should(Equal("/data"))
+		Ω(rr.Attributes.Names()).Should(Equal([]string{"pages", "summary", "title"}))
+		Ω(rr.Relationships.Names()).Should(Equal([]string{"book"}))
+		Ω(rr.Unknown).Should(Equal([]string{"pages"}))
+
+		Ω(report.Unknown).Should(Equal([]string{"/extra", "/data/version", "/data/attributes/pages"}))
+	})
+
+	It("reports the members named back by Naming", func() {
+		var stadium Stadium
+
+		_, report, err := UnmarshalWithReport([]byte(`
+			{
+				"data": {
+					"type": "stadiums",
+					"id": "1",
+					"attributes": {"name": "Arena", "seatCount": 100, "roofType": "open"},
+					"relationships": {"homeTeam": {"data": {"type": "teams", "id": "2"}}}
+				}
+			}
+		`), &stadium, WithNaming(CamelCase))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(stadium.SeatCount).Should(Equal(100))
+
+		rr := report.Resources[0]
+		Ω(rr.Attributes.Names()).Should(Equal([]string{"name", "roofType", "seat_count"}))
+		Ω(rr.Relationships.Names()).Should(Equal([]string{"home_team"}))
+		Ω(rr.Unknown).Should(Equal([]string{"roofType"}))
+
+		Ω(report.Unknown).Should(Equal([]string{"/data/attributes/roofType"}))
+	})
+
+	It("reports each collection resource", func() {
+		var chapters Chapters
+
+		_, report, err := UnmarshalWithReport([]byte(`
+			{
+				"data": [
+					{"type": "chapters", "id": "1", "attributes": {"title": "One"}},
+					{"type": "chapters", "id": "2", "attributes": {"heading": "Two"}}
+				]
+			}
+		`), &chapters)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(report.Resources).Should(HaveLen(2))
+		Ω(report.Resources[0].Pointer).Should(Equal("/data/0"))
+		Ω(report.Resources[0].Unknown).Should(BeEmpty())
+		Ω(report.Resources[1].Pointer).Should(Equal("/data/1"))
+		Ω(report.Unknown).Should(Equal([]string{"/data/1/attributes/heading"}))
+	})
+
+	It("knows all attributes of targets unmarshaling attributes themselves", func() {
+		_, report, err := UnmarshalWithReport([]byte(`{"data": {"type": "records", "id": "1", "attributes": {"anything": 1}}}`), &RecordView{})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(report.Resources).Should(HaveLen(1))
+		Ω(report.Unknown).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("DisallowUnknownFields", func() {
+	It("rejects attributes the target has no fields for", func() {
+		_, err := Unmarshal([]byte(`{"data": {"type": "chapters", "id": "1", "attributes": {"title": "One", "titel": "One", "Summary": "x"}}}`), &Chapter{}, WithDisallowUnknownFields())
+
+		var validationErr *ValidationError
+
+		Ω(errors.As(err, &validationErr)).Should(BeTrue())
+		Ω(validationErr.Errors).Should(HaveLen(2))
+		Ω(validationErr.Errors[0].Status).Should(Equal("400"))
+		Ω(validationErr.Errors[0].Code).Should(Equal("unknown_attribute"))
+		Ω(validationErr.Errors[0].Source.Pointer).Should(Equal("/data/attributes/Summary"))
+		Ω(validationErr.Errors[1].Source.Pointer).Should(Equal("/data/attributes/titel"))
+	})
+
+	It("points to the collection resource", func() {
+		_, err := Unmarshal([]byte(`{"data": [{"type": "chapters", "id": "1"}, {"type": "chapters", "id": "2", "attributes": {"pages": 1}}]}`), &Chapters{}, WithDisallowUnknownFields())
+
+		var validationErr *ValidationError
+
+		Ω(errors.As(err, &validationErr)).Should(BeTrue())
+		Ω(validationErr.Errors[0].Source.Pointer).Should(Equal("/data/1/attributes/pages"))
+	})
+
+	It("accepts any attributes of targets unmarshaling attributes themselves", func() {
+		_, err := Unmarshal([]byte(`{"data": {"type": "records", "id": "1", "attributes": {"anything": 1}}}`), &RecordView{}, WithDisallowUnknownFields())
+
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("accepts unknown attributes by default", func() {
+		_, err := Unmarshal([]byte(`{"data": {"type": "chapters", "id": "1", "attributes": {"titel": "One"}}}`), &Chapter{})
+
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+)
+
+// RequestIDMeta is the error object meta member containing correlation/request ID.
+const RequestIDMeta = "request_id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying correlation/request ID, which HTTP helpers stamp into emitted error objects.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns correlation/request ID attached to ctx by WithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+
+	return id, ok && len(id) > 0
+}
+
+func stampRequestID(errs []*ErrorObject, id string) []*ErrorObject {
+	if len(id) == 0 || len(errs) == 0 {
+		return errs
+	}
+
+	stamped := make([]*ErrorObject, 0, len(errs))
+
+	for _, err := range errs {
+		if err == nil {
+			stamped = append(stamped, err)
+			continue
+		}
+
+		copied := *err
+		copied.Meta = make(map[string]interface{}, len(err.Meta)+1)
+
+		for k, v := range err.Meta {
+			copied.Meta[k] = v
+		}
+
+		copied.Meta[RequestIDMeta] = id
+
+		stamped = append(stamped, &copied)
+	}
+
+	return stamped
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Request ID", func() {
+
+	It("stamps marshaled error objects with request ID", func() {
+		view := ErrorsView{
+			ValidationErrors: []*ErrorObject{
+				{
+					Status: "400",
+					Title:  "is not supported",
+					Source: ErrorObjectSource{
+						Header: "X-Locale",
+					},
+				},
+			},
+		}
+
+		result, err := MarshalWithOptions(view, Options{RequestID: "f3b4c1"})
+
+		expected := `
+      {
+        "errors": [
+          {
+            "status": "400",
+            "title": "is not supported",
+            "source": {
+              "header": "X-Locale"
+            },
+            "meta": {
+              "request_id": "f3b4c1"
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.ValidationErrors[0].Meta).Should(BeNil())
+	})
+
+	It("stamps written error objects with request ID from context", func() {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/books", nil)
+		r = r.WithContext(WithRequestID(r.Context(), "f3b4c1"))
+
+		err := WriteErrors(w, r, []*ErrorObject{{Status: "404", Title: "not found"}})
+
+		expected := `
+      {
+        "errors": [
+          {
+            "status": "404",
+            "title": "not found",
+            "source": {},
+            "meta": {
+              "request_id": "f3b4c1"
+            }
+          }
+        ]
+      }
+    `
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(w.Body.String()).Should(MatchJSON(expected))
+	})
+})
@@ -0,0 +1,274 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PartialMeta is the document meta member listing included resources which failed to resolve, see IncludePartial.
+const PartialMeta = "partial"
+
+// IncludeResolver loads resources of a resource type by their IDs to be marshaled as included,
+// the resources must be usable as included, e.g. implement MarshalResourceIdentifier.
+//
+// IncludeResolver example:
+//
+//	payload, err := jsonapi.Marshal(view, jsonapi.WithResolver("authors", func(ctx context.Context, ids []string) ([]interface{}, error) {
+//	  authors, err := store.Authors(ctx, ids)
+//	  ...
+//	}))
+//
+// The resolver is called with the context of Options.Context, it is cancelled after Options.ResolveTimeout when it is set.
+// Marshal doesn't wait for the resolver beyond the timeout even if the resolver ignores the context,
+// the resources are skipped and listed in document meta as timed out, e.g.
+// "meta": {"partial": [{"type": "authors", "detail": "context deadline exceeded", "timeout": true}]},
+// regardless of IncludeFailurePolicy. Marshal fails with the context error when Options.Context is cancelled.
+type IncludeResolver func(ctx context.Context, ids []string) ([]interface{}, error)
+
+// IncludeFailurePolicy describes how failure of IncludeResolver is handled.
+type IncludeFailurePolicy int
+
+const (
+	// IncludeFail makes Marshal return the resolver error, which is the default.
+	IncludeFail IncludeFailurePolicy = iota
+	// IncludePartial marshals the document without the resources which failed to resolve,
+	// the failures are listed in document meta, e.g. "meta": {"partial": [{"type": "authors", "detail": "..."}]},
+	// and logged with Options.Logger.
+	IncludePartial
+)
+
+type includeFailure struct {
+	Type    string `json:"type"`
+	Detail  string `json:"detail"`
+	Timeout bool   `json:"timeout,omitempty"`
+}
+
+// resolveIncluded adds resources referenced by relationships, which aren't included yet, loaded by the resolvers of their types.
+// The relationships of primary data, included and resolved resources are followed, only along Options.Include paths when they are given.
+func resolveIncluded(doc *Document, opts *Options) ([]includeFailure, error) {
+	if len(opts.Resolvers) == 0 {
+		return nil, nil
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// node is a resource which relationships are followed, all of them or along the include paths.
+	type node struct {
+		ro    *ResourceObject
+		all   bool
+		paths [][]string
+	}
+
+	present := map[ResourceObjectIdentifier]bool{}
+	included := map[ResourceObjectIdentifier]*ResourceObject{}
+	expanded := map[ResourceObjectIdentifier]bool{}
+
+	for _, ro := range documentResources(doc) {
+		present[existenceKey(ro.ResourceObjectIdentifier)] = true
+	}
+
+	for _, ro := range doc.Included {
+		included[existenceKey(ro.ResourceObjectIdentifier)] = ro
+	}
+
+	var pending []node
+
+	if opts.Include == nil {
+		for _, ro := range documentResources(doc) {
+			pending = append(pending, node{ro: ro, all: true})
+		}
+	} else {
+		var paths [][]string
+
+		for _, path := range opts.Include {
+			paths = append(paths, strings.Split(path, "."))
+		}
+
+		for _, ro := range documentResources(&Document{Data: doc.Data}) {
+			pending = append(pending, node{ro: ro, paths: paths})
+		}
+	}
+
+	var failures []includeFailure
+
+	failed := map[string]bool{}
+
+	for len(pending) > 0 {
+		requested := map[string][]string{}
+		following := map[ResourceObjectIdentifier]*node{}
+
+		var next []node
+
+		for _, n := range pending {
+			for name := range n.ro.Relationships {
+				followed := n.all
+
+				var tails [][]string
+
+				for _, path := range n.paths {
+					if path[0] == name {
+						followed = true
+
+						if len(path) > 1 {
+							tails = append(tails, path[1:])
+						}
+					}
+				}
+
+				if !followed {
+					continue
+				}
+
+				for _, roi := range relationshipIdentifiers(n.ro, name) {
+					key := existenceKey(*roi)
+
+					if ro, ok := included[key]; ok && !n.all && len(tails) > 0 && !expanded[key] {
+						expanded[key] = true
+						next = append(next, node{ro: ro, paths: tails})
+					}
+
+					if len(key.ID) == 0 || present[key] || failed[key.Type] {
+						continue
+					}
+
+					if _, ok := opts.Resolvers[key.Type]; !ok {
+						continue
+					}
+
+					follow, ok := following[key]
+					if !ok {
+						follow = &node{}
+						following[key] = follow
+						requested[key.Type] = append(requested[key.Type], key.ID)
+					}
+
+					follow.all = follow.all || n.all
+					follow.paths = append(follow.paths, tails...)
+				}
+			}
+		}
+
+		types := make([]string, 0, len(requested))
+
+		for typ := range requested {
+			types = append(types, typ)
+		}
+
+		sort.Strings(types)
+
+		pending = next
+
+		for _, typ := range types {
+			resources, err := callResolver(ctx, opts.Resolvers[typ], requested[typ], opts.ResolveTimeout)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+
+				timeout := errors.Is(err, context.DeadlineExceeded)
+
+				if !timeout && opts.IncludeFailure != IncludePartial {
+					return nil, fmt.Errorf("jsonapi: resolve %q included: %w", typ, err)
+				}
+
+				if opts.Logger != nil {
+					opts.Logger.Printf("jsonapi: resolve %q included: %v", typ, err)
+				}
+
+				failed[typ] = true
+				failures = append(failures, includeFailure{Type: typ, Detail: err.Error(), Timeout: timeout})
+
+				continue
+			}
+
+			for _, resource := range resources {
+				mri, err := resourceIdentifier(resource)
+				if err != nil {
+					return nil, err
+				}
+
+				ro, err := marshalResourceObject(mri, opts)
+				if err != nil {
+					return nil, err
+				}
+
+				key := existenceKey(ro.ResourceObjectIdentifier)
+				if present[key] {
+					continue
+				}
+
+				present[key] = true
+
+				resolved := node{ro: &ro}
+
+				if follow, ok := following[key]; ok {
+					resolved.all, resolved.paths = follow.all, follow.paths
+				}
+
+				doc.Included = append(doc.Included, &ro)
+				pending = append(pending, resolved)
+			}
+		}
+	}
+
+	return failures, nil
+}
+
+// callResolver calls the resolver with the context cancelled after the timeout, when it is not zero,
+// and returns the context error as soon as the context is done.
+func callResolver(ctx context.Context, resolver IncludeResolver, ids []string, timeout time.Duration) ([]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		resources []interface{}
+		err       error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		resources, err := resolver(ctx, ids)
+		done <- result{resources, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.resources, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func marshalPartial(meta json.RawMessage, failures []includeFailure) (json.RawMessage, error) {
+	return transformAttributes(meta, func(members map[string]json.RawMessage) error {
+		raw, err := json.Marshal(failures)
+		if err != nil {
+			return err
+		}
+
+		members[PartialMeta] = raw
+
+		return nil
+	})
+}
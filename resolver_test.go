@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Review struct {
+	ID       string `json:"-"`
+	Body     string `json:"body"`
+	AuthorID string `json:"-"`
+	BookID   string `json:"-"`
+}
+
+func (r Review) GetID() string {
+	return r.ID
+}
+
+func (r Review) GetType() string {
+	return "reviews"
+}
+
+func (r Review) GetRelationships() map[string]interface{} {
+	return map[string]interface{}{
+		"author": ResourceObjectIdentifier{Type: "authors", ID: r.AuthorID},
+		"book":   ResourceObjectIdentifier{Type: "books", ID: r.BookID},
+	}
+}
+
+type ReviewsView struct {
+	Reviews []Review
+}
+
+func (v ReviewsView) GetData() interface{} {
+	return v.Reviews
+}
+
+var _ = Describe("IncludeResolver", func() {
+
+	view := ReviewsView{
+		Reviews: []Review{
+			{ID: "1", Body: "Great", AuthorID: "1", BookID: "1"},
+			{ID: "2", Body: "Good", AuthorID: "1", BookID: "2"},
+		},
+	}
+
+	var calls map[string][][]string
+
+	authors := func(ctx context.Context, ids []string) ([]interface{}, error) {
+		calls["authors"] = append(calls["authors"], ids)
+
+		var resources []interface{}
+
+		for _, id := range ids {
+			resources = append(resources, Author{ID: id, Name: "Author " + id})
+		}
+
+		return resources, nil
+	}
+
+	books := func(ctx context.Context, ids []string) ([]interface{}, error) {
+		calls["books"] = append(calls["books"], ids)
+
+		return nil, errors.New("books service is unavailable")
+	}
+
+	BeforeEach(func() {
+		calls = map[string][][]string{}
+	})
+
+	It("resolves included resources once per type", func() {
+		result, err := Marshal(view, WithResolver("authors", authors))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(calls["authors"]).Should(Equal([][]string{{"1"}}))
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Included).Should(HaveLen(1))
+		Ω(doc.Included[0].Type).Should(Equal("authors"))
+	})
+
+	It("resolves only requested include paths", func() {
+		_, err := Marshal(view, WithResolver("authors", authors), WithResolver("books", books), WithInclude("author"))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(calls).Should(HaveKey("authors"))
+		Ω(calls).ShouldNot(HaveKey("books"))
+	})
+
+	It("returns resolver error by default", func() {
+		_, err := Marshal(view, WithResolver("authors", authors), WithResolver("books", books))
+
+		Ω(err).Should(MatchError(`jsonapi: resolve "books" included: books service is unavailable`))
+	})
+
+	It("marshals partial document when resolver fails", func() {
+		buf := &bytes.Buffer{}
+
+		result, err := Marshal(view, WithResolver("authors", authors), WithResolver("books", books), WithPartialIncludes(log.New(buf, "", 0)))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(calls["books"]).Should(Equal([][]string{{"1", "2"}}))
+		Ω(buf.String()).Should(ContainSubstring("books service is unavailable"))
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Included).Should(HaveLen(1))
+		Ω(doc.Meta).Should(MatchJSON(`{"partial": [{"type": "books", "detail": "books service is unavailable"}]}`))
+	})
+
+	It("skips resolvers exceeding the timeout", func() {
+		slow := func(ctx context.Context, ids []string) ([]interface{}, error) {
+			time.Sleep(time.Second)
+
+			return nil, nil
+		}
+
+		result, err := Marshal(view, WithResolver("authors", authors), WithResolver("books", slow), WithResolveTimeout(10*time.Millisecond))
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+		Ω(doc.Included).Should(HaveLen(1))
+		Ω(doc.Meta).Should(MatchJSON(`{"partial": [{"type": "books", "detail": "context deadline exceeded", "timeout": true}]}`))
+	})
+
+	It("returns the request context error", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Marshal(view, WithResolver("authors", authors), WithContext(ctx))
+
+		Ω(err).Should(MatchError(context.Canceled))
+	})
+})
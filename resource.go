@@ -0,0 +1,168 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ResourceBuilder constructs resource object programmatically, when there is no Go struct describing it,
+// e.g. in admin tools or for schema-less data. See DocumentBuilder for building documents out of them.
+//
+// ResourceBuilder example:
+//
+//	book, err := jsonapi.NewResource("books", "1").
+//	  Attr("title", "An Introduction to Programming in Go").
+//	  ToOne("author", "authors", "9").
+//	  ToMany("readers", "people", "1", "2").
+//	  Link("self", "/books/1").
+//	  Build()
+type ResourceBuilder struct {
+	ro         *ResourceObject
+	attributes map[string]json.RawMessage
+	meta       map[string]json.RawMessage
+	err        error
+}
+
+// NewResource creates ResourceBuilder of the resource identified by type and ID.
+func NewResource(resourceType, id string) *ResourceBuilder {
+	return &ResourceBuilder{
+		ro: &ResourceObject{
+			ResourceObjectIdentifier: ResourceObjectIdentifier{Type: resourceType, ID: id},
+		},
+		attributes: map[string]json.RawMessage{},
+		meta:       map[string]json.RawMessage{},
+	}
+}
+
+// LID sets local ID identifying the resource within the document when it has no ID yet.
+func (b *ResourceBuilder) LID(lid string) *ResourceBuilder {
+	b.ro.LID = lid
+
+	return b
+}
+
+// Attr sets the resource attribute, the error of the value marshaling is returned by Build.
+func (b *ResourceBuilder) Attr(name string, value interface{}) *ResourceBuilder {
+	if raw, err := marshalValue(value); err == nil {
+		b.attributes[name] = raw
+	} else {
+		b.fail(err)
+	}
+
+	return b
+}
+
+// Meta sets the resource meta member, the error of the value marshaling is returned by Build.
+func (b *ResourceBuilder) Meta(name string, value interface{}) *ResourceBuilder {
+	if raw, err := marshalValue(value); err == nil {
+		b.meta[name] = raw
+	} else {
+		b.fail(err)
+	}
+
+	return b
+}
+
+// ToOne sets to-one relationship linkage, empty ID is marshaled as null.
+func (b *ResourceBuilder) ToOne(name, resourceType, id string) *ResourceBuilder {
+	data := &relationshipData{}
+
+	if len(id) > 0 {
+		data.One = &ResourceObjectIdentifier{Type: resourceType, ID: id}
+	}
+
+	b.relationships()[name] = &relationship{Data: data}
+
+	return b
+}
+
+// ToMany sets to-many relationship linkage of resources of the same type, no IDs are marshaled as empty array.
+func (b *ResourceBuilder) ToMany(name, resourceType string, ids ...string) *ResourceBuilder {
+	data := &relationshipData{Many: make([]*ResourceObjectIdentifier, 0, len(ids))}
+
+	for _, id := range ids {
+		data.Many = append(data.Many, &ResourceObjectIdentifier{Type: resourceType, ID: id})
+	}
+
+	b.relationships()[name] = &relationship{Data: data}
+
+	return b
+}
+
+// Link sets the resource link by name, e.g. "self".
+func (b *ResourceBuilder) Link(name, href string) *ResourceBuilder {
+	if b.ro.Links == nil {
+		b.ro.Links = Links{}
+	}
+
+	b.ro.Links[name] = &Link{Href: href}
+
+	return b
+}
+
+// Build returns the built resource object, or the first error occurred while building it.
+func (b *ResourceBuilder) Build() (*ResourceObject, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if len(b.attributes) > 0 {
+		attributes, err := transformAttributes(nil, copyMembers(b.attributes))
+		if err != nil {
+			return nil, err
+		}
+
+		b.ro.Attributes = attributes
+	}
+
+	if len(b.meta) > 0 {
+		meta, err := transformAttributes(nil, copyMembers(b.meta))
+		if err != nil {
+			return nil, err
+		}
+
+		b.ro.Meta = meta
+	}
+
+	return b.ro, nil
+}
+
+func (b *ResourceBuilder) relationships() map[string]*relationship {
+	if b.ro.Relationships == nil {
+		b.ro.Relationships = map[string]*relationship{}
+	}
+
+	return b.ro.Relationships
+}
+
+func (b *ResourceBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+func copyMembers(from map[string]json.RawMessage) func(members map[string]json.RawMessage) error {
+	return func(members map[string]json.RawMessage) error {
+		for k, v := range from {
+			members[k] = v
+		}
+
+		return nil
+	}
+}
+
+func marshalValue(value interface{}) (json.RawMessage, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
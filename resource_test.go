@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("ResourceBuilder", func() {
+
+	It("builds resource object", func() {
+		book, err := NewResource("books", "1").
+			Attr("title", "An Introduction to Programming in Go").
+			Attr("year", 2012).
+			Meta("rank", 1).
+			ToOne("author", "authors", "9").
+			ToOne("editor", "people", "").
+			ToMany("readers", "people", "1", "2").
+			ToMany("reviews", "reviews").
+			Link("self", "/books/1").
+			Build()
+
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(book)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		expected := `
+      {
+        "type": "books",
+        "id": "1",
+        "attributes": {
+          "title": "An Introduction to Programming in Go",
+          "year": 2012
+        },
+        "meta": {"rank": 1},
+        "relationships": {
+          "author": {"data": {"type": "authors", "id": "9"}},
+          "editor": {"data": null},
+          "readers": {"data": [{"type": "people", "id": "1"}, {"type": "people", "id": "2"}]},
+          "reviews": {"data": []}
+        },
+        "links": {"self": "/books/1"}
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+	})
+
+	It("builds document out of resource objects", func() {
+		book, err := NewResource("books", "").LID("new-book").Attr("title", "Go").Build()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		doc, err := NewDocument().SetOne(book).Build()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(doc)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "books", "lid": "new-book", "attributes": {"title": "Go"}}}`))
+	})
+
+	It("returns attribute marshaling error", func() {
+		_, err := NewResource("books", "1").Attr("title", make(chan int)).Build()
+
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,217 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// Scalars is a registry of custom attribute types encoding and decoding, e.g. of decimal.Decimal,
+// applied to struct fields of the registered types and pointers to them instead of their own JSON encoding,
+// so domain types don't have to implement json.Marshaler and json.Unmarshaler. It is safe for concurrent use.
+//
+// Scalars example:
+//
+//	var scalars = jsonapi.NewScalars()
+//
+//	jsonapi.RegisterScalar(scalars,
+//	  func(d decimal.Decimal) (interface{}, error) {
+//	    return d.String(), nil
+//	  },
+//	  func(raw json.RawMessage) (decimal.Decimal, error) {
+//	    var s string
+//	    if err := json.Unmarshal(raw, &s); err != nil {
+//	      return decimal.Decimal{}, err
+//	    }
+//	    return decimal.NewFromString(s)
+//	  },
+//	)
+//
+//	payload, err := jsonapi.Marshal(view, jsonapi.WithScalars(scalars))
+//
+// Null attributes of pointer fields are not decoded, the fields are set to nil.
+type Scalars struct {
+	mu      sync.RWMutex
+	scalars map[reflect.Type]scalar
+}
+
+type scalar struct {
+	encode func(reflect.Value) (interface{}, error)
+	decode func(json.RawMessage) (reflect.Value, error)
+}
+
+// NewScalars creates empty Scalars registry.
+func NewScalars() *Scalars {
+	return &Scalars{scalars: map[reflect.Type]scalar{}}
+}
+
+// RegisterScalar registers encode and decode functions of T attributes, encode returns the value marshaled
+// in place of the attribute, decode parses the attribute raw JSON value. Either of them may be nil
+// to leave the direction to the type JSON encoding.
+func RegisterScalar[T any](s *Scalars, encode func(T) (interface{}, error), decode func(json.RawMessage) (T, error)) {
+	var sc scalar
+
+	if encode != nil {
+		sc.encode = func(v reflect.Value) (interface{}, error) {
+			return encode(v.Interface().(T))
+		}
+	}
+
+	if decode != nil {
+		sc.decode = func(raw json.RawMessage) (reflect.Value, error) {
+			v, err := decode(raw)
+			return reflect.ValueOf(&v).Elem(), err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scalars[reflect.TypeOf((*T)(nil)).Elem()] = sc
+}
+
+// lookup returns scalar of the field type, a pointer to registered type gives the type scalar.
+func (s *Scalars) lookup(typ reflect.Type) (scalar, bool) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sc, ok := s.scalars[typ]
+
+	return sc, ok
+}
+
+// encodeScalars replaces attributes of registered scalar types fields of v by their encoded values.
+func encodeScalars(attributes json.RawMessage, v interface{}, scalars *Scalars) (json.RawMessage, error) {
+	value := reflect.ValueOf(v)
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return attributes, nil
+		}
+
+		value = value.Elem()
+	}
+
+	encoded := map[string]interface{}{}
+
+	for name, field := range jsonFields(value.Type()) {
+		sc, ok := scalars.lookup(field.field.Type)
+		if !ok || sc.encode == nil {
+			continue
+		}
+
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if !ok {
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+
+			fieldValue = fieldValue.Elem()
+		}
+
+		encodedValue, err := sc.encode(fieldValue)
+		if err != nil {
+			return nil, &AttributeError{Pointer: Pointer(name), Err: err}
+		}
+
+		encoded[name] = encodedValue
+	}
+
+	if len(encoded) == 0 {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for name, encodedValue := range encoded {
+			// omitted members, e.g. by omitempty, stay omitted
+			if _, ok := members[name]; !ok {
+				continue
+			}
+
+			raw, err := json.Marshal(encodedValue)
+			if err != nil {
+				return &AttributeError{Pointer: Pointer(name), Err: err}
+			}
+
+			members[name] = raw
+		}
+
+		return nil
+	})
+}
+
+// decodeScalars sets fields of registered scalar types of v from the attributes, it returns the other attributes.
+func decodeScalars(attributes json.RawMessage, v interface{}, scalars *Scalars, pointer string) (json.RawMessage, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return attributes, nil
+	}
+
+	value = value.Elem()
+	if value.Kind() != reflect.Struct {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for name, field := range jsonFields(value.Type()) {
+			raw, ok := members[name]
+			if !ok {
+				continue
+			}
+
+			sc, ok := scalars.lookup(field.field.Type)
+			if !ok || sc.decode == nil {
+				continue
+			}
+
+			delete(members, name)
+
+			fieldValue, ok := fieldByIndex(value, field.index)
+			if !ok || !fieldValue.CanSet() {
+				continue
+			}
+
+			if fieldValue.Kind() == reflect.Ptr && string(raw) == "null" {
+				fieldValue.Set(reflect.Zero(fieldValue.Type()))
+				continue
+			}
+
+			decoded, err := sc.decode(raw)
+			if err != nil {
+				return &AttributeError{Pointer: pointer + Pointer(name), Err: err}
+			}
+
+			if fieldValue.Kind() == reflect.Ptr {
+				ptr := reflect.New(fieldValue.Type().Elem())
+				ptr.Elem().Set(decoded)
+				decoded = ptr
+			}
+
+			fieldValue.Set(decoded)
+		}
+
+		return nil
+	})
+}
+
+// hasStructAttributes tells attributes of v are encoded from and decoded into its struct fields.
+func hasStructAttributes(v interface{}) bool {
+	switch v.(type) {
+	case MarshalAttributes, MarshalRawAttributes, UnmarshalAttributes, UnmarshalRawAttributes:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+// Fixed is a domain decimal type without JSON encoding of its own.
+type Fixed struct {
+	cents int64
+}
+
+func (f Fixed) String() string {
+	return fmt.Sprintf("%d.%02d", f.cents/100, f.cents%100)
+}
+
+func ParseFixed(s string) (Fixed, error) {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) != 2 || len(parts[1]) != 2 {
+		return Fixed{}, errors.New("fixed must have two decimal places")
+	}
+
+	units, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Fixed{}, err
+	}
+
+	cents, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Fixed{}, err
+	}
+
+	return Fixed{cents: units*100 + cents}, nil
+}
+
+type Listing struct {
+	ID       string `json:"-"`
+	Title    string `json:"title"`
+	Price    Fixed  `json:"price"`
+	Discount *Fixed `json:"discount"`
+}
+
+func (l Listing) GetID() string {
+	return l.ID
+}
+
+func (l Listing) GetType() string {
+	return "listings"
+}
+
+func (l *Listing) SetID(id string) error {
+	l.ID = id
+	return nil
+}
+
+func (l *Listing) SetType(string) error {
+	return nil
+}
+
+func (l *Listing) SetData(to func(target interface{}) error) error {
+	return to(l)
+}
+
+func (l Listing) GetData() interface{} {
+	return l
+}
+
+var _ = Describe("Scalars", func() {
+	var scalars *Scalars
+
+	BeforeEach(func() {
+		scalars = NewScalars()
+
+		RegisterScalar(scalars,
+			func(f Fixed) (interface{}, error) {
+				return f.String(), nil
+			},
+			func(raw json.RawMessage) (Fixed, error) {
+				var s string
+				if err := json.Unmarshal(raw, &s); err != nil {
+					return Fixed{}, err
+				}
+				return ParseFixed(s)
+			},
+		)
+	})
+
+	It("encodes attributes of registered types and pointers to them", func() {
+		discount := Fixed{cents: 150}
+
+		payload, err := Marshal(Listing{ID: "1", Title: "Lamp", Price: Fixed{cents: 1999}, Discount: &discount}, WithScalars(scalars))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`{"data": {"type": "listings", "id": "1", "attributes": {"title": "Lamp", "price": "19.99", "discount": "1.50"}}}`))
+
+		payload, err = Marshal(Listing{ID: "1", Title: "Lamp", Price: Fixed{cents: 1999}}, WithScalars(scalars))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`{"data": {"type": "listings", "id": "1", "attributes": {"title": "Lamp", "price": "19.99", "discount": null}}}`))
+	})
+
+	It("decodes attributes of registered types and pointers to them", func() {
+		var listing Listing
+
+		_, err := Unmarshal([]byte(`{"data": {"type": "listings", "id": "1", "attributes": {"title": "Lamp", "price": "19.99", "discount": "1.50"}}}`), &listing, WithScalars(scalars))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(listing.Title).Should(Equal("Lamp"))
+		Ω(listing.Price).Should(Equal(Fixed{cents: 1999}))
+		Ω(listing.Discount).Should(Equal(&Fixed{cents: 150}))
+
+		_, err = Unmarshal([]byte(`{"data": {"type": "listings", "id": "1", "attributes": {"discount": null}}}`), &listing, WithScalars(scalars))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(listing.Discount).Should(BeNil())
+	})
+
+	It("reports decoding failures as attribute errors", func() {
+		_, err := Unmarshal([]byte(`{"data": {"type": "listings", "id": "1", "attributes": {"price": "19.9"}}}`), &Listing{}, WithScalars(scalars))
+
+		var attributeErr *AttributeError
+
+		Ω(errors.As(err, &attributeErr)).Should(BeTrue())
+		Ω(attributeErr.Pointer).Should(Equal("/data/attributes/price"))
+	})
+
+	It("leaves the types to their own JSON encoding without registry", func() {
+		payload, err := Marshal(Listing{ID: "1", Title: "Lamp", Price: Fixed{cents: 1999}})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(payload).Should(MatchJSON(`{"data": {"type": "listings", "id": "1", "attributes": {"title": "Lamp", "price": {}, "discount": null}}}`))
+	})
+})
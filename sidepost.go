@@ -0,0 +1,176 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrSidepostCycle returned when sideposted resources reference each other in a cycle.
+var ErrSidepostCycle = errors.New("jsonapi: sideposted resources reference each other in a cycle")
+
+var resourceTypes = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+}{
+	byName: map[string]reflect.Type{},
+}
+
+// RegisterResourceType registers Go type of sample for JSON API resource type, so sideposted resources
+// of that type can be unmarshaled, see Options.Sidepost. Pointer to sample type must implement UnmarshalResourceIdentifier
+// or sample type must have the resource members tagged.
+//
+// RegisterResourceType example:
+//
+//	jsonapi.RegisterResourceType("authors", Author{})
+func RegisterResourceType(resourceType string, sample interface{}) {
+	typ := reflect.TypeOf(sample)
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	resourceTypes.Lock()
+	defer resourceTypes.Unlock()
+
+	resourceTypes.byName[resourceType] = typ
+}
+
+func lookupResourceType(resourceType string) (reflect.Type, bool) {
+	resourceTypes.RLock()
+	defer resourceTypes.RUnlock()
+
+	typ, ok := resourceTypes.byName[resourceType]
+
+	return typ, ok
+}
+
+// sidepostKey identifies resource within the document by type and ID, or by type and LID when it has no ID yet.
+func sidepostKey(roi ResourceObjectIdentifier) ResourceObjectIdentifier {
+	if len(roi.ID) > 0 {
+		return ResourceObjectIdentifier{Type: roi.Type, ID: roi.ID}
+	}
+
+	return ResourceObjectIdentifier{Type: roi.Type, LID: roi.LID}
+}
+
+type sidepostResolver struct {
+	doc      *Document
+	opts     *Options
+	index    map[ResourceObjectIdentifier]int
+	visiting map[ResourceObjectIdentifier]bool
+	resolved map[ResourceObjectIdentifier]interface{}
+}
+
+// resolveSideposted unmarshals included resources into their registered Go types in dependency order,
+// so a resource is unmarshaled after the included resources it references.
+func resolveSideposted(doc *Document, opts *Options) (map[ResourceObjectIdentifier]interface{}, error) {
+	resolver := &sidepostResolver{
+		doc:      doc,
+		opts:     opts,
+		index:    map[ResourceObjectIdentifier]int{},
+		visiting: map[ResourceObjectIdentifier]bool{},
+		resolved: map[ResourceObjectIdentifier]interface{}{},
+	}
+
+	for i, ro := range doc.Included {
+		resolver.index[sidepostKey(ro.ResourceObjectIdentifier)] = i
+	}
+
+	for _, ro := range doc.Included {
+		if err := resolver.resolve(sidepostKey(ro.ResourceObjectIdentifier)); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolver.resolved, nil
+}
+
+func (sr *sidepostResolver) resolve(key ResourceObjectIdentifier) error {
+	if _, ok := sr.resolved[key]; ok {
+		return nil
+	}
+
+	if sr.visiting[key] {
+		return fmt.Errorf("%w: %s", ErrSidepostCycle, describeIdentifier(key))
+	}
+
+	sr.visiting[key] = true
+	defer delete(sr.visiting, key)
+
+	i := sr.index[key]
+	ro := sr.doc.Included[i]
+
+	for name := range ro.Relationships {
+		for _, roi := range relationshipIdentifiers(ro, name) {
+			dependency := sidepostKey(*roi)
+
+			if _, ok := sr.index[dependency]; ok {
+				if err := sr.resolve(dependency); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	typ, ok := lookupResourceType(ro.Type)
+	if !ok {
+		return fmt.Errorf("jsonapi: sideposted resource type %q is not registered", ro.Type)
+	}
+
+	value := reflect.New(typ).Interface()
+
+	ui, err := unmarshalTarget(value)
+	if err != nil {
+		return err
+	}
+
+	opts := *sr.opts
+	opts.sideposted = sr.resolved
+
+	if err := unmarshalResourceObject(ro, ui, fmt.Sprintf("/included/%d", i), &opts); err != nil {
+		return err
+	}
+
+	sr.resolved[key] = value
+
+	return nil
+}
+
+func describeIdentifier(roi ResourceObjectIdentifier) string {
+	if len(roi.ID) > 0 {
+		return fmt.Sprintf("%s %q", roi.Type, roi.ID)
+	}
+
+	return fmt.Sprintf("%s lid %q", roi.Type, roi.LID)
+}
+
+func unmarshalSidepostedMany(many []*ResourceObjectIdentifier, opts *Options) []interface{} {
+	if len(opts.sideposted) == 0 {
+		return nil
+	}
+
+	found := false
+	values := make([]interface{}, 0, len(many))
+
+	for _, roi := range many {
+		if resolved, ok := opts.sideposted[sidepostKey(*roi)]; ok {
+			values = append(values, resolved)
+			found = true
+			continue
+		}
+
+		values = append(values, roi)
+	}
+
+	if !found {
+		return nil
+	}
+
+	return values
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Post struct {
+	ID     string
+	Title  string `json:"title"`
+	Writer *Writer
+	Tags   []interface{}
+}
+
+func (p *Post) SetID(id string) error {
+	p.ID = id
+	return nil
+}
+
+func (p *Post) SetType(string) error {
+	return nil
+}
+
+func (p *Post) SetRelationships(relationships map[string]interface{}) error {
+	if writer, ok := relationships["writer"].(*Writer); ok {
+		p.Writer = writer
+	}
+
+	if tags, ok := relationships["tags"].([]interface{}); ok {
+		p.Tags = tags
+	}
+
+	return nil
+}
+
+type PostView struct {
+	Post Post
+}
+
+func (v *PostView) SetData(to func(target interface{}) error) error {
+	return to(&v.Post)
+}
+
+type Writer struct {
+	LID    string
+	Name   string `json:"name"`
+	Avatar *Avatar
+	Editor *Writer
+}
+
+func (w *Writer) SetID(string) error {
+	return nil
+}
+
+func (w *Writer) SetLID(lid string) error {
+	w.LID = lid
+	return nil
+}
+
+func (w *Writer) SetType(string) error {
+	return nil
+}
+
+func (w *Writer) SetRelationships(relationships map[string]interface{}) error {
+	if avatar, ok := relationships["avatar"].(*Avatar); ok {
+		w.Avatar = avatar
+	}
+
+	if editor, ok := relationships["editor"].(*Writer); ok {
+		w.Editor = editor
+	}
+
+	return nil
+}
+
+type Avatar struct {
+	URL string `json:"url"`
+}
+
+func (a *Avatar) SetID(string) error {
+	return nil
+}
+
+func (a *Avatar) SetType(string) error {
+	return nil
+}
+
+var _ = Describe("Sidepost", func() {
+
+	BeforeEach(func() {
+		RegisterResourceType("writers", Writer{})
+		RegisterResourceType("avatars", Avatar{})
+	})
+
+	data := []byte(`
+    {
+      "data": {
+        "type": "posts",
+        "attributes": { "title": "Sideposting" },
+        "relationships": {
+          "writer": { "data": { "type": "writers", "lid": "w1" } },
+          "tags": { "data": [{ "type": "tags", "id": "1" }] }
+        }
+      },
+      "included": [
+        {
+          "type": "writers",
+          "lid": "w1",
+          "attributes": { "name": "Rob" },
+          "relationships": {
+            "avatar": { "data": { "type": "avatars", "lid": "a1" } }
+          }
+        },
+        {
+          "type": "avatars",
+          "lid": "a1",
+          "attributes": { "url": "https://example.com/rob.png" }
+        }
+      ]
+    }
+  `)
+
+	It("passes sideposted resources into SetRelationships", func() {
+		view := PostView{}
+
+		_, err := UnmarshalWithOptions(data, &view, Options{Sidepost: true})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Post.Title).Should(Equal("Sideposting"))
+		Ω(view.Post.Writer).ShouldNot(BeNil())
+		Ω(view.Post.Writer.LID).Should(Equal("w1"))
+		Ω(view.Post.Writer.Name).Should(Equal("Rob"))
+		Ω(view.Post.Writer.Avatar).Should(Equal(&Avatar{URL: "https://example.com/rob.png"}))
+		Ω(view.Post.Tags).Should(BeNil())
+	})
+
+	It("passes resource identifiers without sideposting", func() {
+		view := PostView{}
+
+		_, err := Unmarshal(data, &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Post.Writer).Should(BeNil())
+	})
+
+	It("returns error for sideposted resources cycle", func() {
+		cycle := []byte(`
+      {
+        "data": {
+          "type": "posts",
+          "relationships": {
+            "writer": { "data": { "type": "writers", "lid": "w1" } }
+          }
+        },
+        "included": [
+          {
+            "type": "writers",
+            "lid": "w1",
+            "relationships": { "editor": { "data": { "type": "writers", "lid": "w2" } } }
+          },
+          {
+            "type": "writers",
+            "lid": "w2",
+            "relationships": { "editor": { "data": { "type": "writers", "lid": "w1" } } }
+          }
+        ]
+      }
+    `)
+
+		_, err := UnmarshalWithOptions(cycle, &PostView{}, Options{Sidepost: true})
+
+		Ω(errors.Is(err, ErrSidepostCycle)).Should(BeTrue())
+	})
+
+	It("returns error for unregistered sideposted resource type", func() {
+		unknown := []byte(`{"data": {"type": "posts"}, "included": [{"type": "comments", "lid": "c1"}]}`)
+
+		_, err := UnmarshalWithOptions(unknown, &PostView{}, Options{Sidepost: true})
+
+		Ω(err).Should(MatchError(`jsonapi: sideposted resource type "comments" is not registered`))
+	})
+})
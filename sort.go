@@ -0,0 +1,341 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortSpec describes sort query parameter, the fields are given in order of precedence.
+type SortSpec []SortField
+
+// SortField describes a single sort parameter field.
+type SortField struct {
+	// Field name, may be a dot-separated path of nested attributes.
+	Field string
+	// Descending order, the field is prefixed with minus sign.
+	Descending bool
+}
+
+// String returns sort query parameter value of the spec, e.g. "-year,title".
+func (s SortSpec) String() string {
+	fields := make([]string, 0, len(s))
+
+	for _, sf := range s {
+		if sf.Descending {
+			fields = append(fields, "-"+sf.Field)
+		} else {
+			fields = append(fields, sf.Field)
+		}
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// Comparator compares two collection elements by a sort field,
+// it returns a negative number when a is less than b, zero when they are equal and a positive number otherwise.
+type Comparator func(a, b interface{}) int
+
+// ParseSort parses sort query parameter value, e.g. "-year,title", the errors are returned as error objects with source parameter.
+func ParseSort(value string) (SortSpec, []*ErrorObject) {
+	var (
+		spec SortSpec
+		errs []*ErrorObject
+	)
+
+	for _, field := range strings.Split(value, ",") {
+		sf := SortField{Field: field}
+
+		if strings.HasPrefix(field, "-") {
+			sf = SortField{Field: field[1:], Descending: true}
+		}
+
+		if len(sf.Field) == 0 {
+			errs = append(errs, queryError("sort", "Invalid query parameter", "Sort field must not be empty."))
+			continue
+		}
+
+		spec = append(spec, sf)
+	}
+
+	return spec, errs
+}
+
+// ApplySort sorts slice of structs (or pointers to structs) in place according to the spec, keeping the order of equal elements,
+// slice may be given by pointer. Fields are looked up by their json names, "id" field uses GetID when element implements
+// MarshalResourceIdentifier. Strings, numbers, booleans and time.Time values are compared out of the box,
+// comparators override comparison by field name. Elements of other types, e.g. interfaces, are sorted by comparators only.
+// Fields which can't be compared are reported as error objects with source parameter and the slice is left unsorted,
+// a value which isn't a slice is reported as 500 Internal Server Error.
+//
+// ApplySort example:
+//
+//	errs := jsonapi.ApplySort(books, params.Sort, map[string]jsonapi.Comparator{
+//	  "author": func(a, b interface{}) int {
+//	    return strings.Compare(a.(Book).Author.Name, b.(Book).Author.Name)
+//	  },
+//	})
+func ApplySort(slice interface{}, spec SortSpec, comparators map[string]Comparator) []*ErrorObject {
+	var errs []*ErrorObject
+
+	value := reflect.ValueOf(slice)
+
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Slice {
+		return []*ErrorObject{NewError(http.StatusInternalServerError, "", http.StatusText(http.StatusInternalServerError),
+			fmt.Sprintf("ApplySort called with non-slice value of type %T.", slice))}
+	}
+
+	compare := make([]Comparator, len(spec))
+
+	for i, sf := range spec {
+		if comparator, ok := comparators[sf.Field]; ok {
+			compare[i] = comparator
+			continue
+		}
+
+		comparator, ok := fieldComparator(value.Type().Elem(), sf.Field)
+		if !ok {
+			errs = append(errs, queryError("sort", "Invalid query parameter", fmt.Sprintf("Sorting by %q is not supported.", sf.Field)))
+			continue
+		}
+
+		compare[i] = comparator
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	elements := make([]interface{}, value.Len())
+
+	for i := range elements {
+		elements[i] = value.Index(i).Interface()
+	}
+
+	indexes := make([]int, len(elements))
+
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := elements[indexes[i]], elements[indexes[j]]
+
+		for k, sf := range spec {
+			result := compare[k](a, b)
+
+			if sf.Descending {
+				result = -result
+			}
+
+			if result != 0 {
+				return result < 0
+			}
+		}
+
+		return false
+	})
+
+	sorted := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+
+	for i, index := range indexes {
+		sorted.Index(i).Set(value.Index(index))
+	}
+
+	reflect.Copy(value, sorted)
+
+	return nil
+}
+
+func fieldComparator(typ reflect.Type, field string) (Comparator, bool) {
+	if field == "id" && typ.Implements(reflect.TypeOf((*MarshalResourceIdentifier)(nil)).Elem()) {
+		return func(a, b interface{}) int {
+			return strings.Compare(a.(MarshalResourceIdentifier).GetID(), b.(MarshalResourceIdentifier).GetID())
+		}, true
+	}
+
+	path := strings.Split(field, ".")
+
+	if _, ok := jsonFieldType(typ, path); !ok {
+		return nil, false
+	}
+
+	return func(a, b interface{}) int {
+		av, _ := jsonFieldValue(reflect.ValueOf(a), path)
+		bv, _ := jsonFieldValue(reflect.ValueOf(b), path)
+
+		return compareValues(av, bv)
+	}, true
+}
+
+func jsonFieldType(typ reflect.Type, path []string) (reflect.Type, bool) {
+	for _, name := range path {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		if typ.Kind() != reflect.Struct {
+			return nil, false
+		}
+
+		index, ok := jsonFieldIndex(typ, name)
+		if !ok {
+			return nil, false
+		}
+
+		typ = typ.FieldByIndex(index).Type
+	}
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ, isComparable(typ)
+}
+
+func jsonFieldValue(value reflect.Value, path []string) (reflect.Value, bool) {
+	for _, name := range path {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			value = value.Elem()
+		}
+
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		index, ok := jsonFieldIndex(value.Type(), name)
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		value, ok = fieldByIndex(value, index)
+		if !ok {
+			return reflect.Value{}, false
+		}
+	}
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		value = value.Elem()
+	}
+
+	return value, true
+}
+
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(x)
+	}
+
+	return value, true
+}
+
+func jsonFieldIndex(typ reflect.Type, name string) ([]int, bool) {
+	field, ok := jsonFields(typ)[name]
+	if !ok {
+		return nil, false
+	}
+
+	return field.index, true
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func isComparable(typ reflect.Type) bool {
+	if typ == timeType {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func compareValues(a, b reflect.Value) int {
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0
+	case !a.IsValid():
+		return -1
+	case !b.IsValid():
+		return 1
+	}
+
+	if a.Type() == timeType {
+		at, bt := a.Interface().(time.Time), b.Interface().(time.Time)
+
+		switch {
+		case at.Before(bt):
+			return -1
+		case at.After(bt):
+			return 1
+		}
+
+		return 0
+	}
+
+	switch a.Kind() {
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Bool:
+		switch {
+		case a.Bool() == b.Bool():
+			return 0
+		case b.Bool():
+			return -1
+		}
+
+		return 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int() < b.Int(), a.Int() > b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(a.Float() < b.Float(), a.Float() > b.Float())
+	}
+
+	return 0
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Sort", func() {
+
+	Describe("ParseSort", func() {
+
+		It("parses sort fields", func() {
+			spec, errs := ParseSort("-year,title")
+
+			Ω(errs).Should(BeEmpty())
+			Ω(spec).Should(Equal(SortSpec{
+				{Field: "year", Descending: true},
+				{Field: "title"},
+			}))
+			Ω(spec.String()).Should(Equal("-year,title"))
+		})
+
+		It("reports empty sort fields", func() {
+			_, errs := ParseSort("title,-")
+
+			Ω(errs).Should(HaveLen(1))
+			Ω(errs[0].Source.Parameter).Should(Equal("sort"))
+		})
+	})
+
+	Describe("ApplySort", func() {
+
+		books := func() Books {
+			return Books{
+				{ID: "1", Title: "Go in Action", Year: "2015"},
+				{ID: "2", Title: "An Introduction to Programming in Go", Year: "2012"},
+				{ID: "3", Title: "Go Programming Language", Year: "2015"},
+			}
+		}
+
+		ids := func(books Books) []string {
+			var result []string
+
+			for _, book := range books {
+				result = append(result, book.ID)
+			}
+
+			return result
+		}
+
+		It("sorts slice by json fields", func() {
+			result := books()
+
+			errs := ApplySort(result, SortSpec{{Field: "year", Descending: true}, {Field: "title"}}, nil)
+
+			Ω(errs).Should(BeEmpty())
+			Ω(ids(result)).Should(Equal([]string{"3", "1", "2"}))
+		})
+
+		It("sorts slice of pointers by id", func() {
+			first, second := books()[0], books()[1]
+			result := []*BookWithMeta{{Book: second}, {Book: first}}
+
+			errs := ApplySort(result, SortSpec{{Field: "id"}}, nil)
+
+			Ω(errs).Should(BeEmpty())
+			Ω(result[0].ID).Should(Equal("1"))
+		})
+
+		It("sorts slice using comparators", func() {
+			result := books()
+
+			errs := ApplySort(result, SortSpec{{Field: "length"}}, map[string]Comparator{
+				"length": func(a, b interface{}) int {
+					return len(a.(Book).Title) - len(b.(Book).Title)
+				},
+			})
+
+			Ω(errs).Should(BeEmpty())
+			Ω(ids(result)).Should(Equal([]string{"1", "3", "2"}))
+		})
+
+		It("reports fields which can't be sorted", func() {
+			result := books()
+
+			errs := ApplySort(result, SortSpec{{Field: "title"}, {Field: "unknown"}}, nil)
+
+			Ω(errs).Should(HaveLen(1))
+			Ω(strings.Contains(errs[0].Detail, "unknown")).Should(BeTrue())
+			Ω(ids(result)).Should(Equal([]string{"1", "2", "3"}))
+		})
+
+		It("reports value which isn't a slice", func() {
+			for _, value := range []interface{}{nil, Book{ID: "1"}, (*Books)(nil)} {
+				errs := ApplySort(value, SortSpec{{Field: "title"}}, nil)
+
+				Ω(errs).Should(HaveLen(1))
+				Ω(errs[0].Status).Should(Equal("500"))
+			}
+		})
+
+		It("sorts interface elements by comparators only", func() {
+			result := []interface{}{Book{ID: "2"}, Book{ID: "1"}}
+
+			Ω(ApplySort(result, SortSpec{{Field: "title"}}, nil)).Should(HaveLen(1))
+
+			errs := ApplySort(result, SortSpec{{Field: "id"}}, map[string]Comparator{
+				"id": func(a, b interface{}) int {
+					return strings.Compare(a.(Book).ID, b.(Book).ID)
+				},
+			})
+
+			Ω(errs).Should(BeEmpty())
+			Ω(result).Should(Equal([]interface{}{Book{ID: "1"}, Book{ID: "2"}}))
+		})
+	})
+})
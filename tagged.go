@@ -0,0 +1,442 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// taggedStruct describes struct type fields tagged as resource members:
+//
+//	type Book struct {
+//	  ID        int64    `json:"-" jsonapi:"id,type=books"`
+//	  Title     string   `json:"title"`
+//	  AuthorID  string   `json:"-" jsonapi:"relationship=author,type=authors"`
+//	  ReaderIDs []string `json:"-" jsonapi:"relationship=readers,type=people"`
+//	  Publisher *Publisher `json:"-" jsonapi:"relationship=publisher"`
+//	}
+//
+// Resource type is given either by type option of the id field or by the field tagged as `jsonapi:"type"`.
+// The id field may be a string, an integer or a type implementing encoding.TextMarshaler and encoding.TextUnmarshaler, e.g. UUID.
+// Relationship fields hold related resource IDs (string or []string, type option is required then)
+// or related resources (struct, pointer to struct or slice of them).
+type taggedStruct struct {
+	id            []int
+	lid           []int
+	typ           []int
+	typeName      string
+	relationships []taggedRelationship
+}
+
+type taggedRelationship struct {
+	name     string
+	index    []int
+	typeName string
+}
+
+var taggedStructsCache sync.Map
+
+// taggedStructOf returns tagged fields description of the struct type, false when the type has no id field tagged.
+func taggedStructOf(typ reflect.Type) (*taggedStruct, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	if cached, ok := taggedStructsCache.Load(typ); ok {
+		ts := cached.(*taggedStruct)
+
+		return ts, ts.id != nil
+	}
+
+	ts := &taggedStruct{}
+	collectTaggedFields(typ, nil, ts)
+
+	taggedStructsCache.Store(typ, ts)
+
+	return ts, ts.id != nil
+}
+
+func collectTaggedFields(typ reflect.Type, index []int, ts *taggedStruct) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			fieldType := field.Type
+
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+
+			if field.Anonymous && fieldType.Kind() == reflect.Struct {
+				collectTaggedFields(fieldType, fieldIndex, ts)
+			}
+
+			continue
+		}
+
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+
+		options := parseTag(tag)
+
+		if _, ok := options["id"]; ok && ts.id == nil {
+			ts.id = fieldIndex
+			ts.typeName = options["type"]
+		}
+
+		if _, ok := options["lid"]; ok && ts.lid == nil {
+			ts.lid = fieldIndex
+		}
+
+		if typeName, ok := options["type"]; ok && len(typeName) == 0 && ts.typ == nil {
+			ts.typ = fieldIndex
+		}
+
+		if name, ok := options["relationship"]; ok && len(name) > 0 {
+			ts.relationships = append(ts.relationships, taggedRelationship{
+				name:     name,
+				index:    fieldIndex,
+				typeName: options["type"],
+			})
+		}
+	}
+}
+
+// taggedResource implements the Marshal interfaces for values of tagged struct types.
+type taggedResource struct {
+	value  reflect.Value
+	tagged *taggedStruct
+}
+
+func newTaggedResource(v interface{}) (taggedResource, bool) {
+	value := reflect.ValueOf(v)
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return taggedResource{}, false
+		}
+
+		value = value.Elem()
+	}
+
+	ts, ok := taggedStructOf(value.Type())
+	if !ok {
+		return taggedResource{}, false
+	}
+
+	return taggedResource{value: value, tagged: ts}, true
+}
+
+func (tr taggedResource) stringField(index []int) string {
+	if index == nil {
+		return ""
+	}
+
+	field, ok := fieldByIndex(tr.value, index)
+	if !ok || field.Kind() != reflect.String {
+		return ""
+	}
+
+	return field.String()
+}
+
+// GetID returns the id field value, numeric and UUID ids are converted by FormatID.
+func (tr taggedResource) GetID() string {
+	field, ok := fieldByIndex(tr.value, tr.tagged.id)
+	if !ok {
+		return ""
+	}
+
+	id, _ := FormatID(field.Interface())
+
+	return id
+}
+
+func (tr taggedResource) GetLID() string {
+	return tr.stringField(tr.tagged.lid)
+}
+
+func (tr taggedResource) GetType() string {
+	if tr.tagged.typ != nil {
+		return tr.stringField(tr.tagged.typ)
+	}
+
+	return tr.tagged.typeName
+}
+
+func (tr taggedResource) GetRelationships() map[string]interface{} {
+	relationships := make(map[string]interface{}, len(tr.tagged.relationships))
+
+	for _, rel := range tr.tagged.relationships {
+		field, ok := fieldByIndex(tr.value, rel.index)
+		if !ok {
+			continue
+		}
+
+		relationships[rel.name] = taggedRelationshipValue(field, rel.typeName)
+	}
+
+	return relationships
+}
+
+func taggedRelationshipValue(field reflect.Value, typeName string) interface{} {
+	switch field.Kind() {
+	case reflect.String:
+		return ResourceObjectIdentifier{Type: typeName, ID: field.String()}
+	case reflect.Ptr:
+		// nil is marshaled as empty to-one relationship, regardless of Options.EmptyID
+		if field.IsNil() {
+			return nil
+		}
+
+		return field.Elem().Interface()
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.String {
+			identifiers := make([]ResourceObjectIdentifier, 0, field.Len())
+
+			for i := 0; i < field.Len(); i++ {
+				identifiers = append(identifiers, ResourceObjectIdentifier{Type: typeName, ID: field.Index(i).String()})
+			}
+
+			return identifiers
+		}
+	}
+
+	return field.Interface()
+}
+
+func (tr taggedResource) GetMeta() interface{} {
+	if mm, ok := tr.value.Interface().(MarshalMeta); ok {
+		return mm.GetMeta()
+	}
+
+	return struct{}{}
+}
+
+func (tr taggedResource) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	err := enc.Encode(tr.value.Interface())
+
+	return buf.Bytes(), err
+}
+
+func (tr taggedResource) unwrap() interface{} {
+	return tr.value.Interface()
+}
+
+func (tr taggedResource) rewrap(value interface{}) MarshalResourceIdentifier {
+	return taggedResource{value: reflect.ValueOf(value), tagged: tr.tagged}
+}
+
+// taggedTarget implements the Unmarshal interfaces for pointers to tagged struct types.
+type taggedTarget struct {
+	value  reflect.Value
+	tagged *taggedStruct
+}
+
+func newTaggedTarget(v interface{}) (*taggedTarget, bool) {
+	value := reflect.ValueOf(v)
+
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil, false
+	}
+
+	ts, ok := taggedStructOf(value.Type())
+	if !ok {
+		return nil, false
+	}
+
+	return &taggedTarget{value: value.Elem(), tagged: ts}, true
+}
+
+func (tt *taggedTarget) setString(index []int, s string) error {
+	if index == nil {
+		return nil
+	}
+
+	field := tt.value.FieldByIndex(index)
+
+	if field.Kind() != reflect.String {
+		return fmt.Errorf("jsonapi: %s field %s must be a string", tt.value.Type(), tt.value.Type().FieldByIndex(index).Name)
+	}
+
+	field.SetString(s)
+
+	return nil
+}
+
+// SetID sets the id field value, numeric and UUID ids are converted as ParseID does.
+func (tt *taggedTarget) SetID(id string) error {
+	return setID(tt.value.FieldByIndex(tt.tagged.id), id)
+}
+
+func (tt *taggedTarget) SetLID(lid string) error {
+	return tt.setString(tt.tagged.lid, lid)
+}
+
+func (tt *taggedTarget) SetType(typ string) error {
+	return tt.setString(tt.tagged.typ, typ)
+}
+
+func (tt *taggedTarget) SetRelationships(relationships map[string]interface{}) error {
+	for _, rel := range tt.tagged.relationships {
+		value, ok := relationships[rel.name]
+		if !ok {
+			continue
+		}
+
+		if err := setTaggedRelationship(tt.value.FieldByIndex(rel.index), value); err != nil {
+			return fmt.Errorf("%w: %q relationship", err, rel.name)
+		}
+	}
+
+	if ur, ok := tt.value.Addr().Interface().(UnmarshalRelationships); ok {
+		return ur.SetRelationships(relationships)
+	}
+
+	return nil
+}
+
+func (tt *taggedTarget) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, tt.value.Addr().Interface())
+}
+
+func (tt *taggedTarget) unwrap() interface{} {
+	return tt.value.Addr().Interface()
+}
+
+func setTaggedRelationship(field reflect.Value, value interface{}) error {
+	switch linkage := value.(type) {
+	case *ResourceObjectIdentifier:
+		return setTaggedRelated(field, linkage)
+	case []*ResourceObjectIdentifier:
+		if field.Kind() != reflect.Slice {
+			return fmt.Errorf("jsonapi: to-many relationship can't be set into %s", field.Type())
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(linkage), len(linkage))
+
+		for i, roi := range linkage {
+			if err := setTaggedRelated(slice.Index(i), roi); err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+
+		return nil
+	}
+
+	resolved := reflect.ValueOf(value)
+
+	if resolved.Type().AssignableTo(field.Type()) {
+		field.Set(resolved)
+	}
+
+	return nil
+}
+
+func setTaggedRelated(field reflect.Value, roi *ResourceObjectIdentifier) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(roi.ID)
+
+		return nil
+	case reflect.Ptr:
+		related := reflect.New(field.Type().Elem())
+
+		if err := setIdentifier(related.Interface(), roi); err != nil {
+			return err
+		}
+
+		field.Set(related)
+
+		return nil
+	case reflect.Struct:
+		related := reflect.New(field.Type())
+
+		if err := setIdentifier(related.Interface(), roi); err != nil {
+			return err
+		}
+
+		field.Set(related.Elem())
+
+		return nil
+	}
+
+	return fmt.Errorf("jsonapi: to-one relationship can't be set into %s", field.Type())
+}
+
+func setIdentifier(target interface{}, roi *ResourceObjectIdentifier) error {
+	ui, err := unmarshalTarget(target)
+	if err != nil {
+		return err
+	}
+
+	if err := ui.SetID(roi.ID); err != nil {
+		return err
+	}
+
+	if ul, ok := ui.(UnmarshalLocalIdentifier); ok {
+		if err := ul.SetLID(roi.LID); err != nil {
+			return err
+		}
+	}
+
+	return ui.SetType(roi.Type)
+}
+
+// unmarshalTarget returns target itself when it implements UnmarshalResourceIdentifier,
+// so the interfaces take precedence over tags, tagged target wrapper otherwise.
+func unmarshalTarget(target interface{}) (UnmarshalResourceIdentifier, error) {
+	if ui, ok := target.(UnmarshalResourceIdentifier); ok {
+		return ui, nil
+	}
+
+	if tt, ok := newTaggedTarget(target); ok {
+		return tt, nil
+	}
+
+	return nil, fmt.Errorf("jsonapi: %T does not implement UnmarshalResourceIdentifier", target)
+}
+
+// wrappedResource is implemented by the wrappers which make values usable as resources.
+type wrappedResource interface {
+	unwrap() interface{}
+}
+
+// rewrappedResource is implemented by the marshal wrappers which can wrap another value of the same type,
+// e.g. the copy BeforeMarshal hook has changed.
+type rewrappedResource interface {
+	rewrap(value interface{}) MarshalResourceIdentifier
+}
+
+// resourceType returns Go type of the resource value, the wrapped value type for the wrappers.
+func resourceType(v interface{}) reflect.Type {
+	return reflect.TypeOf(unwrapResource(v))
+}
+
+// unwrapResource returns the wrapped value for the wrappers, v itself otherwise.
+func unwrapResource(v interface{}) interface{} {
+	if wr, ok := v.(wrappedResource); ok {
+		return wr.unwrap()
+	}
+
+	return v
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Novel struct {
+	ID        string    `json:"-" jsonapi:"id,type=novels"`
+	Title     string    `json:"title"`
+	AuthorID  string    `json:"-" jsonapi:"relationship=author,type=authors"`
+	ReaderIDs []string  `json:"-" jsonapi:"relationship=readers,type=people"`
+	Editor    *Novelist `json:"-" jsonapi:"relationship=editor"`
+}
+
+type Novelist struct {
+	ID   string `json:"-" jsonapi:"id"`
+	Type string `json:"-" jsonapi:"type"`
+	Name string `json:"name"`
+}
+
+type NovelView struct {
+	Novel Novel
+}
+
+func (v NovelView) GetData() interface{} {
+	return v.Novel
+}
+
+func (v *NovelView) SetData(to func(target interface{}) error) error {
+	return to(&v.Novel)
+}
+
+type NovelsView struct {
+	Novels []*Novel
+}
+
+func (v *NovelsView) SetData(to func(target interface{}) error) error {
+	return to(&v.Novels)
+}
+
+type Saga struct {
+	ID    string `json:"-" jsonapi:"id,type=sagas"`
+	Title string `json:"title"`
+}
+
+func (s Saga) GetCacheHints() CacheHints {
+	return CacheHints{MaxAge: time.Minute, SurrogateKeys: []string{"sagas:" + s.ID}}
+}
+
+func (s Saga) GetLID() string {
+	return "saga-" + s.ID
+}
+
+var _ = Describe("Tagged structs", func() {
+
+	novel := Novel{
+		ID:        "1",
+		Title:     "Dune",
+		AuthorID:  "2",
+		ReaderIDs: []string{"3", "4"},
+		Editor:    &Novelist{ID: "5", Type: "editors"},
+	}
+
+	expected := `
+    {
+      "data": {
+        "type": "novels",
+        "id": "1",
+        "attributes": {
+          "title": "Dune"
+        },
+        "relationships": {
+          "author": {
+            "data": { "type": "authors", "id": "2" }
+          },
+          "readers": {
+            "data": [
+              { "type": "people", "id": "3" },
+              { "type": "people", "id": "4" }
+            ]
+          },
+          "editor": {
+            "data": { "type": "editors", "id": "5" }
+          }
+        }
+      }
+    }
+  `
+
+	It("marshals tagged struct", func() {
+		result, err := Marshal(NovelView{Novel: novel})
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("unmarshals tagged struct", func() {
+		view := NovelView{}
+
+		_, err := Unmarshal([]byte(expected), &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Novel).Should(Equal(Novel{
+			ID:        "1",
+			Title:     "Dune",
+			AuthorID:  "2",
+			ReaderIDs: []string{"3", "4"},
+			Editor:    &Novelist{ID: "5", Type: "editors"},
+		}))
+	})
+
+	It("unmarshals collection of tagged structs", func() {
+		view := NovelsView{}
+
+		_, err := Unmarshal([]byte(`{"data": [{"type": "novels", "id": "1", "attributes": {"title": "Dune"}}]}`), &view)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Novels).Should(Equal([]*Novel{{ID: "1", Title: "Dune"}}))
+	})
+
+	It("marshals nil related tagged struct as null", func() {
+		result, err := Marshal(NovelView{Novel: Novel{ID: "1", Title: "Dune"}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`
+      {
+        "data": {
+          "type": "novels",
+          "id": "1",
+          "attributes": { "title": "Dune" },
+          "relationships": {
+            "author": { "data": null },
+            "readers": { "data": [] },
+            "editor": { "data": null }
+          }
+        }
+      }
+    `))
+	})
+
+	It("marshals nil related tagged struct as null regardless of empty id policy", func() {
+		view := NovelView{Novel: Novel{ID: "1", Title: "Dune", AuthorID: "2"}}
+
+		for _, policy := range []EmptyIDPolicy{EmptyIDNull, EmptyIDKeep, EmptyIDError} {
+			result, err := Marshal(view, WithEmptyID(policy))
+
+			Ω(err).ShouldNot(HaveOccurred(), strconv.Itoa(int(policy)))
+			Ω(result).Should(MatchJSON(`
+        {
+          "data": {
+            "type": "novels",
+            "id": "1",
+            "attributes": { "title": "Dune" },
+            "relationships": {
+              "author": { "data": { "type": "authors", "id": "2" } },
+              "readers": { "data": [] },
+              "editor": { "data": null }
+            }
+          }
+        }
+      `), strconv.Itoa(int(policy)))
+		}
+	})
+
+	It("resolves optional interfaces of the tagged struct", func() {
+		result, err := Marshal(EpisodeView{Data: Saga{ID: "1", Title: "Foundation"}})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`
+      {
+        "data": {
+          "type": "sagas",
+          "id": "1",
+          "lid": "saga-1",
+          "attributes": {"title": "Foundation"},
+          "meta": {"cache": {"max_age": 60, "surrogate_keys": ["sagas:1"]}}
+        }
+      }
+    `))
+	})
+})
@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// tagName is the struct field tag key holding jsonapi attribute options, e.g. `jsonapi:"encrypted"`.
+// Several options are comma-separated, an option may have a value after equal sign,
+// the value is single-quoted when it contains commas, e.g. `jsonapi:"default='[1,2]'"`.
+const tagName = "jsonapi"
+
+// jsonField describes struct field visible as JSON object member.
+type jsonField struct {
+	// name JSON member name.
+	name string
+	// index field index sequence for reflect.Value.FieldByIndex.
+	index []int
+	// field struct field description.
+	field reflect.StructField
+	// tag parsed jsonapi tag options.
+	tag map[string]string
+}
+
+var jsonFieldsCache sync.Map
+
+// jsonFields returns JSON members of struct type by their names, fields of embedded structs are promoted.
+func jsonFields(typ reflect.Type) map[string]*jsonField {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if cached, ok := jsonFieldsCache.Load(typ); ok {
+		return cached.(map[string]*jsonField)
+	}
+
+	fields := map[string]*jsonField{}
+	collectJSONFields(typ, nil, fields)
+
+	jsonFieldsCache.Store(typ, fields)
+
+	return fields
+}
+
+func collectJSONFields(typ reflect.Type, index []int, fields map[string]*jsonField) {
+	var embedded []reflect.StructField
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && len(name) == 0 {
+			embedded = append(embedded, field)
+			continue
+		}
+
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		if _, ok := fields[name]; ok {
+			continue
+		}
+
+		fields[name] = &jsonField{
+			name:  name,
+			index: append(append([]int{}, index...), i),
+			field: field,
+			tag:   parseTag(field.Tag.Get(tagName)),
+		}
+	}
+
+	for _, field := range embedded {
+		fieldType := field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct {
+			collectJSONFields(fieldType, append(append([]int{}, index...), field.Index...), fields)
+		}
+	}
+}
+
+func parseTag(tag string) map[string]string {
+	options := map[string]string{}
+
+	if len(tag) == 0 {
+		return options
+	}
+
+	for _, option := range splitTag(tag) {
+		parts := strings.SplitN(option, "=", 2)
+
+		if len(parts) == 2 {
+			options[parts[0]] = unquoteTagValue(parts[1])
+		} else {
+			options[parts[0]] = ""
+		}
+	}
+
+	return options
+}
+
+// splitTag splits tag into comma-separated options, commas within single quotes don't separate them.
+func splitTag(tag string) []string {
+	var (
+		options []string
+		quoted  bool
+		start   int
+	)
+
+	for i, r := range tag {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			options = append(options, tag[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(options, tag[start:])
+}
+
+func unquoteTagValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+// taggedAttributes returns json names of the type fields having the jsonapi tag option.
+func taggedAttributes(typ reflect.Type, option string) []string {
+	var names []string
+
+	for name, field := range jsonFields(typ) {
+		if _, ok := field.tag[option]; ok {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
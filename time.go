@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// timeAttributes returns json names of the type fields holding time.Time or *time.Time.
+func timeAttributes(typ reflect.Type) []string {
+	var names []string
+
+	for name, field := range jsonFields(typ) {
+		fieldType := field.field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType == timeType {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// normalizeTimes converts time attributes into loc, the attributes must be RFC 3339 strings,
+// any other value is reported as AttributeError with pointer to the attribute under the given attributes pointer.
+func normalizeTimes(attributes json.RawMessage, typ reflect.Type, loc *time.Location, pointer string) (json.RawMessage, error) {
+	names := timeAttributes(typ)
+	if len(names) == 0 || len(attributes) == 0 || bytes.Equal(attributes, []byte("{}\n")) {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for _, name := range names {
+			value, ok := members[name]
+			if !ok || bytes.Equal(value, []byte("null")) {
+				continue
+			}
+
+			var formatted string
+
+			if err := json.Unmarshal(value, &formatted); err != nil {
+				return &AttributeError{Pointer: pointer + Pointer(name), Err: errors.New("time must be a RFC 3339 string")}
+			}
+
+			t, err := time.Parse(time.RFC3339Nano, formatted)
+			if err != nil {
+				return &AttributeError{Pointer: pointer + Pointer(name), Err: fmt.Errorf("time must be formatted as RFC 3339: %q", formatted)}
+			}
+
+			normalized, err := json.Marshal(t.In(loc))
+			if err != nil {
+				return err
+			}
+
+			members[name] = normalized
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Event struct {
+	ID       string     `json:"-"`
+	Title    string     `json:"title"`
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+func (e Event) GetID() string {
+	return e.ID
+}
+
+func (e Event) GetType() string {
+	return "events"
+}
+
+func (e *Event) SetID(id string) error {
+	e.ID = id
+	return nil
+}
+
+func (e *Event) SetType(string) error {
+	return nil
+}
+
+type EventView struct {
+	Event Event
+}
+
+func (v EventView) GetData() interface{} {
+	return v.Event
+}
+
+func (v *EventView) SetData(to func(target interface{}) error) error {
+	return to(&v.Event)
+}
+
+type EventsView struct {
+	Events []Event
+}
+
+func (v *EventsView) SetData(to func(target interface{}) error) error {
+	return to(&v.Events)
+}
+
+var _ = Describe("Time zone normalization", func() {
+
+	kyiv := time.FixedZone("EET", 2*60*60)
+
+	It("marshals time attributes in the given location", func() {
+		endsAt := time.Date(2020, 5, 1, 14, 0, 0, 0, kyiv)
+
+		view := EventView{
+			Event: Event{
+				ID:       "1",
+				Title:    "Meetup",
+				StartsAt: time.Date(2020, 5, 1, 12, 0, 0, 0, kyiv),
+				EndsAt:   &endsAt,
+			},
+		}
+
+		result, err := MarshalWithOptions(view, Options{TimeZone: time.UTC})
+
+		expected := `
+      {
+        "data": {
+          "type": "events",
+          "id": "1",
+          "attributes": {
+            "title": "Meetup",
+            "starts_at": "2020-05-01T10:00:00Z",
+            "ends_at": "2020-05-01T12:00:00Z"
+          }
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("unmarshals time attributes in the given location", func() {
+		data := []byte(`{"data": {"type": "events", "id": "1", "attributes": {"starts_at": "2020-05-01T12:00:00+02:00", "ends_at": null}}}`)
+
+		view := EventView{}
+
+		_, err := UnmarshalWithOptions(data, &view, Options{TimeZone: time.UTC})
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Event.StartsAt.Location()).Should(Equal(time.UTC))
+		Ω(view.Event.StartsAt.Hour()).Should(Equal(10))
+		Ω(view.Event.EndsAt).Should(BeNil())
+	})
+
+	It("returns attribute error with pointer for malformed time", func() {
+		data := []byte(`
+      {
+        "data": [
+          {"type": "events", "id": "1", "attributes": {"starts_at": "2020-05-01T12:00:00Z"}},
+          {"type": "events", "id": "2", "attributes": {"starts_at": "May 1, 2020"}}
+        ]
+      }
+    `)
+
+		_, err := UnmarshalWithOptions(data, &EventsView{}, Options{TimeZone: time.UTC})
+
+		var attributeErr *AttributeError
+
+		Ω(errors.As(err, &attributeErr)).Should(BeTrue())
+		Ω(attributeErr.Pointer).Should(Equal("/data/1/attributes/starts_at"))
+		Ω(attributeErr.ErrorObject().Source.Pointer).Should(Equal("/data/1/attributes/starts_at"))
+		Ω(attributeErr.ErrorObject().Status).Should(Equal("400"))
+	})
+})
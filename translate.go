@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Translator interface should be implemented to localize user-facing string attributes for the request locale,
+// the attributes are tagged `jsonapi:"translatable"` or given by Options.Translatable.
+// Translate returns false when there is no translation, the attribute keeps its value then.
+//
+// Translate example:
+//
+//	func(c Catalog) Translate(locale, resourceType, attribute, value string) (string, bool) {
+//	  translated, ok := c[locale][value]
+//	  return translated, ok
+//	}
+type Translator interface {
+	Translate(locale, resourceType, attribute, value string) (string, bool)
+}
+
+// TranslatorFunc is an adapter to allow the use of ordinary functions as Translator.
+type TranslatorFunc func(locale, resourceType, attribute, value string) (string, bool)
+
+// Translate calls f(locale, resourceType, attribute, value).
+func (f TranslatorFunc) Translate(locale, resourceType, attribute, value string) (string, bool) {
+	return f(locale, resourceType, attribute, value)
+}
+
+func translatableAttributes(resourceType string, typ reflect.Type, opts *Options) []string {
+	return append(taggedAttributes(typ, "translatable"), opts.Translatable[resourceType]...)
+}
+
+// translateAttributes replaces translatable string attributes, and strings of translatable string arrays, with their translations.
+func translateAttributes(resourceType string, attributes json.RawMessage, typ reflect.Type, opts *Options) (json.RawMessage, error) {
+	names := translatableAttributes(resourceType, typ, opts)
+	if len(names) == 0 || isEmptyObject(attributes) {
+		return attributes, nil
+	}
+
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for _, name := range names {
+			value, ok := members[name]
+			if !ok {
+				continue
+			}
+
+			switch jsonDelim(value) {
+			case '[':
+				var values []string
+
+				if json.Unmarshal(value, &values) != nil {
+					continue
+				}
+
+				for i, s := range values {
+					if translated, ok := opts.Translator.Translate(opts.Locale, resourceType, name, s); ok {
+						values[i] = translated
+					}
+				}
+
+				raw, err := json.Marshal(values)
+				if err != nil {
+					return err
+				}
+
+				members[name] = raw
+			default:
+				var s string
+
+				if json.Unmarshal(value, &s) != nil {
+					continue
+				}
+
+				if translated, ok := opts.Translator.Translate(opts.Locale, resourceType, name, s); ok {
+					raw, err := json.Marshal(translated)
+					if err != nil {
+						return err
+					}
+
+					members[name] = raw
+				}
+			}
+		}
+
+		return nil
+	})
+}
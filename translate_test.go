@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Dish struct {
+	ID          string   `json:"-"`
+	Name        string   `json:"name" jsonapi:"translatable"`
+	Allergens   []string `json:"allergens" jsonapi:"translatable"`
+	Description *string  `json:"description"`
+	Code        string   `json:"code"`
+}
+
+func (d Dish) GetID() string {
+	return d.ID
+}
+
+func (d Dish) GetType() string {
+	return "dishes"
+}
+
+type DishView struct {
+	Dish Dish
+}
+
+func (v DishView) GetData() interface{} {
+	return v.Dish
+}
+
+var _ = Describe("Translator", func() {
+
+	catalog := map[string]map[string]string{
+		"de": {"Soup": "Suppe", "nuts": "Nüsse", "Hot": "Scharf"},
+	}
+
+	translator := TranslatorFunc(func(locale, resourceType, attribute, value string) (string, bool) {
+		translated, ok := catalog[locale][value]
+		return translated, ok
+	})
+
+	description := "Hot"
+	view := DishView{Dish: Dish{ID: "1", Name: "Soup", Allergens: []string{"nuts", "milk"}, Description: &description, Code: "Soup"}}
+
+	It("translates translatable attributes for the locale", func() {
+		result, err := Marshal(view, WithTranslator("de", translator, nil))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "dishes", "id": "1", "attributes": {
+			"name": "Suppe", "allergens": ["Nüsse", "milk"], "description": "Hot", "code": "Soup"
+		}}}`))
+	})
+
+	It("translates attributes given by options", func() {
+		result, err := Marshal(view, WithTranslator("de", translator, map[string][]string{"dishes": {"description"}}))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(ContainSubstring(`"description":"Scharf"`))
+	})
+
+	It("keeps the values without translations", func() {
+		result, err := Marshal(view, WithTranslator("fr", translator, nil))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(ContainSubstring(`"name":"Soup"`))
+	})
+})
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+)
+
+// TypedResource is resource object with attributes decoded into T, it keeps identifier, meta, links and
+// relationships of the resource object, for handlers which want typed attributes without writing a view.
+//
+// TypedResource example:
+//
+//	var book jsonapi.TypedResource[BookAttributes]
+//
+//	if err := book.DecodeAttributes(doc.Data.One); err != nil {
+//	  ...
+//	}
+//
+//	book.Attributes.Title = "Updated"
+//
+//	ro, err := book.EncodeAttributes()
+type TypedResource[T any] struct {
+	ResourceObjectIdentifier
+	// Attributes the resource attributes.
+	Attributes T
+	// Meta the resource meta raw data.
+	Meta json.RawMessage
+	// Links the resource links.
+	Links Links
+
+	relationships map[string]*relationship
+}
+
+// TypedResourceOf returns typed resource of the resource object, see TypedResource.DecodeAttributes.
+func TypedResourceOf[T any](ro *ResourceObject) (TypedResource[T], error) {
+	var r TypedResource[T]
+
+	err := r.DecodeAttributes(ro)
+
+	return r, err
+}
+
+// DecodeAttributes sets the resource out of the resource object, decoding its attributes into T.
+// Missing attributes leave T zero value.
+func (r *TypedResource[T]) DecodeAttributes(ro *ResourceObject) error {
+	var attributes T
+
+	if len(ro.Attributes) > 0 {
+		if err := json.Unmarshal(ro.Attributes, &attributes); err != nil {
+			return err
+		}
+	}
+
+	*r = TypedResource[T]{
+		ResourceObjectIdentifier: ro.ResourceObjectIdentifier,
+		Attributes:               attributes,
+		Meta:                     ro.Meta,
+		Links:                    ro.Links,
+		relationships:            ro.Relationships,
+	}
+
+	return nil
+}
+
+// EncodeAttributes returns resource object of the resource, encoding T as its attributes.
+// Relationships of the decoded resource object are kept.
+func (r TypedResource[T]) EncodeAttributes() (*ResourceObject, error) {
+	attributes, err := json.Marshal(r.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(attributes) == "{}" || string(attributes) == "null" {
+		attributes = nil
+	}
+
+	return &ResourceObject{
+		ResourceObjectIdentifier: r.ResourceObjectIdentifier,
+		Attributes:               attributes,
+		Meta:                     r.Meta,
+		Relationships:            r.relationships,
+		Links:                    r.Links,
+	}, nil
+}
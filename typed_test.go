@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type BookAttributes struct {
+	Title string `json:"title"`
+	Year  int    `json:"year,omitempty"`
+}
+
+var _ = Describe("TypedResource", func() {
+
+	payload := []byte(`{
+		"data": {
+			"type": "books",
+			"id": "1",
+			"attributes": {"title": "Dune", "year": 1965},
+			"meta": {"rank": 1},
+			"relationships": {"author": {"data": {"type": "authors", "id": "2"}}},
+			"links": {"self": "/books/1"}
+		}
+	}`)
+
+	It("decodes attributes keeping the rest of resource object", func() {
+		doc := Document{}
+		Ω(json.Unmarshal(payload, &doc)).Should(Succeed())
+
+		book, err := TypedResourceOf[BookAttributes](doc.Data.One)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(book.Type).Should(Equal("books"))
+		Ω(book.ID).Should(Equal("1"))
+		Ω(book.Attributes).Should(Equal(BookAttributes{Title: "Dune", Year: 1965}))
+		Ω(book.Meta).Should(MatchJSON(`{"rank": 1}`))
+		Ω(book.Links).Should(HaveKey("self"))
+	})
+
+	It("encodes attributes back into resource object", func() {
+		doc := Document{}
+		Ω(json.Unmarshal(payload, &doc)).Should(Succeed())
+
+		var book TypedResource[BookAttributes]
+		Ω(book.DecodeAttributes(doc.Data.One)).Should(Succeed())
+
+		book.Attributes.Title = "Dune Messiah"
+		book.Attributes.Year = 0
+
+		ro, err := book.EncodeAttributes()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		result, err := json.Marshal(ro)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{
+			"type": "books",
+			"id": "1",
+			"attributes": {"title": "Dune Messiah"},
+			"meta": {"rank": 1},
+			"relationships": {"author": {"data": {"type": "authors", "id": "2"}}},
+			"links": {"self": "/books/1"}
+		}`))
+	})
+
+	It("fails on attributes not matching T", func() {
+		_, err := TypedResourceOf[BookAttributes](&ResourceObject{Attributes: json.RawMessage(`{"title": 1}`)})
+
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,147 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidUUID returned when UUID is malformed, the error is wrapped with the details.
+var ErrInvalidUUID = errors.New("jsonapi: invalid UUID")
+
+// UUID is RFC 4122 UUID, it is formatted in canonical lowercase form, e.g. "6ba7b810-9dad-11d1-80b4-00c04fd430c8".
+// The type has the layout of uuid.UUID-style types, so they convert into each other, e.g. jsonapi.UUID(u).
+type UUID [16]byte
+
+// NewUUID returns random (version 4) UUID.
+func NewUUID() (UUID, error) {
+	var u UUID
+
+	if _, err := rand.Read(u[:]); err != nil {
+		return u, err
+	}
+
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+
+	return u, nil
+}
+
+// ParseUUID parses UUID in canonical, braced "{...}", URN "urn:uuid:..." or 32 hex digits form, case-insensitively.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	text := s
+
+	switch {
+	case len(text) == 45 && strings.EqualFold(text[:9], "urn:uuid:"):
+		text = text[9:]
+	case len(text) == 38 && text[0] == '{' && text[37] == '}':
+		text = text[1:37]
+	}
+
+	switch len(text) {
+	case 36:
+		if text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+			return u, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+		}
+
+		text = text[:8] + text[9:13] + text[14:18] + text[19:23] + text[24:]
+	case 32:
+	default:
+		return u, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+
+	if _, err := hex.Decode(u[:], []byte(text)); err != nil {
+		return UUID{}, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+
+	return u, nil
+}
+
+// IsZero returns true for nil UUID.
+func (u UUID) IsZero() bool {
+	return u == UUID{}
+}
+
+// String returns canonical form of the UUID.
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf)
+}
+
+// MarshalText marshals canonical form of the UUID, so the UUID attributes are marshaled as JSON strings.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText unmarshals UUID in any form ParseUUID accepts, it fails when the UUID is malformed.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+
+	*u = parsed
+
+	return nil
+}
+
+// UUIDResource is embeddable base of UUID-keyed resources, it implements GetID and SetID.
+// Nil UUID is marshaled as empty resource ID, malformed resource ID fails Unmarshal.
+//
+// UUIDResource example:
+//
+//	type Order struct {
+//	  jsonapi.UUIDResource
+//	  Total int `json:"total"`
+//	}
+//
+//	func(o Order) GetType() string {
+//	  return "orders"
+//	}
+type UUIDResource struct {
+	ID UUID `json:"-"`
+}
+
+// GetID returns canonical form of the resource UUID, empty string for nil UUID.
+func (r UUIDResource) GetID() string {
+	if r.ID.IsZero() {
+		return ""
+	}
+
+	return r.ID.String()
+}
+
+// SetID parses resource UUID, empty ID leaves it unchanged.
+func (r *UUIDResource) SetID(id string) error {
+	if len(id) == 0 {
+		return nil
+	}
+
+	u, err := ParseUUID(id)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidID, err)
+	}
+
+	r.ID = u
+
+	return nil
+}
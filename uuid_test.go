@@ -0,0 +1,131 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Shipment struct {
+	UUIDResource
+	Carrier string `json:"carrier"`
+	Parcel  UUID   `json:"parcel"`
+}
+
+func (s Shipment) GetType() string {
+	return "shipments"
+}
+
+func (s *Shipment) SetType(string) error {
+	return nil
+}
+
+func (s *Shipment) SetData(to func(target interface{}) error) error {
+	return to(s)
+}
+
+type ShipmentView struct {
+	Data Shipment
+}
+
+func (v ShipmentView) GetData() interface{} {
+	return v.Data
+}
+
+var _ = Describe("UUID", func() {
+	const canonical = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	Describe("ParseUUID", func() {
+		It("accepts canonical, uppercase, braced, URN and hex digits forms", func() {
+			for _, s := range []string{
+				canonical,
+				"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+				"{" + canonical + "}",
+				"urn:uuid:" + canonical,
+				"6ba7b8109dad11d180b400c04fd430c8",
+			} {
+				u, err := ParseUUID(s)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(u.String()).Should(Equal(canonical))
+			}
+		})
+
+		It("fails on malformed UUIDs", func() {
+			for _, s := range []string{
+				"",
+				"6ba7b810-9dad-11d1-80b4",
+				"6ba7b8109-dad-11d1-80b4-00c04fd430c8",
+				"zba7b810-9dad-11d1-80b4-00c04fd430c8",
+			} {
+				_, err := ParseUUID(s)
+				Ω(err).Should(MatchError(ErrInvalidUUID))
+			}
+		})
+	})
+
+	It("generates random version 4 UUIDs", func() {
+		u, err := NewUUID()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(u.IsZero()).Should(BeFalse())
+		Ω(u.String()[14]).Should(Equal(byte('4')))
+		Ω(u.String()[19]).Should(BeElementOf(byte('8'), byte('9'), byte('a'), byte('b')))
+
+		other, err := NewUUID()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(other).ShouldNot(Equal(u))
+	})
+
+	It("is formatted as resource id by FormatID, nil UUID gives empty id", func() {
+		u, _ := ParseUUID(canonical)
+
+		Ω(FormatID(u)).Should(Equal(canonical))
+		Ω(FormatID(UUID{})).Should(BeEmpty())
+	})
+
+	Describe("UUIDResource", func() {
+		It("marshals canonical resource id and UUID attributes", func() {
+			id, _ := ParseUUID("{6BA7B810-9DAD-11D1-80B4-00C04FD430C8}")
+			parcel, _ := ParseUUID("urn:uuid:6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+
+			payload, err := Marshal(ShipmentView{Data: Shipment{UUIDResource: UUIDResource{ID: id}, Carrier: "DHL", Parcel: parcel}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(payload).Should(MatchJSON(`
+				{
+					"data": {
+						"type": "shipments",
+						"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+						"attributes": {"carrier": "DHL", "parcel": "6ba7b811-9dad-11d1-80b4-00c04fd430c8"}
+					}
+				}
+			`))
+		})
+
+		It("unmarshals and validates resource id", func() {
+			var shipment Shipment
+
+			_, err := Unmarshal([]byte(`{"data": {"type": "shipments", "id": "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", "attributes": {"carrier": "DHL", "parcel": "6ba7b811-9dad-11d1-80b4-00c04fd430c8"}}}`), &shipment)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(shipment.GetID()).Should(Equal(canonical))
+			Ω(shipment.Parcel.String()).Should(Equal("6ba7b811-9dad-11d1-80b4-00c04fd430c8"))
+
+			_, err = Unmarshal([]byte(`{"data": {"type": "shipments", "id": "42", "attributes": {"carrier": "DHL"}}}`), &Shipment{})
+			Ω(err).Should(MatchError(ErrInvalidID))
+		})
+
+		It("fails on malformed UUID attributes", func() {
+			_, err := Unmarshal([]byte(`{"data": {"type": "shipments", "id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "attributes": {"parcel": "42"}}}`), &Shipment{})
+			Ω(err).Should(MatchError(ErrInvalidUUID))
+		})
+
+		It("marshals nil UUID as empty resource id", func() {
+			Ω(Shipment{}.GetID()).Should(BeEmpty())
+		})
+	})
+})
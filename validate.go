@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+	"strings"
+)
+
+// ResourceValidator interface may be implemented to validate the resource, e.g. across several fields,
+// after its attributes and relationships are set on Unmarshal.
+// Error objects source pointers may be relative to the resource, e.g. "/attributes/title",
+// Unmarshal prefixes them with the resource pointer, e.g. "/data/1/attributes/title",
+// and returns the errors as ValidationError.
+//
+// ValidateResource example:
+//
+//	func (e *Event) ValidateResource(ctx context.Context) []*jsonapi.ErrorObject {
+//	  if e.EndsAt.Before(e.StartsAt) {
+//	    return []*jsonapi.ErrorObject{{
+//	      Status: "422",
+//	      Title:  "must not be before starts_at",
+//	      Source: jsonapi.ErrorObjectSource{Pointer: "/attributes/ends_at"},
+//	    }}
+//	  }
+//
+//	  return nil
+//	}
+type ResourceValidator interface {
+	ValidateResource(ctx context.Context) []*ErrorObject
+}
+
+func validateResource(target interface{}, pointer string, opts *Options) error {
+	rv, ok := unwrapResource(target).(ResourceValidator)
+	if !ok {
+		return nil
+	}
+
+	errs := rv.ValidateResource(optionsContext(opts))
+	if len(errs) == 0 {
+		return nil
+	}
+
+	enriched := make([]*ErrorObject, 0, len(errs))
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		copied := *err
+		copied.Source.Pointer = resourcePointer(pointer, err.Source.Pointer)
+
+		enriched = append(enriched, &copied)
+	}
+
+	if len(enriched) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: enriched}
+}
+
+// resourcePointer prefixes pointer relative to the resource with the resource pointer,
+// pointers to the document members are returned as is.
+func resourcePointer(resource, pointer string) string {
+	if strings.HasPrefix(pointer, "/data") || strings.HasPrefix(pointer, "/included") {
+		return pointer
+	}
+
+	if len(pointer) > 0 && !strings.HasPrefix(pointer, "/") {
+		pointer = "/" + pointer
+	}
+
+	return resource + pointer
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type contextKey struct{}
+
+type Booking struct {
+	ID     string `json:"-"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	RoomID string `json:"-"`
+}
+
+func (b *Booking) SetID(id string) error {
+	b.ID = id
+	return nil
+}
+
+func (b *Booking) SetType(string) error {
+	return nil
+}
+
+func (b *Booking) SetRelationships(relationships map[string]interface{}) error {
+	if room, ok := relationships["room"].(*ResourceObjectIdentifier); ok {
+		b.RoomID = room.ID
+	}
+
+	return nil
+}
+
+func (b *Booking) ValidateResource(ctx context.Context) []*ErrorObject {
+	var errs []*ErrorObject
+
+	if b.To <= b.From {
+		errs = append(errs, &ErrorObject{
+			Status: "422",
+			Title:  "must be after from",
+			Source: ErrorObjectSource{Pointer: "/attributes/to"},
+		})
+	}
+
+	if len(b.RoomID) == 0 {
+		errs = append(errs, &ErrorObject{
+			Status: "422",
+			Title:  ctx.Value(contextKey{}).(string),
+			Source: ErrorObjectSource{Pointer: "relationships/room"},
+		})
+	}
+
+	return errs
+}
+
+type BookingsView struct {
+	Bookings []*Booking
+}
+
+func (v *BookingsView) SetData(to func(target interface{}) error) error {
+	return to(&v.Bookings)
+}
+
+var _ = Describe("ResourceValidator", func() {
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "is required")
+
+	It("unmarshals valid resources", func() {
+		data := []byte(`{"data": [{"type": "bookings", "id": "1", "attributes": {"from": 1, "to": 2}, "relationships": {"room": {"data": {"type": "rooms", "id": "1"}}}}]}`)
+
+		view := BookingsView{}
+
+		_, err := Unmarshal(data, &view, WithContext(ctx))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(view.Bookings).Should(HaveLen(1))
+	})
+
+	It("returns validation errors with resource pointers", func() {
+		data := []byte(`
+      {
+        "data": [
+          {"type": "bookings", "id": "1", "attributes": {"from": 2, "to": 1}, "relationships": {"room": {"data": {"type": "rooms", "id": "1"}}}},
+          {"type": "bookings", "id": "2", "attributes": {"from": 1, "to": 2}},
+          {"type": "bookings", "id": "3", "attributes": {"from": 1, "to": 2}, "relationships": {"room": {"data": {"type": "rooms", "id": "1"}}}}
+        ]
+      }
+    `)
+
+		_, err := Unmarshal(data, &BookingsView{}, WithContext(ctx))
+
+		var validationErr *ValidationError
+
+		Ω(errors.As(err, &validationErr)).Should(BeTrue())
+		Ω(validationErr.Errors).Should(HaveLen(2))
+		Ω(validationErr.Errors[0].Source.Pointer).Should(Equal("/data/0/attributes/to"))
+		Ω(validationErr.Errors[1].Source.Pointer).Should(Equal("/data/1/relationships/room"))
+		Ω(validationErr.Errors[1].Title).Should(Equal("is required"))
+	})
+})
@@ -0,0 +1,164 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes failed validation of a struct field, it is implemented by go-playground/validator FieldError,
+// so the library doesn't depend on the validator package.
+type FieldError interface {
+	// Tag validation tag which failed, e.g. "min".
+	Tag() string
+	// Param validation tag parameter, e.g. "8" of "min=8".
+	Param() string
+	// StructNamespace struct field namespace, e.g. "User.Address.City" or "User.Tags[0]".
+	StructNamespace() string
+	// Error returns error message.
+	Error() string
+}
+
+// ErrorsFromValidator returns 422 error objects describing failed validations of err, e.g. validator.ValidationErrors,
+// with source pointers to the resource attributes, e.g. "/data/attributes/address/city".
+// Struct field names are replaced with the attribute names of resource, which is the validated struct,
+// they are kept as is when resource is nil. Validation tag is the error object code, its parameter is carried in meta,
+// see ParamsMeta. It returns nil when err has no failed validations.
+//
+// ErrorsFromValidator example:
+//
+//	if err := validate.Struct(user); err != nil {
+//	  return jsonapi.WriteErrors(w, r, jsonapi.ErrorsFromValidator(err, user))
+//	}
+func ErrorsFromValidator(err error, resource interface{}) []*ErrorObject {
+	var typ reflect.Type
+
+	if resource != nil {
+		typ = reflect.TypeOf(resource)
+	}
+
+	var errs []*ErrorObject
+
+	for _, fe := range fieldErrors(err) {
+		pointer := validatorPointer(fe.StructNamespace(), typ)
+
+		tag := fe.Tag()
+		if len(fe.Param()) > 0 {
+			tag += "=" + fe.Param()
+		}
+
+		eo := &ErrorObject{
+			Status: strconv.Itoa(http.StatusUnprocessableEntity),
+			Title:  "Invalid attribute",
+			Detail: fmt.Sprintf("Attribute %s failed on the %q validation.", strings.TrimPrefix(pointer, "/data/attributes/"), tag),
+			Code:   fe.Tag(),
+			Source: ErrorObjectSource{Pointer: pointer},
+		}
+
+		if len(fe.Param()) > 0 {
+			eo.Meta = map[string]interface{}{ParamsMeta: map[string]interface{}{"param": fe.Param()}}
+		}
+
+		errs = append(errs, eo)
+	}
+
+	return errs
+}
+
+// fieldErrors returns field errors of err, the error itself or the elements of error slice,
+// e.g. validator.ValidationErrors, found in err chain.
+func fieldErrors(err error) []FieldError {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if fe, ok := err.(FieldError); ok {
+			return []FieldError{fe}
+		}
+
+		value := reflect.ValueOf(err)
+		if value.Kind() != reflect.Slice {
+			continue
+		}
+
+		var fes []FieldError
+
+		for i := 0; i < value.Len(); i++ {
+			if fe, ok := value.Index(i).Interface().(FieldError); ok {
+				fes = append(fes, fe)
+			}
+		}
+
+		if len(fes) > 0 {
+			return fes
+		}
+	}
+
+	return nil
+}
+
+// validatorPointer returns pointer to the attribute of struct field namespace, e.g. "User.Tags[0]",
+// the first namespace segment is the validated struct name.
+func validatorPointer(namespace string, typ reflect.Type) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 1 {
+		segments = segments[1:]
+	}
+
+	tokens := []string{"data", "attributes"}
+
+	for _, segment := range segments {
+		name, key := segment, ""
+
+		if i := strings.Index(segment, "["); i > 0 && strings.HasSuffix(segment, "]") {
+			name, key = segment[:i], segment[i+1:len(segment)-1]
+		}
+
+		member, next := name, reflect.Type(nil)
+
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		if typ != nil && typ.Kind() == reflect.Struct {
+			// embedded structs fields are promoted
+			if field, ok := typ.FieldByName(name); ok && field.Anonymous && len(key) == 0 {
+				continue
+			}
+
+			for jsonName, field := range jsonFields(typ) {
+				if field.field.Name == name {
+					member, next = jsonName, field.field.Type
+					break
+				}
+			}
+		}
+
+		tokens = append(tokens, member)
+
+		if len(key) > 0 {
+			tokens = append(tokens, key)
+
+			for next != nil && next.Kind() == reflect.Ptr {
+				next = next.Elem()
+			}
+
+			if next != nil {
+				switch next.Kind() {
+				case reflect.Slice, reflect.Array, reflect.Map:
+					next = next.Elem()
+				default:
+					next = nil
+				}
+			}
+		}
+
+		typ = next
+	}
+
+	return Pointer(tokens...)
+}
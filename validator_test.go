@@ -0,0 +1,155 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+// fieldError mimics go-playground/validator FieldError.
+type fieldError struct {
+	tag, param, namespace string
+}
+
+func (fe fieldError) Tag() string             { return fe.tag }
+func (fe fieldError) Param() string           { return fe.param }
+func (fe fieldError) StructNamespace() string { return fe.namespace }
+func (fe fieldError) Error() string           { return fe.namespace + " failed on " + fe.tag }
+
+type fieldErrorInterface interface {
+	Tag() string
+	Param() string
+	StructNamespace() string
+	Error() string
+}
+
+// validationErrors mimics go-playground/validator ValidationErrors.
+type validationErrors []fieldErrorInterface
+
+func (ve validationErrors) Error() string {
+	messages := make([]string, 0, len(ve))
+
+	for _, fe := range ve {
+		messages = append(messages, fe.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Audit struct {
+	CreatedBy string `json:"created_by"`
+}
+
+type Signup struct {
+	Audit
+	ID       string             `json:"-"`
+	Email    string             `json:"email_address"`
+	Password string             `json:"password"`
+	Address  *Address           `json:"address"`
+	Tags     []string           `json:"tags"`
+	Contacts map[string]Address `json:"contacts"`
+}
+
+var _ = Describe("Error constructors", func() {
+
+	It("creates error object", func() {
+		eo := NewError(422, "too_short", "is too short", "Password is too short.")
+
+		Ω(eo).Should(Equal(&ErrorObject{Status: "422", Code: "too_short", Title: "is too short", Detail: "Password is too short."}))
+	})
+
+	It("returns error objects as is", func() {
+		eo := NewError(409, "taken", "is taken", "")
+
+		Ω(ErrorFromErr(fmt.Errorf("create: %w", eo))).Should(BeIdenticalTo(eo))
+		Ω(ErrorFromErr(Errors{eo})).Should(BeIdenticalTo(eo))
+		Ω(ErrorFromErr(&ValidationError{Errors: []*ErrorObject{eo}})).Should(BeIdenticalTo(eo))
+		Ω(ErrorFromErr(nil)).Should(BeNil())
+	})
+
+	It("describes client errors", func() {
+		attributeErr := ErrorFromErr(&AttributeError{Pointer: "/data/attributes/starts_at", Err: fmt.Errorf("invalid time")})
+
+		Ω(attributeErr.Status).Should(Equal("400"))
+		Ω(attributeErr.Source.Pointer).Should(Equal("/data/attributes/starts_at"))
+
+		var target Recipe
+
+		_, err := Unmarshal([]byte(`{"data": `), &target)
+
+		Ω(ErrorFromErr(err).Status).Should(Equal("400"))
+		Ω(ErrorFromErr(ErrDocumentTooLarge).Status).Should(Equal("413"))
+	})
+
+	It("doesn't disclose other errors", func() {
+		eo := ErrorFromErr(fmt.Errorf("connection refused"))
+
+		Ω(eo).Should(Equal(&ErrorObject{Status: "500", Title: "Internal Server Error"}))
+	})
+})
+
+var _ = Describe("Validator errors", func() {
+
+	err := validationErrors{
+		fieldError{tag: "required", namespace: "Signup.Email"},
+		fieldError{tag: "min", param: "8", namespace: "Signup.Password"},
+		fieldError{tag: "required", namespace: "Signup.Address.City"},
+		fieldError{tag: "alpha", namespace: "Signup.Tags[1]"},
+		fieldError{tag: "required", namespace: "Signup.Contacts[home].City"},
+		fieldError{tag: "required", namespace: "Signup.Audit.CreatedBy"},
+	}
+
+	It("converts validation errors into error objects with attribute pointers", func() {
+		errs := ErrorsFromValidator(fmt.Errorf("validate: %w", err), Signup{})
+
+		pointers := make([]string, 0, len(errs))
+
+		for _, eo := range errs {
+			Ω(eo.Status).Should(Equal("422"))
+			pointers = append(pointers, eo.Source.Pointer)
+		}
+
+		Ω(pointers).Should(Equal([]string{
+			"/data/attributes/email_address",
+			"/data/attributes/password",
+			"/data/attributes/address/city",
+			"/data/attributes/tags/1",
+			"/data/attributes/contacts/home/city",
+			"/data/attributes/created_by",
+		}))
+
+		Ω(errs[1].Code).Should(Equal("min"))
+		Ω(errs[1].Detail).Should(Equal(`Attribute password failed on the "min=8" validation.`))
+		Ω(errs[1].Meta).Should(Equal(map[string]interface{}{ParamsMeta: map[string]interface{}{"param": "8"}}))
+	})
+
+	It("keeps struct field names without resource", func() {
+		errs := ErrorsFromValidator(err[:1], nil)
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Source.Pointer).Should(Equal("/data/attributes/Email"))
+	})
+
+	It("converts single field error", func() {
+		errs := ErrorsFromValidator(err[0], &Signup{})
+
+		Ω(errs).Should(HaveLen(1))
+		Ω(errs[0].Source.Pointer).Should(Equal("/data/attributes/email_address"))
+	})
+
+	It("returns nil without validation errors", func() {
+		Ω(ErrorsFromValidator(fmt.Errorf("boom"), Signup{})).Should(BeNil())
+		Ω(ErrorsFromValidator(nil, Signup{})).Should(BeNil())
+	})
+})
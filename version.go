@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JSON API specification versions, see Options.Version.
+const (
+	Version10 = "1.0"
+	Version11 = "1.1"
+)
+
+// ErrVersionFeature returned when a document uses a feature the target JSON API version doesn't support,
+// e.g. lid member in JSON API 1.0 mode.
+var ErrVersionFeature = errors.New("jsonapi: feature is not supported by JSON API 1.0")
+
+// checkVersion rejects JSON API 1.1 features in JSON API 1.0 mode: local identifiers, error source header,
+// error type links and extension members. The extension members are looked up in raw document data when it is given.
+func checkVersion(doc *Document, data []byte, version string) error {
+	switch version {
+	case "", Version11:
+		return nil
+	case Version10:
+	default:
+		return fmt.Errorf("jsonapi: unknown JSON API version %q", version)
+	}
+
+	for _, ro := range documentResources(doc) {
+		if len(ro.LID) > 0 {
+			return versionFeatureError("lid")
+		}
+
+		for name := range ro.Relationships {
+			for _, roi := range relationshipIdentifiers(ro, name) {
+				if len(roi.LID) > 0 {
+					return versionFeatureError("lid")
+				}
+			}
+		}
+	}
+
+	for _, err := range doc.Errors {
+		if len(err.Source.Header) > 0 {
+			return versionFeatureError("source.header")
+		}
+
+		if _, ok := err.Links["type"]; ok {
+			return versionFeatureError("error type link")
+		}
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	members := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	var extensions []string
+
+	for member := range members {
+		if strings.Contains(member, ":") {
+			extensions = append(extensions, member)
+		}
+	}
+
+	if len(extensions) > 0 {
+		sort.Strings(extensions)
+
+		return versionFeatureError(fmt.Sprintf("extension member %q", extensions[0]))
+	}
+
+	return nil
+}
+
+func versionFeatureError(feature string) error {
+	return fmt.Errorf("%w: %s", ErrVersionFeature, feature)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Version", func() {
+
+	localView := BookWithLocalAuthorView{
+		Book: BookWithLocalAuthor{
+			Book:      Book{Title: "Go in Action", Year: "2015", Type: "books"},
+			LID:       "book-1",
+			AuthorLID: "author-1",
+		},
+	}
+
+	It("marshals local identifiers by default", func() {
+		_, err := Marshal(localView)
+
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("rejects local identifiers on marshal in JSON API 1.0 mode", func() {
+		_, err := MarshalWithOptions(localView, Options{Version: Version10})
+
+		Ω(errors.Is(err, ErrVersionFeature)).Should(BeTrue())
+		Ω(err).Should(MatchError(ContainSubstring("lid")))
+	})
+
+	It("rejects error source header in JSON API 1.0 mode", func() {
+		view := ErrorsView{
+			ValidationErrors: []*ErrorObject{{Title: "is required", Source: ErrorObjectSource{Header: "X-Token"}}},
+		}
+
+		_, err := MarshalWithOptions(view, Options{Version: Version10})
+
+		Ω(errors.Is(err, ErrVersionFeature)).Should(BeTrue())
+	})
+
+	It("rejects error type links in JSON API 1.0 mode", func() {
+		view := ErrorsView{
+			ValidationErrors: []*ErrorObject{{Title: "is required", Links: Links{"type": {Href: "https://example.com/required"}}}},
+		}
+
+		_, err := MarshalWithOptions(view, Options{Version: Version10})
+
+		Ω(errors.Is(err, ErrVersionFeature)).Should(BeTrue())
+	})
+
+	It("rejects extension members on unmarshal in JSON API 1.0 mode", func() {
+		data := []byte(`{"data": {"type": "books", "id": "1"}, "version:id": "42"}`)
+
+		_, err := UnmarshalWithOptions(data, &BookView{}, Options{Version: Version10})
+
+		Ω(err).Should(MatchError(ContainSubstring(`extension member "version:id"`)))
+
+		_, err = UnmarshalWithOptions(data, &BookView{}, Options{Version: Version11})
+
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("rejects relationship local identifiers on unmarshal in JSON API 1.0 mode", func() {
+		data := []byte(`{"data": {"type": "books", "id": "1", "relationships": {"author": {"data": {"type": "authors", "lid": "a"}}}}}`)
+
+		_, err := UnmarshalWithOptions(data, &BookView{}, Options{Version: Version10})
+
+		Ω(errors.Is(err, ErrVersionFeature)).Should(BeTrue())
+	})
+
+	It("returns error for unknown version", func() {
+		_, err := MarshalWithOptions(BookView{Book: Book{ID: "1", Type: "books"}}, Options{Version: "2.0"})
+
+		Ω(err).Should(MatchError(`jsonapi: unknown JSON API version "2.0"`))
+	})
+})
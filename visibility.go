@@ -0,0 +1,65 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+)
+
+// AttributeVisibility reports whether the attribute of the resource type is visible to the caller,
+// hidden attributes are dropped on Marshal, see Options.Visibility.
+// Resource types and attribute names are given as the resource defines them, before Options.Naming and Options.TypeNaming.
+//
+// AttributeVisibility example:
+//
+//	func visibility(role string) jsonapi.AttributeVisibility {
+//	  return func(resourceType, attribute string) bool {
+//	    return role == "admin" || resourceType != "employees" || attribute != "salary"
+//	  }
+//	}
+//
+//	payload, err := jsonapi.Marshal(view, jsonapi.WithVisibility(visibility(user.Role)))
+type AttributeVisibility func(resourceType, attribute string) bool
+
+// HiddenAttributes returns AttributeVisibility hiding the given attribute names by resource type,
+// MaskAllTypes key hides the attributes of every type.
+//
+// HiddenAttributes example:
+//
+//	acl := map[string]jsonapi.AttributeVisibility{
+//	  "admin": nil,
+//	  "staff": jsonapi.HiddenAttributes(map[string][]string{"employees": {"salary"}}),
+//	  "guest": jsonapi.HiddenAttributes(map[string][]string{"employees": {"salary", "email"}}),
+//	}
+//
+//	payload, err := jsonapi.Marshal(view, jsonapi.WithVisibility(acl[user.Role]))
+func HiddenAttributes(hidden map[string][]string) AttributeVisibility {
+	index := make(map[string]map[string]bool, len(hidden))
+
+	for typ, names := range hidden {
+		index[typ] = make(map[string]bool, len(names))
+
+		for _, name := range names {
+			index[typ][name] = true
+		}
+	}
+
+	return func(resourceType, attribute string) bool {
+		return !index[resourceType][attribute] && !index[MaskAllTypes][attribute]
+	}
+}
+
+// hideAttributes removes attributes of the resource type the visibility hides.
+func hideAttributes(resourceType string, attributes json.RawMessage, visible AttributeVisibility) (json.RawMessage, error) {
+	return transformAttributes(attributes, func(members map[string]json.RawMessage) error {
+		for name := range members {
+			if !visible(resourceType, name) {
+				delete(members, name)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type Employee struct {
+	ID     string `json:"-"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Salary int    `json:"salary"`
+}
+
+func (e Employee) GetID() string {
+	return e.ID
+}
+
+func (e Employee) GetType() string {
+	return "employees"
+}
+
+func (e Employee) GetData() interface{} {
+	return e
+}
+
+var _ = Describe("Attribute visibility", func() {
+	employee := Employee{ID: "1", Name: "Jane", Email: "jane@example.com", Salary: 5000}
+
+	It("drops attributes hidden by visibility hook", func() {
+		visibility := func(resourceType, attribute string) bool {
+			return resourceType != "employees" || attribute != "salary"
+		}
+
+		result, err := Marshal(employee, WithVisibility(visibility))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "employees", "id": "1", "attributes": {
+			"name": "Jane", "email": "jane@example.com"
+		}}}`))
+	})
+
+	It("hides attributes by resource type", func() {
+		acl := map[string]AttributeVisibility{
+			"admin": nil,
+			"staff": HiddenAttributes(map[string][]string{"employees": {"salary"}}),
+			"guest": HiddenAttributes(map[string][]string{"employees": {"salary"}, MaskAllTypes: {"email"}}),
+		}
+
+		expected := map[string]string{
+			"admin": `{"name": "Jane", "email": "jane@example.com", "salary": 5000}`,
+			"staff": `{"name": "Jane", "email": "jane@example.com"}`,
+			"guest": `{"name": "Jane"}`,
+		}
+
+		for role, attributes := range expected {
+			result, err := Marshal(employee, WithVisibility(acl[role]))
+
+			Ω(err).ShouldNot(HaveOccurred(), role)
+			Ω(result).Should(MatchJSON(`{"data": {"type": "employees", "id": "1", "attributes": `+attributes+`}}`), role)
+		}
+	})
+
+	It("hides attributes before naming them", func() {
+		result, err := Marshal(employee, WithNaming(KebabCase), WithVisibility(HiddenAttributes(map[string][]string{"employees": {"salary", "email"}})))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "employees", "id": "1", "attributes": {"name": "Jane"}}}`))
+	})
+
+	It("omits attributes object when every attribute is hidden", func() {
+		result, err := Marshal(employee, WithVisibility(func(string, string) bool { return false }))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": {"type": "employees", "id": "1"}}`))
+	})
+})
@@ -0,0 +1,133 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// WatchMeta is the document meta member of watch endpoints, e.g. "meta": {"watch": {"token": "42"}}.
+const WatchMeta = "watch"
+
+// WatchTokenParam query parameter the client passes the last received watch token with, e.g. GET /books?watchToken=42.
+const WatchTokenParam = "watchToken"
+
+// WatchState describes "watch" document meta member.
+type WatchState struct {
+	// Token opaque token of the state the document brings the client to, it is passed with the next request.
+	Token string `json:"token"`
+	// Deleted identifiers of the resources deleted since the previous token.
+	Deleted []ResourceObjectIdentifier `json:"deleted,omitempty"`
+	// Heartbeat tells the document is meta-only one sent when nothing changed.
+	Heartbeat bool `json:"heartbeat,omitempty"`
+}
+
+// WatchView is a view of watch endpoint response, containing only the resources changed since the client token.
+//
+// WatchView example:
+//
+//	changed, deleted, token := store.ChangesSince(jsonapi.WatchToken(r))
+//
+//	payload, err := jsonapi.Marshal(jsonapi.Watch(changed, deleted, token))
+type WatchView struct {
+	// Data changed resources collection.
+	Data interface{}
+	// Included resources.
+	Included []interface{}
+	// Deleted identifiers of the deleted resources.
+	Deleted []ResourceObjectIdentifier
+	// Token the new watch token.
+	Token string
+}
+
+// Watch returns view of the changed resources collection and deleted resources identifiers, having the new watch token.
+func Watch(changed interface{}, deleted []ResourceObjectIdentifier, token string) WatchView {
+	return WatchView{Data: changed, Deleted: deleted, Token: token}
+}
+
+// GetData returns changed resources, empty collection when there are none.
+func (v WatchView) GetData() interface{} {
+	if v.Data == nil {
+		return []interface{}{}
+	}
+
+	return v.Data
+}
+
+// GetIncluded returns included resources.
+func (v WatchView) GetIncluded() []interface{} {
+	return v.Included
+}
+
+// GetMeta returns "watch" meta member.
+func (v WatchView) GetMeta() interface{} {
+	return map[string]interface{}{WatchMeta: WatchState{Token: v.Token, Deleted: v.Deleted}}
+}
+
+// HeartbeatView is a view of meta-only watch endpoint response, sent when nothing changed to keep the connection alive.
+type HeartbeatView struct {
+	// Token the unchanged watch token.
+	Token string
+}
+
+// Heartbeat returns view of meta-only document keeping the watch token.
+func Heartbeat(token string) HeartbeatView {
+	return HeartbeatView{Token: token}
+}
+
+// GetMeta returns "watch" meta member.
+func (v HeartbeatView) GetMeta() interface{} {
+	return map[string]interface{}{WatchMeta: WatchState{Token: v.Token, Heartbeat: true}}
+}
+
+// WatchToken returns watch token the client passed with the request, empty when it watches from scratch.
+func WatchToken(r *http.Request) string {
+	return r.URL.Query().Get(WatchTokenParam)
+}
+
+// AwaitWatch waits for the changes since token of long-poll request, it returns WatchView received from changes
+// or HeartbeatView keeping the token when nothing is received within heartbeat. It returns ctx error when ctx is done first,
+// e.g. the client disconnected.
+//
+// AwaitWatch example:
+//
+//	view, err := jsonapi.AwaitWatch(r.Context(), token, 30*time.Second, store.Subscribe(token))
+//	if err != nil {
+//	  return
+//	}
+//
+//	payload, err := jsonapi.Marshal(view)
+func AwaitWatch(ctx context.Context, token string, heartbeat time.Duration, changes <-chan WatchView) (interface{}, error) {
+	timer := time.NewTimer(heartbeat)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case view, ok := <-changes:
+		if !ok {
+			return Heartbeat(token), nil
+		}
+
+		return view, nil
+	case <-timer.C:
+		return Heartbeat(token), nil
+	}
+}
+
+// WatchState method returns "watch" meta member of watch endpoint response, false when the document has no such member.
+func (d *Document) WatchState() (WatchState, bool) {
+	var state WatchState
+
+	raw, ok := lookupValue(d.Meta, []string{WatchMeta})
+	if !ok || json.Unmarshal(raw, &state) != nil {
+		return state, false
+	}
+
+	return state, true
+}
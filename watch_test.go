@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+var _ = Describe("Watch", func() {
+
+	It("reads the client watch token", func() {
+		r := httptest.NewRequest("GET", "/books?watchToken=41", nil)
+
+		Ω(WatchToken(r)).Should(Equal("41"))
+	})
+
+	It("marshals changed resources with deleted ones and the new token", func() {
+		changed := []Book{{ID: "1", Type: "books", Title: "Dune", Year: "1965"}}
+		deleted := []ResourceObjectIdentifier{{Type: "books", ID: "2"}}
+
+		result, err := Marshal(Watch(changed, deleted, "42"))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{
+			"data": [{"type": "books", "id": "1", "attributes": {"title": "Dune", "year": "1965"}}],
+			"meta": {"watch": {"token": "42", "deleted": [{"type": "books", "id": "2"}]}}
+		}`))
+
+		doc := Document{}
+		Ω(json.Unmarshal(result, &doc)).Should(Succeed())
+
+		state, ok := doc.WatchState()
+		Ω(ok).Should(BeTrue())
+		Ω(state.Token).Should(Equal("42"))
+		Ω(state.Deleted).Should(Equal(deleted))
+		Ω(state.Heartbeat).Should(BeFalse())
+	})
+
+	It("marshals empty collection when nothing changed", func() {
+		result, err := Marshal(Watch(nil, nil, "42"))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"data": [], "meta": {"watch": {"token": "42"}}}`))
+	})
+
+	It("marshals meta-only heartbeat", func() {
+		result, err := Marshal(Heartbeat("42"))
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result).Should(MatchJSON(`{"meta": {"watch": {"token": "42", "heartbeat": true}}}`))
+	})
+
+	Describe("AwaitWatch", func() {
+
+		It("returns the received changes", func() {
+			changes := make(chan WatchView, 1)
+			changes <- Watch([]Book{}, nil, "43")
+
+			view, err := AwaitWatch(context.Background(), "42", time.Second, changes)
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(view).Should(Equal(Watch([]Book{}, nil, "43")))
+		})
+
+		It("returns heartbeat when nothing changed in time", func() {
+			view, err := AwaitWatch(context.Background(), "42", time.Millisecond, make(chan WatchView))
+
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(view).Should(Equal(Heartbeat("42")))
+		})
+
+		It("returns context error", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := AwaitWatch(ctx, "42", time.Second, make(chan WatchView))
+
+			Ω(err).Should(MatchError(context.Canceled))
+		})
+	})
+})
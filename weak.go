@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi
+
+// MarshalExistence interface may be implemented by relationship values to tell whether the referenced resource exists,
+// linkage of a resource which doesn't exist is marshaled with "meta": {"exists": false}
+// and unmarshaled into ResourceObjectIdentifier with Missing set.
+type MarshalExistence interface {
+	Exists() bool
+}
+
+// WeakReference is a relationship value referring to a resource the server knows ID of,
+// but can't tell it still exists, e.g. in eventually-consistent systems.
+//
+// WeakReference example:
+//
+//	func(s SomeStruct) GetRelationships() map[string]interface{} {
+//	  return map[string]interface{}{
+//	    "author": jsonapi.WeakReference{
+//	      Type: "authors",
+//	      ID:   s.AuthorID,
+//	      Resolve: func(typ, id string) bool {
+//	        return authors.Exists(id)
+//	      },
+//	    },
+//	  }
+//	}
+type WeakReference struct {
+	Type string
+	ID   string
+	// Resolve reports whether the referenced resource exists, the resource is assumed to exist when it is nil.
+	Resolve func(typ, id string) bool
+}
+
+// GetID returns the referenced resource ID.
+func (wr WeakReference) GetID() string {
+	return wr.ID
+}
+
+// GetType returns the referenced resource type.
+func (wr WeakReference) GetType() string {
+	return wr.Type
+}
+
+// Exists resolves the referenced resource.
+func (wr WeakReference) Exists() bool {
+	if wr.Resolve == nil {
+		return true
+	}
+
+	return wr.Resolve(wr.Type, wr.ID)
+}
+
+func isMissing(payload interface{}) bool {
+	me, ok := payload.(MarshalExistence)
+
+	return ok && !me.Exists()
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Pieoneers Software Incorporated. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pieoneers/jsonapi-go"
+)
+
+type BookWithWeakAuthor struct {
+	Book
+	AuthorID      string          `json:"-"`
+	AuthorMissing bool            `json:"-"`
+	Existing      map[string]bool `json:"-"`
+}
+
+func (b BookWithWeakAuthor) GetRelationships() map[string]interface{} {
+	resolve := func(typ, id string) bool {
+		return b.Existing[id]
+	}
+
+	return map[string]interface{}{
+		"author": WeakReference{Type: "authors", ID: b.AuthorID, Resolve: resolve},
+		"editors": []WeakReference{
+			{Type: "authors", ID: "1", Resolve: resolve},
+			{Type: "authors", ID: "2", Resolve: resolve},
+		},
+	}
+}
+
+func (b *BookWithWeakAuthor) SetRelationships(relationships map[string]interface{}) error {
+	if author, ok := relationships["author"].(*ResourceObjectIdentifier); ok {
+		b.AuthorID = author.ID
+		b.AuthorMissing = author.Missing
+	}
+
+	return nil
+}
+
+type BookWithWeakAuthorView struct {
+	Book BookWithWeakAuthor
+}
+
+func (v BookWithWeakAuthorView) GetData() interface{} {
+	return v.Book
+}
+
+func (v *BookWithWeakAuthorView) SetData(to func(target interface{}) error) error {
+	return to(&v.Book)
+}
+
+var _ = Describe("Weak references", func() {
+
+	view := BookWithWeakAuthorView{
+		Book: BookWithWeakAuthor{
+			Book:     Book{ID: "1", Title: "Go in Action", Year: "2015", Type: "books"},
+			AuthorID: "2",
+			Existing: map[string]bool{"1": true},
+		},
+	}
+
+	It("marshals missing references with exists meta", func() {
+		result, err := Marshal(view)
+
+		expected := `
+      {
+        "data": {
+          "type": "books",
+          "id": "1",
+          "attributes": {
+            "title": "Go in Action",
+            "year": "2015"
+          },
+          "relationships": {
+            "author": {
+              "data": { "type": "authors", "id": "2", "meta": { "exists": false } }
+            },
+            "editors": {
+              "data": [
+                { "type": "authors", "id": "1" },
+                { "type": "authors", "id": "2", "meta": { "exists": false } }
+              ]
+            }
+          }
+        }
+      }
+    `
+
+		Ω(result).Should(MatchJSON(expected))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("surfaces missing references to SetRelationships", func() {
+		data, err := Marshal(view)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		decoded := BookWithWeakAuthorView{}
+
+		_, err = Unmarshal(data, &decoded)
+
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(decoded.Book.AuthorID).Should(Equal("2"))
+		Ω(decoded.Book.AuthorMissing).Should(BeTrue())
+	})
+})